@@ -2,27 +2,33 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
-	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
 	_ "github.com/solumD/go-blog-api/docs"
 	"github.com/solumD/go-blog-api/internal/config"
-	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/like"
-	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/posts"
-	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/remove"
-	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/save"
-	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/unlike"
-	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/update"
-	"github.com/solumD/go-blog-api/internal/http-server/handlers/user/login"
-	"github.com/solumD/go-blog-api/internal/http-server/handlers/user/register"
-	mwAuth "github.com/solumD/go-blog-api/internal/http-server/middleware/auth"
-	mwLogger "github.com/solumD/go-blog-api/internal/http-server/middleware/logger"
+	mwIdempotency "github.com/solumD/go-blog-api/internal/http-server/middleware/idempotency"
+	mwRatelimit "github.com/solumD/go-blog-api/internal/http-server/middleware/ratelimit"
+	"github.com/solumD/go-blog-api/internal/http-server/router"
+	"github.com/solumD/go-blog-api/internal/lib/broker"
+	"github.com/solumD/go-blog-api/internal/lib/health"
+	"github.com/solumD/go-blog-api/internal/lib/idempotency"
 	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/lib/markdown"
+	"github.com/solumD/go-blog-api/internal/lib/media"
+	"github.com/solumD/go-blog-api/internal/lib/metrics"
+	"github.com/solumD/go-blog-api/internal/lib/notifier"
+	"github.com/solumD/go-blog-api/internal/lib/policy"
+	"github.com/solumD/go-blog-api/internal/lib/ratelimit"
+	"github.com/solumD/go-blog-api/internal/lib/stream"
+	"github.com/solumD/go-blog-api/internal/lib/tracing"
+	"github.com/solumD/go-blog-api/internal/storage/blob"
 	sqlite "github.com/solumD/go-blog-api/internal/storage/sqlite"
-	httpSwagger "github.com/swaggo/http-swagger"
 )
 
 const (
@@ -64,51 +70,205 @@ func main() {
 
 	log.Info("connected to storage")
 
+	// метрики хранилища и бизнес-событий собираются в одном реестре
+	m := metrics.New()
+	storage.SetQueryObserver(m)
+	m.RegisterDBStats(storage.Stats)
+
+	// спаны запросов и вызовов методов хранилища
+	tracerProvider, err := tracing.New(context.Background(), cfg.Tracing)
+	if err != nil {
+		log.Error("failed to init tracing", sl.Err(err))
+		os.Exit(1)
+	}
+	storage.SetTracerProvider(tracerProvider)
+
+	// список модераторов задается в конфиге
+	if err = storage.SetModerators(context.TODO(), cfg.Moderators); err != nil {
+		log.Error("failed to set moderators", sl.Err(err))
+		os.Exit(1)
+	}
+
+	// инициализируем рендерер текста постов
+	renderer := markdown.New(cfg.RenderCacheSize)
+
+	// инициализируем хранилище загруженных файлов
+	blobs, err := blob.NewLocal(cfg.Media.Path)
+	if err != nil {
+		log.Error("failed to init media storage", sl.Err(err))
+		os.Exit(1)
+	}
+
+	// фоновые задачи останавливаются после сервера, чтобы закончить работу, начатую запросами
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	workers := health.NewWorkers()
+
+	// удаляем загрузки, которые так и не были прикреплены к постам
+	collector := media.NewCollector(log, storage, blobs, cfg.Media.OrphanTTL, cfg.Media.GCInterval)
+	workers.Go(workersCtx, "media_collector", collector.Run)
+
+	// правила проверки постов перечитываются из файла без перезапуска
+	contentPolicy := policy.New()
+	if cfg.Policy.Path != "" {
+		watcher := policy.NewWatcher(log, cfg.Policy.Path, storage, contentPolicy, cfg.Policy.ReloadInterval)
+		if _, err := watcher.Reload(); err != nil {
+			log.Error("failed to load content policy", sl.Err(err))
+			os.Exit(1)
+		}
+		workers.Go(workersCtx, "policy_watcher", watcher.Run)
+	}
+
+	// потоки событий закрываются при остановке сервера, иначе он ждал бы их до конца таймаута
+	streams, closeStreams := context.WithCancel(context.Background())
+
+	// события в реальном времени для GET /stream
+	hub := stream.NewHub(log, cfg.Stream.ReplaySize, cfg.Stream.BufferSize)
+
+	// уведомления доставляются в фоне, чтобы не замедлять ответы на запросы
+	events := notifier.New(log, storage, hub, cfg.NotificationQueueSize)
+	workers.Go(workersCtx, "notifier", events.Run)
+
+	// события в топиках для WebSocket-соединений
+	eventBroker := broker.New(log, cfg.WebSocket.BufferSize, cfg.WebSocket.MaxSubscriptions)
+
+	// проверки готовности для GET /readyz, сюда же добавляются проверки новых подсистем
+	checks := health.New()
+	checks.Register("storage", health.CheckerFunc(storage.Ping))
+	checks.Register("migrations", health.CheckerFunc(storage.CheckMigrations))
+	checks.Register("workers", workers)
+
+	// ключи идемпотентности по умолчанию хранятся в БД, чтобы их видели все экземпляры сервиса
+	var idempotencyStore interface {
+		mwIdempotency.Store
+		idempotency.ExpiredRemover
+	}
+	switch cfg.Idempotency.Store {
+	case "sqlite":
+		idempotencyStore = storage
+	case "memory":
+		idempotencyStore = idempotency.NewMemory()
+	default:
+		log.Error("unknown idempotency store", slog.String("store", cfg.Idempotency.Store))
+		os.Exit(1)
+	}
+
+	cleaner := idempotency.NewCleaner(log, idempotencyStore, cfg.Idempotency.CleanupInterval)
+	workers.Go(workersCtx, "idempotency_cleaner", cleaner.Run)
+
+	// ограничения частоты запросов задаются в конфиге для каждого маршрута
+	limiter := mwRatelimit.New(log, ratelimit.NewMemory(), cfg.RateLimits)
+
 	// инициализируем роутер
-	router := chi.NewRouter()
-
-	// инициализируем middleware логгера
-	router.Use(mwLogger.New(log))
-
-	// иниализируем вспомогательные middleware
-	router.Use(middleware.RequestID)
-	router.Use(middleware.Recoverer)
-	router.Use(middleware.URLFormat)
-
-	// обработчики, связанные с постами
-	router.Route("/post", func(r chi.Router) {
-		r.Use(mwAuth.New(cfg.TokenSecret, log))
-		r.Post("/create", save.New(context.Background(), log, storage))
-		r.Delete("/delete", remove.New(context.Background(), log, storage))
-		r.Patch("/update", update.New(context.Background(), log, storage))
-		r.Put("/like", like.New(context.Background(), log, storage))
-		r.Put("/unlike", unlike.New(context.Background(), log, storage))
+	handler := router.New(cfg, log, router.Deps{
+		Storage:        storage,
+		Blobs:          blobs,
+		Renderer:       renderer,
+		Policy:         contentPolicy,
+		Metrics:        m,
+		TracerProvider: tracerProvider,
+		Limiter:        limiter,
+		Notifier:       events,
+		Hub:            hub,
+		Broker:         eventBroker,
+		Checks:         checks,
+		Idempotency:    idempotencyStore,
+		Streams:        streams,
 	})
 
-	// обработчики, связанные с пользователями
-	router.Get("/user/{login}", posts.New(context.Background(), log, storage))
-	router.Post("/auth/register", register.New(context.Background(), log, storage))
-	router.Post("/auth/login", login.New(context.Background(), cfg.TokenSecret, log, storage))
-
-	router.Get("/swagger/*", httpSwagger.Handler(
-		httpSwagger.URL("http://localhost:8081/swagger/doc.json"),
-	))
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	log.Info("starting server", slog.String("address", cfg.Address))
 
 	srv := &http.Server{
 		Addr:         cfg.Address,
-		Handler:      router,
+		Handler:      handler,
 		ReadTimeout:  cfg.HTTPServer.Timeout,
 		WriteTimeout: cfg.HTTPServer.Timeout,
 		IdleTimeout:  cfg.HTTPServer.IdleTimeout,
 	}
 
-	if err := srv.ListenAndServe(); err != nil {
-		log.Error("failed to start server")
+	srv.RegisterOnShutdown(closeStreams)
+
+	serverErr := make(chan error, 2)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	// метрики отдаются на отдельном адресе, а не вместе с API
+	var metricsSrv *http.Server
+	if cfg.Metrics.Address != "" {
+		log.Info("starting metrics server", slog.String("address", cfg.Metrics.Address))
+
+		mux := http.NewServeMux()
+		mux.Handle(cfg.Metrics.Path, m.Handler())
+
+		metricsSrv = &http.Server{
+			Addr:         cfg.Metrics.Address,
+			Handler:      mux,
+			ReadTimeout:  cfg.HTTPServer.Timeout,
+			WriteTimeout: cfg.HTTPServer.Timeout,
+			IdleTimeout:  cfg.HTTPServer.IdleTimeout,
+		}
+
+		go func() {
+			if err := metricsSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serverErr <- err
+			}
+		}()
+	}
+
+	failed := false
+
+	select {
+	case <-ctx.Done():
+		log.Info("stopping server")
+
+		// балансировщик успевает увидеть, что сервис не готов, и перестает присылать запросы
+		checks.Stop()
+		time.Sleep(cfg.HTTPServer.ShutdownDelay)
+	case err := <-serverErr:
+		log.Error("failed to start server", sl.Err(err))
+		failed = true
+	}
+
+	// сервер перестает принимать соединения и ждет завершения текущих запросов
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTPServer.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Error("failed to stop server gracefully", sl.Err(err))
+	}
+
+	if metricsSrv != nil {
+		if err := metricsSrv.Shutdown(shutdownCtx); err != nil {
+			log.Error("failed to stop metrics server", sl.Err(err))
+		}
+	}
+
+	// уведомления, которые остались в очереди, доставляются до закрытия хранилища
+	stopWorkers()
+	workers.Wait()
+
+	if err := storage.Close(); err != nil {
+		log.Error("failed to close storage", sl.Err(err))
 	}
 
-	log.Error("server stopped")
+	// оставшиеся спаны отправляются после остановки всего, что их создает
+	tracingCtx, cancelTracing := context.WithTimeout(context.Background(), cfg.HTTPServer.ShutdownTimeout)
+	defer cancelTracing()
+
+	if err := tracerProvider.Shutdown(tracingCtx); err != nil {
+		log.Error("failed to stop tracing", sl.Err(err))
+	}
+
+	log.Info("server stopped")
+
+	if failed {
+		os.Exit(1)
+	}
 }
 
 func InitLogger(env string) *slog.Logger {
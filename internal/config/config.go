@@ -3,22 +3,110 @@ package config
 import (
 	"log"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/solumD/go-blog-api/internal/lib/ratelimit"
+	"github.com/solumD/go-blog-api/internal/lib/tracing"
+	"github.com/solumD/go-blog-api/internal/types"
 )
 
 type Config struct {
-	Env         string `yaml:"env" env-default:"local" env-required:"true"`
-	TokenSecret string `yaml:"token_secret" env-required:"true"`
-	StoragePath string `yaml:"storage_path" env-required:"true"`
-	HTTPServer  `yaml:"http_server"`
+	Env                   string   `yaml:"env" env-default:"local" env-required:"true"`
+	TokenSecret           string   `yaml:"token_secret" env-required:"true"`
+	StoragePath           string   `yaml:"storage_path" env-required:"true"`
+	RenderCacheSize       int      `yaml:"render_cache_size" env-default:"1000"`
+	Reactions             []string `yaml:"reactions" env-default:"like,love,laugh,wow,sad,angry"`
+	NotificationQueueSize int      `yaml:"notification_queue_size" env-default:"1000"`
+	Moderators            []string `yaml:"moderators"`
+	HTTPServer            `yaml:"http_server"`
+	Media                 Media          `yaml:"media"`
+	Stream                Stream         `yaml:"stream"`
+	WebSocket             WebSocket      `yaml:"websocket"`
+	Policy                Policy         `yaml:"policy"`
+	Metrics               Metrics        `yaml:"metrics"`
+	Tracing               tracing.Config `yaml:"tracing"`
+	LegacyRoutes          LegacyRoutes   `yaml:"legacy_routes"`
+	Idempotency           Idempotency    `yaml:"idempotency"`
+	PostBatch             PostBatch      `yaml:"post_batch"`
+	GraphQL               GraphQL        `yaml:"graphql"`
+	// ограничения частоты запросов по названиям маршрутов
+	RateLimits map[string]ratelimit.Limit `yaml:"rate_limits"`
 }
 
 type HTTPServer struct {
 	Address     string        `yaml:"address" env-default:"localhost:8081"`
 	Timeout     time.Duration `yaml:"timeout" env-default:"5s"`
 	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"60s"`
+	// сколько ждать завершения запросов при остановке сервера
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env-default:"10s"`
+	// сколько сервер отвечает, что не готов, прежде чем перестать принимать соединения
+	ShutdownDelay time.Duration `yaml:"shutdown_delay" env-default:"0s"`
+}
+
+type Media struct {
+	Path          string        `yaml:"path" env-default:"./storage/media"`
+	MaxSize       int64         `yaml:"max_size" env-default:"5242880"`
+	ThumbnailSize int           `yaml:"thumbnail_size" env-default:"256"`
+	OrphanTTL     time.Duration `yaml:"orphan_ttl" env-default:"24h"`
+	GCInterval    time.Duration `yaml:"gc_interval" env-default:"1h"`
+}
+
+type Stream struct {
+	Heartbeat  time.Duration `yaml:"heartbeat" env-default:"15s"`
+	ReplaySize int           `yaml:"replay_size" env-default:"1000"`
+	BufferSize int           `yaml:"buffer_size" env-default:"64"`
+}
+
+type WebSocket struct {
+	PongWait         time.Duration `yaml:"pong_wait" env-default:"60s"`
+	BufferSize       int           `yaml:"buffer_size" env-default:"64"`
+	MaxSubscriptions int           `yaml:"max_subscriptions" env-default:"50"`
+}
+
+type Policy struct {
+	Path           string        `yaml:"path"`
+	ReloadInterval time.Duration `yaml:"reload_interval" env-default:"30s"`
+}
+
+// Metrics отдаются на отдельном адресе, чтобы не быть доступными снаружи вместе с API.
+type Metrics struct {
+	Address string `yaml:"address"`
+	Path    string `yaml:"path" env-default:"/metrics"`
+}
+
+// LegacyRoutes - маршруты без префикса /v1, которые остаются для совместимости
+// и отдают хэдеры Deprecation и Sunset.
+type LegacyRoutes struct {
+	DeprecatedAt time.Time `yaml:"deprecated_at" env-layout:"2006-01-02" env-default:"2026-10-19"`
+	Sunset       time.Time `yaml:"sunset" env-layout:"2006-01-02" env-default:"2027-04-19"`
+}
+
+// Idempotency - ключи идемпотентности POST, PATCH и DELETE запросов.
+type Idempotency struct {
+	// sqlite или memory
+	Store           string        `yaml:"store" env-default:"sqlite"`
+	TTL             time.Duration `yaml:"ttl" env-default:"24h"`
+	CleanupInterval time.Duration `yaml:"cleanup_interval" env-default:"1h"`
+	// сколько ключ занят выполняющимся запросом: если сервис остановился,
+	// не сохранив ответ, запрос с ключом можно повторить после этого
+	Lease time.Duration `yaml:"lease" env-default:"1m"`
+	// тело запроса с ключом читается целиком, поэтому его размер ограничен;
+	// он должен быть больше media.max_size, иначе загрузку нельзя сделать с ключом
+	MaxBodySize int64 `yaml:"max_body_size" env-default:"6291456"`
+}
+
+// PostBatch - пакетные операции с постами в /v1/posts:batch.
+type PostBatch struct {
+	MaxOperations int `yaml:"max_operations" env-default:"100"`
+}
+
+// GraphQL - ограничения запросов к /graphql. Сложность - число полей запроса,
+// в котором поля страницы считаются столько раз, сколько элементов на странице.
+type GraphQL struct {
+	MaxDepth      int `yaml:"max_depth" env-default:"10"`
+	MaxComplexity int `yaml:"max_complexity" env-default:"1000"`
 }
 
 // MustLoad считывает конфиг-файл в объект типа Config и возвращает указатель на него
@@ -35,5 +123,10 @@ func MustLoad() *Config {
 		log.Fatalf("cannot read config: %s", err)
 	}
 
+	// на like работают эндпоинты /v1/posts/{id}/like, /post/like и /post/unlike
+	if !slices.Contains(cfg.Reactions, types.ReactionLike) {
+		log.Fatalf("reactions must contain %q", types.ReactionLike)
+	}
+
 	return &cfg
 }
@@ -0,0 +1,183 @@
+package create
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/storage"
+)
+
+// maxMembers - сколько участников, кроме создателя, может быть в переписке
+const maxMembers = 9
+
+type Request struct {
+	Members []string `json:"members"`
+}
+
+type Response struct {
+	resp.Response
+	ID int64 `json:"id,omitempty"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=ConversationCreator
+type ConversationCreator interface {
+	IsUserExist(ctx context.Context, login string) (bool, error)
+	IsBlocked(ctx context.Context, first string, second string) (bool, error)
+	FindDirectConversation(ctx context.Context, first string, second string) (int64, error)
+	CreateConversation(ctx context.Context, created_by string, members []string, date_created string) (int64, error)
+}
+
+// @Summary     Create conversation
+// @Security    ApiKeyAuth
+// @Tags        conversations
+// @Description start a conversation with one user or a small group (up to 9 other members);
+// @Description if a conversation with the same single user already exists, its id is returned;
+// @Description users who blocked you or were blocked by you can't be members
+// @ID          create-conversation
+// @Accept      json
+// @Produde     json
+// @Param       input       body     Request true "logins of other members"
+// @Success     200         {object} models.CreateConversationSuccess
+// @Failure     400,403,404,500 {object} models.Problem
+// @Router      /v1/conversations [post]
+func New(log *slog.Logger, conversationCreator ConversationCreator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.conversations.create.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidJSON, "failed to decode request")
+
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		login := r.Header.Get("login")
+
+		// создатель всегда участвует в переписке, поэтому в списке он не нужен
+		members := make([]string, 0, len(req.Members))
+		for _, member := range req.Members {
+			member = strings.TrimSpace(member)
+			if member == "" || member == login || slices.Contains(members, member) {
+				continue
+			}
+			members = append(members, member)
+		}
+
+		if len(members) == 0 {
+			log.Error("invalid request", sl.Err(errors.New("no members")))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, "conversation must have at least one other member")
+
+			return
+		}
+
+		if len(members) > maxMembers {
+			log.Error("invalid request", sl.Err(fmt.Errorf("too many members: %d", len(members))))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, fmt.Sprintf("conversation can't have more than %d other members", maxMembers))
+
+			return
+		}
+
+		for _, member := range members {
+			exist, err := conversationCreator.IsUserExist(ctx, member)
+			if err != nil {
+				log.Error("failed to check if user exists", sl.Err(err))
+
+				resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to create conversation")
+
+				return
+			}
+
+			if !exist {
+				log.Error("invalid request", sl.Err(fmt.Errorf("user doesn't exist: %s", member)))
+
+				resp.Fail(w, r, storage.ErrNotFound, resp.CodeUserNotFound, fmt.Sprintf("user %s doesn't exist", member))
+
+				return
+			}
+
+			blocked, err := conversationCreator.IsBlocked(ctx, member, login)
+			if err != nil {
+				log.Error("failed to check if user is blocked", sl.Err(err))
+
+				resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to create conversation")
+
+				return
+			}
+
+			if blocked {
+				log.Error("invalid request", sl.Err(fmt.Errorf("block between %s and %s", login, member)))
+
+				resp.Fail(w, r, storage.ErrForbidden, resp.CodeBlocked, fmt.Sprintf("you can't message %s", member))
+
+				return
+			}
+		}
+
+		// у двух пользователей одна личная переписка
+		if len(members) == 1 {
+			id, err := conversationCreator.FindDirectConversation(ctx, login, members[0])
+			if err == nil {
+				log.Info("conversation already exists", slog.Int64("id", id))
+
+				render.JSON(w, r, Response{
+					Response: resp.OK(),
+					ID:       id,
+				})
+
+				return
+			} else if !errors.Is(err, storage.ErrConversationNotFound) {
+				log.Error("failed to find conversation", sl.Err(err))
+
+				resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to create conversation")
+
+				return
+			}
+		}
+
+		date_created := time.Now().Format("2006-01-02 15:04:05")
+
+		id, err := conversationCreator.CreateConversation(ctx, login, members, date_created)
+		if err != nil {
+			log.Error("failed to create conversation", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to create conversation")
+
+			return
+		}
+
+		log.Info("conversation created", slog.Int64("id", id))
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			ID:       id,
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package create_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/conversations/create"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/conversations/create/mocks"
+	"github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateConversationHandler(t *testing.T) {
+	testCases := []struct {
+		name       string
+		body       string
+		members    []string
+		missing    string
+		blocked    string
+		existingID int64
+		id         int64
+		respError  string
+		mockError  error
+		statusCode int
+	}{
+		{
+			name:       "New direct conversation",
+			body:       `{"members": ["other_user"]}`,
+			members:    []string{"other_user"},
+			id:         1,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Existing direct conversation",
+			body:       `{"members": ["other_user"]}`,
+			members:    []string{"other_user"},
+			existingID: 7,
+			id:         7,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Group without duplicates and creator",
+			body:       `{"members": [" other_user ", "test_user", "third_user", "other_user"]}`,
+			members:    []string{"other_user", "third_user"},
+			id:         1,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Only creator",
+			body:       `{"members": ["test_user"]}`,
+			respError:  "conversation must have at least one other member",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Too many members",
+			body:       `{"members": ["a1", "a2", "a3", "a4", "a5", "a6", "a7", "a8", "a9", "a10"]}`,
+			respError:  "conversation can't have more than 9 other members",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Missing user",
+			body:       `{"members": ["ghost_user"]}`,
+			members:    []string{"ghost_user"},
+			missing:    "ghost_user",
+			respError:  "user ghost_user doesn't exist",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Blocked member",
+			body:       `{"members": ["other_user", "blocker_user"]}`,
+			members:    []string{"other_user", "blocker_user"},
+			blocked:    "blocker_user",
+			respError:  "you can't message blocker_user",
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "CreateConversation Error",
+			body:       `{"members": ["other_user", "third_user"]}`,
+			members:    []string{"other_user", "third_user"},
+			respError:  "failed to create conversation",
+			mockError:  errors.New("unexpected error"),
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			conversationCreatorMock := mocks.NewConversationCreator(t)
+
+			for _, member := range tc.members {
+				conversationCreatorMock.On("IsUserExist", mock.Anything, member).
+					Return(member != tc.missing, nil).
+					Once()
+
+				if member != tc.missing {
+					conversationCreatorMock.On("IsBlocked", mock.Anything, member, "test_user").
+						Return(member == tc.blocked, nil).
+						Once()
+				}
+			}
+
+			if tc.missing == "" && tc.blocked == "" && len(tc.members) == 1 {
+				var findError error
+				if tc.existingID == 0 {
+					findError = storage.ErrConversationNotFound
+				}
+
+				conversationCreatorMock.On("FindDirectConversation", mock.Anything, "test_user", tc.members[0]).
+					Return(tc.existingID, findError).
+					Once()
+			}
+
+			if tc.missing == "" && tc.blocked == "" && len(tc.members) > 0 && tc.existingID == 0 {
+				conversationCreatorMock.On("CreateConversation", mock.Anything, "test_user", tc.members, mock.AnythingOfType("string")).
+					Return(tc.id, tc.mockError).
+					Once()
+			}
+
+			handler := create.New(loggerdiscard.NewDiscardLogger(), conversationCreatorMock)
+
+			req, err := http.NewRequest(http.MethodPost, "/conversations", strings.NewReader(tc.body))
+			require.NoError(t, err)
+
+			req.Header.Add("login", "test_user")
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			require.Equal(t, tc.statusCode, recorder.Code)
+
+			if tc.respError != "" {
+				var problem response.Problem
+
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+
+				require.Equal(t, tc.respError, problem.Detail)
+
+				return
+			}
+
+			var resp create.Response
+
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+
+			require.Equal(t, response.StatusOK, resp.Status)
+			if tc.respError == "" {
+				require.Equal(t, tc.id, resp.ID)
+			}
+		})
+	}
+}
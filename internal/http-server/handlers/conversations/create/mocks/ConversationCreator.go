@@ -0,0 +1,124 @@
+// Code generated by mockery v2.30.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ConversationCreator is an autogenerated mock type for the ConversationCreator type
+type ConversationCreator struct {
+	mock.Mock
+}
+
+// CreateConversation provides a mock function with given fields: ctx, created_by, members, date_created
+func (_m *ConversationCreator) CreateConversation(ctx context.Context, created_by string, members []string, date_created string) (int64, error) {
+	ret := _m.Called(ctx, created_by, members, date_created)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, string) (int64, error)); ok {
+		return rf(ctx, created_by, members, date_created)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, string) int64); ok {
+		r0 = rf(ctx, created_by, members, date_created)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string, string) error); ok {
+		r1 = rf(ctx, created_by, members, date_created)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindDirectConversation provides a mock function with given fields: ctx, first, second
+func (_m *ConversationCreator) FindDirectConversation(ctx context.Context, first string, second string) (int64, error) {
+	ret := _m.Called(ctx, first, second)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (int64, error)); ok {
+		return rf(ctx, first, second)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) int64); ok {
+		r0 = rf(ctx, first, second)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, first, second)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsBlocked provides a mock function with given fields: ctx, first, second
+func (_m *ConversationCreator) IsBlocked(ctx context.Context, first string, second string) (bool, error) {
+	ret := _m.Called(ctx, first, second)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (bool, error)); ok {
+		return rf(ctx, first, second)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) bool); ok {
+		r0 = rf(ctx, first, second)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, first, second)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsUserExist provides a mock function with given fields: ctx, login
+func (_m *ConversationCreator) IsUserExist(ctx context.Context, login string) (bool, error) {
+	ret := _m.Called(ctx, login)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, login)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, login)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, login)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewConversationCreator creates a new instance of ConversationCreator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewConversationCreator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ConversationCreator {
+	mock := &ConversationCreator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,108 @@
+package details
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+type Response struct {
+	resp.Response
+	Conversation *types.Conversation `json:"conversation,omitempty"`
+}
+
+type ConversationGetter interface {
+	IsConversationMember(ctx context.Context, id int64, login string) (bool, error)
+	GetConversation(ctx context.Context, id int64, viewer string) (*types.Conversation, error)
+}
+
+// @Summary     Conversation
+// @Security    ApiKeyAuth
+// @Tags        conversations
+// @Description get a conversation with its members; last_read_id of each member shows
+// @Description which messages they have read
+// @ID          conversation
+// @Accept      json
+// @Produde     json
+// @Param       id          path     int true "id of a conversation"
+// @Success     200         {object} models.ConversationSuccess
+// @Failure     400,404,500 {object} models.Problem
+// @Router      /v1/conversations/{id} [get]
+func New(log *slog.Logger, conversationGetter ConversationGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.conversations.details.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidID, "invalid conversation id")
+
+			return
+		}
+
+		login := r.Header.Get("login")
+
+		// чужая переписка для пользователя не существует
+		member, err := conversationGetter.IsConversationMember(ctx, id, login)
+		if err != nil {
+			log.Error("failed to check if user is in conversation", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to get conversation")
+
+			return
+		}
+
+		if !member {
+			log.Error("invalid request", sl.Err(fmt.Errorf("conversation doesn't exist: %d", id)))
+
+			resp.Fail(w, r, storage.ErrNotFound, resp.CodeConversationNotFound, "conversation doesn't exist")
+
+			return
+		}
+
+		conversation, err := conversationGetter.GetConversation(ctx, id, login)
+		if errors.Is(err, storage.ErrConversationNotFound) {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, storage.ErrNotFound, resp.CodeConversationNotFound, "conversation doesn't exist")
+
+			return
+		} else if err != nil {
+			log.Error("failed to get conversation", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to get conversation")
+
+			return
+		}
+
+		log.Info("conversation got", slog.Int64("id", id))
+
+		render.JSON(w, r, Response{
+			Response:     resp.OK(),
+			Conversation: conversation,
+		})
+	}
+}
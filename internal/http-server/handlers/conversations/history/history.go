@@ -0,0 +1,120 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	"github.com/solumD/go-blog-api/internal/lib/api/pagination"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+type Response struct {
+	resp.Response
+	Messages   []types.Message `json:"messages"`
+	NextOffset *int            `json:"next_offset,omitempty"`
+}
+
+type MessagesGetter interface {
+	IsConversationMember(ctx context.Context, id int64, login string) (bool, error)
+	GetMessages(ctx context.Context, id int64, limit int, offset int) ([]types.Message, error)
+}
+
+// @Summary     Messages
+// @Security    ApiKeyAuth
+// @Tags        conversations
+// @Description get messages of a conversation, newest first
+// @ID          messages
+// @Accept      json
+// @Produde     json
+// @Param       id          path     int true  "id of a conversation"
+// @Param       limit       query    int false "page size, 20 by default, 100 at most"
+// @Param       offset      query    int false "offset of the page"
+// @Success     200         {object} models.MessagesSuccess
+// @Failure     400,404,500 {object} models.Problem
+// @Router      /v1/conversations/{id}/messages [get]
+func New(log *slog.Logger, messagesGetter MessagesGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.conversations.history.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidID, "invalid conversation id")
+
+			return
+		}
+
+		page, err := pagination.Parse(r)
+		if err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, err.Error())
+
+			return
+		}
+
+		login := r.Header.Get("login")
+
+		// чужая переписка для пользователя не существует
+		member, err := messagesGetter.IsConversationMember(ctx, id, login)
+		if err != nil {
+			log.Error("failed to check if user is in conversation", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to get messages")
+
+			return
+		}
+
+		if !member {
+			log.Error("invalid request", sl.Err(fmt.Errorf("conversation doesn't exist: %d", id)))
+
+			resp.Fail(w, r, storage.ErrNotFound, resp.CodeConversationNotFound, "conversation doesn't exist")
+
+			return
+		}
+
+		// запрашиваем на одно сообщение больше, чтобы понять, есть ли следующая страница
+		messages, err := messagesGetter.GetMessages(ctx, id, page.Limit+1, page.Offset)
+		if err != nil {
+			log.Error("failed to get messages", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to get messages")
+
+			return
+		}
+
+		next := page.Next(len(messages))
+		if next != nil {
+			messages = messages[:page.Limit]
+		}
+
+		log.Info("messages got", slog.Int64("conversation", id), slog.Int("count", len(messages)))
+
+		render.JSON(w, r, Response{
+			Response:   resp.OK(),
+			Messages:   messages,
+			NextOffset: next,
+		})
+	}
+}
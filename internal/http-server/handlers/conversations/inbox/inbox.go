@@ -0,0 +1,88 @@
+package inbox
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	"github.com/solumD/go-blog-api/internal/lib/api/pagination"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+type Response struct {
+	resp.Response
+	Conversations []types.Conversation `json:"conversations"`
+	NextOffset    *int                 `json:"next_offset,omitempty"`
+}
+
+type ConversationsGetter interface {
+	GetConversations(ctx context.Context, login string, limit int, offset int) ([]types.Conversation, error)
+}
+
+// @Summary     Conversations
+// @Security    ApiKeyAuth
+// @Tags        conversations
+// @Description get user's conversations with the last message and the number of unread messages,
+// @Description conversations with the latest messages first
+// @ID          conversations
+// @Accept      json
+// @Produde     json
+// @Param       limit   query    int false "page size, 20 by default, 100 at most"
+// @Param       offset  query    int false "offset of the page"
+// @Success     200     {object} models.ConversationsSuccess
+// @Failure     400,500 {object} models.Problem
+// @Router      /v1/conversations [get]
+func New(log *slog.Logger, conversationsGetter ConversationsGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.conversations.inbox.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		page, err := pagination.Parse(r)
+		if err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, err.Error())
+
+			return
+		}
+
+		login := r.Header.Get("login")
+
+		// запрашиваем на одну переписку больше, чтобы понять, есть ли следующая страница
+		conversations, err := conversationsGetter.GetConversations(ctx, login, page.Limit+1, page.Offset)
+		if err != nil {
+			log.Error("failed to get conversations", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to get conversations")
+
+			return
+		}
+
+		next := page.Next(len(conversations))
+		if next != nil {
+			conversations = conversations[:page.Limit]
+		}
+
+		log.Info("conversations got", slog.Int("count", len(conversations)))
+
+		render.JSON(w, r, Response{
+			Response:      resp.OK(),
+			Conversations: conversations,
+			NextOffset:    next,
+		})
+	}
+}
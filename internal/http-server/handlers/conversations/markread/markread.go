@@ -0,0 +1,115 @@
+package markread
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/storage"
+)
+
+type Request struct {
+	MessageID int64 `json:"message_id,omitempty"`
+}
+
+type Response struct {
+	resp.Response
+	LastReadID int64 `json:"last_read_id"`
+}
+
+type ConversationReader interface {
+	ReadConversation(ctx context.Context, id int64, login string, messageID int64) (int64, error)
+}
+
+// @Summary     Read conversation
+// @Security    ApiKeyAuth
+// @Tags        conversations
+// @Description mark messages of a conversation as read up to message_id, or all of them without a body;
+// @Description the read mark never moves back
+// @ID          read-conversation
+// @Accept      json
+// @Produde     json
+// @Param       id          path     int     true  "id of a conversation"
+// @Param       input       body     Request false "id of the last read message"
+// @Success     200         {object} models.ReadConversationSuccess
+// @Failure     400,404,500 {object} models.Problem
+// @Router      /v1/conversations/{id}/read [post]
+func New(log *slog.Logger, conversationReader ConversationReader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.conversations.markread.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidID, "invalid conversation id")
+
+			return
+		}
+
+		// тело запроса необязательное: без него прочитанными отмечаются все сообщения
+		var req Request
+
+		err = render.DecodeJSON(r.Body, &req)
+		if err != nil && !errors.Is(err, io.EOF) {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidJSON, "failed to decode request")
+
+			return
+		}
+
+		if req.MessageID < 0 {
+			log.Error("invalid request", sl.Err(fmt.Errorf("invalid message id: %d", req.MessageID)))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidID, "invalid message id")
+
+			return
+		}
+
+		login := r.Header.Get("login")
+
+		// переписка без участника пользователя для него не существует
+		lastReadID, err := conversationReader.ReadConversation(ctx, id, login, req.MessageID)
+		if errors.Is(err, storage.ErrConversationNotFound) {
+			log.Error("invalid request", sl.Err(fmt.Errorf("conversation doesn't exist: %d", id)))
+
+			resp.Fail(w, r, storage.ErrNotFound, resp.CodeConversationNotFound, "conversation doesn't exist")
+
+			return
+		} else if err != nil {
+			log.Error("failed to mark conversation as read", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to mark conversation as read")
+
+			return
+		}
+
+		log.Info("conversation read", slog.Int64("id", id), slog.Int64("last_read_id", lastReadID))
+
+		render.JSON(w, r, Response{
+			Response:   resp.OK(),
+			LastReadID: lastReadID,
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Code generated by mockery v2.30.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MessageSender is an autogenerated mock type for the MessageSender type
+type MessageSender struct {
+	mock.Mock
+}
+
+// HasBlockedMember provides a mock function with given fields: ctx, id, login
+func (_m *MessageSender) HasBlockedMember(ctx context.Context, id int64, login string) (bool, error) {
+	ret := _m.Called(ctx, id, login)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) (bool, error)); ok {
+		return rf(ctx, id, login)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) bool); ok {
+		r0 = rf(ctx, id, login)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, id, login)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsConversationMember provides a mock function with given fields: ctx, id, login
+func (_m *MessageSender) IsConversationMember(ctx context.Context, id int64, login string) (bool, error) {
+	ret := _m.Called(ctx, id, login)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) (bool, error)); ok {
+		return rf(ctx, id, login)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) bool); ok {
+		r0 = rf(ctx, id, login)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, id, login)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveMessage provides a mock function with given fields: ctx, id, sender, text, date_created
+func (_m *MessageSender) SaveMessage(ctx context.Context, id int64, sender string, text string, date_created string) (int64, error) {
+	ret := _m.Called(ctx, id, sender, text, date_created)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string, string) (int64, error)); ok {
+		return rf(ctx, id, sender, text, date_created)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string, string) int64); ok {
+		r0 = rf(ctx, id, sender, text, date_created)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string, string) error); ok {
+		r1 = rf(ctx, id, sender, text, date_created)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMessageSender creates a new instance of MessageSender. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMessageSender(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MessageSender {
+	mock := &MessageSender{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
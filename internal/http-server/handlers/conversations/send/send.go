@@ -0,0 +1,149 @@
+package send
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/lib/validator"
+	"github.com/solumD/go-blog-api/internal/storage"
+)
+
+type Request struct {
+	Text string `json:"text"`
+}
+
+type Response struct {
+	resp.Response
+	ID int64 `json:"id,omitempty"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=MessageSender
+type MessageSender interface {
+	IsConversationMember(ctx context.Context, id int64, login string) (bool, error)
+	HasBlockedMember(ctx context.Context, id int64, login string) (bool, error)
+	SaveMessage(ctx context.Context, id int64, sender string, text string, date_created string) (int64, error)
+}
+
+// @Summary     Send message
+// @Security    ApiKeyAuth
+// @Tags        conversations
+// @Description send a message to a conversation; you can't send messages to a conversation
+// @Description with a user who blocked you or was blocked by you
+// @ID          send-message
+// @Accept      json
+// @Produde     json
+// @Param       id              path     int     true "id of a conversation"
+// @Param       input           body     Request true "text of a message, 2000 characters at most"
+// @Success     200             {object} models.SendMessageSuccess
+// @Failure     400,403,404,500 {object} models.Problem
+// @Router      /v1/conversations/{id}/messages [post]
+func New(log *slog.Logger, messageSender MessageSender) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.conversations.send.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidID, "invalid conversation id")
+
+			return
+		}
+
+		var req Request
+
+		err = render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidJSON, "failed to decode request")
+
+			return
+		}
+
+		req.Text = strings.TrimSpace(req.Text)
+
+		if err := validator.ValidateMessage(req.Text); err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, err.Error())
+
+			return
+		}
+
+		login := r.Header.Get("login")
+
+		// чужая переписка для пользователя не существует
+		member, err := messageSender.IsConversationMember(ctx, id, login)
+		if err != nil {
+			log.Error("failed to check if user is in conversation", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to send message")
+
+			return
+		}
+
+		if !member {
+			log.Error("invalid request", sl.Err(fmt.Errorf("conversation doesn't exist: %d", id)))
+
+			resp.Fail(w, r, storage.ErrNotFound, resp.CodeConversationNotFound, "conversation doesn't exist")
+
+			return
+		}
+
+		blocked, err := messageSender.HasBlockedMember(ctx, id, login)
+		if err != nil {
+			log.Error("failed to check blocks in conversation", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to send message")
+
+			return
+		}
+
+		if blocked {
+			log.Error("invalid request", sl.Err(fmt.Errorf("conversation %d has a blocked member", id)))
+
+			resp.Fail(w, r, storage.ErrForbidden, resp.CodeBlocked, "you can't message this conversation")
+
+			return
+		}
+
+		date_created := time.Now().Format("2006-01-02 15:04:05")
+
+		messageID, err := messageSender.SaveMessage(ctx, id, login, req.Text, date_created)
+		if err != nil {
+			log.Error("failed to save message", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to send message")
+
+			return
+		}
+
+		log.Info("message sent", slog.Int64("conversation", id), slog.Int64("id", messageID))
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			ID:       messageID,
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package send_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/conversations/send"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/conversations/send/mocks"
+	"github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendMessageHandler(t *testing.T) {
+	testCases := []struct {
+		name       string
+		id         string
+		body       string
+		text       string
+		member     bool
+		blocked    bool
+		respError  string
+		mockError  error
+		statusCode int
+	}{
+		{
+			name:       "Success",
+			id:         "1",
+			body:       `{"text": " hello "}`,
+			text:       "hello",
+			member:     true,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid id",
+			id:         "abc",
+			body:       `{"text": "hello"}`,
+			respError:  "invalid conversation id",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Empty text",
+			id:         "1",
+			body:       `{"text": "  "}`,
+			respError:  "message can't be empty",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Too long text",
+			id:         "1",
+			body:       `{"text": "` + strings.Repeat("a", 2001) + `"}`,
+			respError:  "message cannot be longer than 2000 characters",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Not a member",
+			id:         "1",
+			body:       `{"text": "hello"}`,
+			text:       "hello",
+			respError:  "conversation doesn't exist",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Blocked member",
+			id:         "1",
+			body:       `{"text": "hello"}`,
+			text:       "hello",
+			member:     true,
+			blocked:    true,
+			respError:  "you can't message this conversation",
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "SaveMessage Error",
+			id:         "1",
+			body:       `{"text": "hello"}`,
+			text:       "hello",
+			member:     true,
+			respError:  "failed to send message",
+			mockError:  errors.New("unexpected error"),
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			messageSenderMock := mocks.NewMessageSender(t)
+
+			if tc.member || tc.statusCode == http.StatusNotFound {
+				messageSenderMock.On("IsConversationMember", mock.Anything, int64(1), "test_user").
+					Return(tc.member, nil).
+					Once()
+			}
+
+			if tc.member {
+				messageSenderMock.On("HasBlockedMember", mock.Anything, int64(1), "test_user").
+					Return(tc.blocked, nil).
+					Once()
+			}
+
+			if tc.member && !tc.blocked {
+				messageSenderMock.On("SaveMessage", mock.Anything, int64(1), "test_user", tc.text, mock.AnythingOfType("string")).
+					Return(int64(5), tc.mockError).
+					Once()
+			}
+
+			router := chi.NewRouter()
+			router.Post("/conversations/{id}/messages", send.New(loggerdiscard.NewDiscardLogger(), messageSenderMock))
+
+			req, err := http.NewRequest(http.MethodPost, "/conversations/"+tc.id+"/messages", strings.NewReader(tc.body))
+			require.NoError(t, err)
+
+			req.Header.Add("login", "test_user")
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			require.Equal(t, tc.statusCode, recorder.Code)
+
+			if tc.respError != "" {
+				var problem response.Problem
+
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+
+				require.Equal(t, tc.respError, problem.Detail)
+
+				return
+			}
+
+			var resp send.Response
+
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+
+			require.Equal(t, response.StatusOK, resp.Status)
+		})
+	}
+}
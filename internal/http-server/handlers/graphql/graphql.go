@@ -0,0 +1,188 @@
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	gql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	gqllog "github.com/graph-gophers/graphql-go/log"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/like"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/remove"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/save"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/unlike"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/update"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/lib/querycost"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+//go:embed schema.graphql
+var schema string
+
+const (
+	// размер страницы, если first не указан, и самый большой размер страницы
+	defaultPageSize = 10
+	maxPageSize     = 50
+	// сколько резолверов запроса выполняются одновременно: элементы страницы
+	// должны загружаться параллельно, чтобы их запросы в хранилище объединялись
+	maxParallelism = 2 * maxPageSize
+)
+
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// PostReader получает данные сразу для многих пользователей и постов,
+// чтобы поля элементов страницы не ходили в хранилище по одному.
+type PostReader interface {
+	GetProfiles(ctx context.Context, logins []string) (map[string]types.Profile, error)
+	ListPosts(ctx context.Context, created_by string, viewer string, after_date string, after_id int64, limit int) ([]types.Post, error)
+	GetPostsByIDs(ctx context.Context, ids []int64, viewer string) (map[int64]types.Post, error)
+	CountPostsReactions(ctx context.Context, ids []int64) (map[int64]map[string]int, error)
+	ListPostsReactions(ctx context.Context, ids []int64, kind string, after_id int64, limit int) (map[int64][]types.Reaction, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=Storage
+
+// Storage - хранилище запросов и мутаций. Мутации работают через те же интерфейсы,
+// что и REST обработчики постов.
+type Storage interface {
+	PostReader
+	save.PostSaver
+	save.MentionSaver
+	save.PostFlagger
+	update.PostUpdater
+	remove.PostRemover
+	like.PostLiker
+	unlike.PostUnLiker
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=PolicyChecker
+type PolicyChecker interface {
+	save.PolicyChecker
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=Counter
+type Counter interface {
+	save.PostCounter
+	like.LikeCounter
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=Notifier
+type Notifier interface {
+	save.Notifier
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=Publisher
+type Publisher interface {
+	save.Publisher
+}
+
+// @Summary     GraphQL
+// @Tags        graphql
+// @Description execute a GraphQL query or mutation over users, posts and likes;
+// @Description the token is optional for queries and required for mutations;
+// @Description errors of fields are returned with status 200 and have code and status in extensions
+// @ID          graphql
+// @Accept      json
+// @Produde     json
+// @Param       input   body     Request true "query, operation name and variables"
+// @Success     200     {object} models.GraphQLResponse
+// @Failure     400,401,429 {object} models.Problem
+// @Router      /graphql [post]
+func New(maxDepth int, maxComplexity int, log *slog.Logger, storage Storage, policyChecker PolicyChecker, counter Counter, eventNotifier Notifier, eventPublisher Publisher) http.HandlerFunc {
+	root := &resolver{
+		storage:   storage,
+		policy:    policyChecker,
+		counter:   counter,
+		notifier:  eventNotifier,
+		publisher: eventPublisher,
+	}
+
+	s := gql.MustParseSchema(schema, root,
+		gql.MaxDepth(maxDepth),
+		gql.MaxParallelism(maxParallelism),
+		gql.Logger(gqllog.LoggerFunc(func(ctx context.Context, value interface{}) {
+			fromContext(ctx).log.Error("resolver panicked", slog.Any("panic", value))
+		})),
+	)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.graphql.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidJSON, "failed to decode request")
+
+			return
+		}
+
+		if strings.TrimSpace(req.Query) == "" {
+			log.Error("invalid request", sl.Err(errors.New("query is empty")))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, "query can't be empty")
+
+			return
+		}
+
+		log.Info("request body decoded", slog.String("operation", req.OperationName))
+
+		// сложность считается до выполнения: запрос, который сделал бы слишком много
+		// обращений к хранилищу, не выполняется совсем. Синтаксические ошибки
+		// пропускаются, чтобы их описала библиотека
+		complexity, err := querycost.Complexity(req.Query, req.OperationName, req.Variables, defaultPageSize)
+		if err == nil && complexity > maxComplexity {
+			log.Error("query is too complex", slog.Int("complexity", complexity))
+
+			render.JSON(w, r, &gql.Response{Errors: []*gqlerrors.QueryError{{
+				Message: fmt.Sprintf("query has complexity %d that exceeds max complexity %d", complexity, maxComplexity),
+				Extensions: map[string]interface{}{
+					"code":   resp.CodeQueryTooComplex,
+					"status": http.StatusBadRequest,
+				},
+			}}})
+
+			return
+		}
+
+		login := r.Header.Get("login")
+
+		ctx = withRequest(ctx, &request{
+			login:   login,
+			log:     log,
+			reader:  storage,
+			loaders: newLoaders(storage, login),
+		})
+
+		result := s.Exec(ctx, req.Query, req.OperationName, req.Variables)
+
+		log.Info("query executed", slog.Int("complexity", complexity), slog.Int("errors", len(result.Errors)))
+
+		render.JSON(w, r, result)
+	}
+}
@@ -0,0 +1,305 @@
+package graphql_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/graphql"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/graphql/mocks"
+	"github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/broker"
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/solumD/go-blog-api/internal/lib/notifier"
+	"github.com/solumD/go-blog-api/internal/lib/policy"
+	"github.com/solumD/go-blog-api/internal/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type handlerMocks struct {
+	storage       *mocks.Storage
+	policyChecker *mocks.PolicyChecker
+	counter       *mocks.Counter
+	notifier      *mocks.Notifier
+	publisher     *mocks.Publisher
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string `json:"message"`
+		Extensions struct {
+			Code   string `json:"code"`
+			Status int    `json:"status"`
+		} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func post(id int64) types.Post {
+	return types.Post{ID: id, Created_by: "test_user", Title: "Very Cool Title", Text: "Very Cool Text", Format: "plain", Visibility: "public", Likes: 1}
+}
+
+// ids проверяет, что в пачке именно эти id в любом порядке.
+func ids(want ...int64) any {
+	slices.Sort(want)
+
+	return mock.MatchedBy(func(got []int64) bool {
+		got = slices.Clone(got)
+		slices.Sort(got)
+
+		return slices.Equal(want, got)
+	})
+}
+
+func TestGraphQLHandler(t *testing.T) {
+	profile := types.Profile{Login: "test_user", DisplayName: "Test User", Joined_at: "2026-10-19 12:00:00", Followers: 2}
+
+	testCases := []struct {
+		name  string
+		login string
+		input string
+		setup func(m handlerMocks)
+		// ожидаемые данные ответа, если запрос выполнен
+		data string
+		// коды ошибок полей
+		codes      []string
+		statusCode int
+		respError  string
+	}{
+		{
+			name: "User with posts and likers in one batch",
+			input: `{"query": "{ user(login: \"test_user\") { login displayName posts(first: 2) {
+				edges { node { id author { login } reactionCounts { kind count } reactions(first: 1) { edges { node { user { login } } } pageInfo { hasNextPage } } } }
+				pageInfo { hasNextPage }
+			} } }"}`,
+			setup: func(m handlerMocks) {
+				m.storage.On("GetProfiles", mock.Anything, []string{"test_user"}).
+					Return(map[string]types.Profile{"test_user": profile}, nil).Once()
+				m.storage.On("ListPosts", mock.Anything, "test_user", "", "", int64(0), 3).
+					Return([]types.Post{post(3), post(2), post(1)}, nil).Once()
+				// поля обоих постов страницы получаются одним запросом
+				m.storage.On("CountPostsReactions", mock.Anything, ids(3, 2)).
+					Return(map[int64]map[string]int{3: {"like": 2, "wow": 1}}, nil).Once()
+				m.storage.On("ListPostsReactions", mock.Anything, ids(3, 2), "like", int64(0), 2).
+					Return(map[int64][]types.Reaction{3: {
+						{ID: 10, PostID: 3, Login: "other_user", Kind: "like"},
+						{ID: 11, PostID: 3, Login: "third_user", Kind: "like"},
+					}}, nil).Once()
+				m.storage.On("GetProfiles", mock.Anything, []string{"other_user"}).
+					Return(map[string]types.Profile{"other_user": {Login: "other_user"}}, nil).Once()
+			},
+			data: `{"user": {"login": "test_user", "displayName": "Test User", "posts": {
+				"edges": [
+					{"node": {"id": "3", "author": {"login": "test_user"},
+						"reactionCounts": [{"kind": "like", "count": 2}, {"kind": "wow", "count": 1}],
+						"reactions": {"edges": [{"node": {"user": {"login": "other_user"}}}], "pageInfo": {"hasNextPage": true}}}},
+					{"node": {"id": "2", "author": {"login": "test_user"},
+						"reactionCounts": [],
+						"reactions": {"edges": [], "pageInfo": {"hasNextPage": false}}}}
+				],
+				"pageInfo": {"hasNextPage": true}
+			}}}`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "Hidden post is null",
+			input: `{"query": "query Post($id: ID!) { post(id: $id) { title } }", "variables": {"id": "7"}}`,
+			setup: func(m handlerMocks) {
+				m.storage.On("GetPostsByIDs", mock.Anything, []int64{7}, "").Return(map[int64]types.Post{}, nil).Once()
+			},
+			data:       `{"post": null}`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Me without token",
+			input:      `{"query": "{ me { login } }"}`,
+			data:       `{"me": null}`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "Invalid page size",
+			input: `{"query": "{ user(login: \"test_user\") { posts(first: 100) { pageInfo { hasNextPage } } } }"}`,
+			setup: func(m handlerMocks) {
+				m.storage.On("GetProfiles", mock.Anything, mock.Anything).Return(map[string]types.Profile{"test_user": profile}, nil).Once()
+			},
+			codes:      []string{response.CodeValidationFailed},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "Create post",
+			login: "test_user",
+			input: `{"query": "mutation { createPost(input: {title: \" Very Cool Title \", text: \"Very Cool Text\"}) { id title } }"}`,
+			setup: func(m handlerMocks) {
+				m.policyChecker.On("Check", mock.Anything, policy.Content{Author: "test_user", Title: "Very Cool Title", Text: "Very Cool Text"}).
+					Return(policy.Decision{}, nil).Once()
+				m.storage.On("SavePost", mock.Anything, "test_user", "Very Cool Title", "Very Cool Text", "plain", "public", []int64(nil), mock.AnythingOfType("string")).
+					Return(int64(1), nil).Once()
+				m.storage.On("GetPostsByIDs", mock.Anything, []int64{1}, "test_user").Return(map[int64]types.Post{1: post(1)}, nil).Once()
+				m.counter.On("PostCreated").Once()
+				m.notifier.On("Notify", notifier.Event{Type: notifier.EventPost, Actor: "test_user", PostID: 1}).Once()
+				m.publisher.On("Publish", "user:test_user", broker.EventPostCreated, broker.PostData{PostID: 1, Author: "test_user"}).Once()
+			},
+			data:       `{"createPost": {"id": "1", "title": "Very Cool Title"}}`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Mutation without token",
+			input:      `{"query": "mutation { likePost(id: 1) { likes } }"}`,
+			codes:      []string{response.CodeUnauthorized},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "Update post of another user",
+			login: "test_user",
+			input: `{"query": "mutation { updatePost(id: 7, input: {title: \"New Title\"}) { id } }"}`,
+			setup: func(m handlerMocks) {
+				m.storage.On("GetPostCreator", mock.Anything, 7).Return("other_user", nil).Once()
+			},
+			codes:      []string{response.CodeNotPostAuthor},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "Delete post",
+			login: "test_user",
+			input: `{"query": "mutation { deletePost(id: 7) }"}`,
+			setup: func(m handlerMocks) {
+				m.storage.On("GetPostCreator", mock.Anything, 7).Return("test_user", nil).Once()
+				m.storage.On("RemovePost", mock.Anything, 7).Return(nil).Once()
+				m.publisher.On("Publish", mock.Anything, broker.EventPostDeleted, broker.PostData{PostID: 7}).Twice()
+			},
+			data:       `{"deletePost": "7"}`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "Like already liked post",
+			login: "test_user",
+			input: `{"query": "mutation { likePost(id: 7) { likes } }"}`,
+			setup: func(m handlerMocks) {
+				m.storage.On("CanViewPost", mock.Anything, 7, "test_user").Return(true, nil).Once()
+				m.storage.On("IsPostLikedByUser", mock.Anything, 7, "test_user").Return(true, nil).Once()
+			},
+			codes:      []string{response.CodeAlreadyLiked},
+			statusCode: http.StatusOK,
+		},
+		{
+			// как и в likePost, скрытый пост для пользователя не существует
+			name:  "Unlike hidden post",
+			login: "test_user",
+			input: `{"query": "mutation { unlikePost(id: 7) { likes } }"}`,
+			setup: func(m handlerMocks) {
+				m.storage.On("CanViewPost", mock.Anything, 7, "test_user").Return(false, nil).Once()
+			},
+			codes:      []string{response.CodePostNotFound},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "Unlike post",
+			login: "test_user",
+			input: `{"query": "mutation { unlikePost(id: 7) { likes } }"}`,
+			setup: func(m handlerMocks) {
+				m.storage.On("CanViewPost", mock.Anything, 7, "test_user").Return(true, nil).Once()
+				m.storage.On("IsPostLikedByUser", mock.Anything, 7, "test_user").Return(true, nil).Once()
+				m.storage.On("UnlikePost", mock.Anything, 7, "test_user").Return(nil).Once()
+				m.storage.On("GetPostsByIDs", mock.Anything, []int64{7}, "test_user").Return(map[int64]types.Post{7: {ID: 7}}, nil).Once()
+				m.publisher.On("Publish", "post:7", broker.EventUnlike, broker.ReactionData{PostID: 7, Login: "test_user"}).Once()
+			},
+			data:       `{"unlikePost": {"likes": 0}}`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "Storage error",
+			input: `{"query": "{ post(id: 7) { title } }"}`,
+			setup: func(m handlerMocks) {
+				m.storage.On("GetPostsByIDs", mock.Anything, []int64{7}, "").Return(nil, errors.New("unexpected error")).Once()
+			},
+			codes:      []string{response.CodeInternal},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Too deep",
+			input:      `{"query": "{ post(id: 7) { repostOf { repostOf { repostOf { repostOf { repostOf { repostOf { repostOf { repostOf { repostOf { repostOf { id } } } } } } } } } } } }"}`,
+			codes:      []string{""},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Too complex",
+			input:      `{"query": "{ user(login: \"test_user\") { posts(first: 50) { edges { node { reactions(first: 50) { edges { node { kind } } } } } } } }"}`,
+			codes:      []string{response.CodeQueryTooComplex},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Empty query",
+			input:      `{"query": " "}`,
+			statusCode: http.StatusBadRequest,
+			respError:  "query can't be empty",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := handlerMocks{
+				storage:       mocks.NewStorage(t),
+				policyChecker: mocks.NewPolicyChecker(t),
+				counter:       mocks.NewCounter(t),
+				notifier:      mocks.NewNotifier(t),
+				publisher:     mocks.NewPublisher(t),
+			}
+
+			if tc.setup != nil {
+				tc.setup(m)
+			}
+
+			handler := graphql.New(10, 1000, loggerdiscard.NewDiscardLogger(), m.storage, m.policyChecker, m.counter, m.notifier, m.publisher)
+
+			// в запросах для читаемости есть переводы строк, в JSON они не допускаются
+			input := strings.NewReplacer("\n", " ", "\t", " ").Replace(tc.input)
+
+			req, err := http.NewRequest(http.MethodPost, "/graphql", bytes.NewReader([]byte(input)))
+			require.NoError(t, err)
+
+			if tc.login != "" {
+				req.Header.Add("login", tc.login)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			require.Equal(t, tc.statusCode, recorder.Code)
+
+			body := recorder.Body.Bytes()
+
+			if tc.respError != "" {
+				var problem response.Problem
+
+				require.NoError(t, json.Unmarshal(body, &problem))
+
+				require.Equal(t, tc.respError, problem.Detail)
+
+				return
+			}
+
+			var resp graphQLResponse
+
+			require.NoError(t, json.Unmarshal(body, &resp))
+			require.Len(t, resp.Errors, len(tc.codes), string(body))
+
+			for i, code := range tc.codes {
+				require.Equal(t, code, resp.Errors[i].Extensions.Code, resp.Errors[i].Message)
+			}
+
+			if tc.data != "" {
+				require.JSONEq(t, tc.data, string(resp.Data))
+			}
+		})
+	}
+}
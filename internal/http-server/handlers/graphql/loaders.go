@@ -0,0 +1,119 @@
+package graphql
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/graph-gophers/dataloader/v7"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+type ctxKey struct{}
+
+// request - то, что нужно резолверам одного запроса.
+type request struct {
+	// логин авторизованного пользователя, пустой у анонима
+	login   string
+	log     *slog.Logger
+	reader  PostReader
+	loaders *loaders
+}
+
+func withRequest(ctx context.Context, req *request) context.Context {
+	return context.WithValue(ctx, ctxKey{}, req)
+}
+
+func fromContext(ctx context.Context) *request {
+	return ctx.Value(ctxKey{}).(*request)
+}
+
+// loaders собирают обращения резолверов к хранилищу за время одного запроса
+// и выполняют их пачкой: поля всех постов страницы получаются одним запросом,
+// а не запросом на каждый пост. Загруженное кэшируется до конца запроса.
+type loaders struct {
+	users          *dataloader.Loader[string, *types.Profile]
+	posts          *dataloader.Loader[int64, *types.Post]
+	reactionCounts *dataloader.Loader[int64, map[string]int]
+	reactions      *dataloader.Loader[reactionsKey, []types.Reaction]
+}
+
+// reactionsPage - страница реакций одного вида, одинаковая для всех постов пачки.
+type reactionsPage struct {
+	kind    string
+	afterID int64
+	limit   int
+}
+
+type reactionsKey struct {
+	postID int64
+	reactionsPage
+}
+
+func newLoaders(reader PostReader, viewer string) *loaders {
+	return &loaders{
+		users: dataloader.NewBatchedLoader(batch(func(ctx context.Context, logins []string) (map[string]*types.Profile, error) {
+			profiles, err := reader.GetProfiles(ctx, logins)
+
+			return pointers(profiles), err
+		})),
+		posts: dataloader.NewBatchedLoader(batch(func(ctx context.Context, ids []int64) (map[int64]*types.Post, error) {
+			posts, err := reader.GetPostsByIDs(ctx, ids, viewer)
+
+			return pointers(posts), err
+		})),
+		reactionCounts: dataloader.NewBatchedLoader(batch(reader.CountPostsReactions)),
+		reactions: dataloader.NewBatchedLoader(batch(func(ctx context.Context, keys []reactionsKey) (map[reactionsKey][]types.Reaction, error) {
+			// у разных страниц разные условия, поэтому на каждую страницу свой запрос
+			ids := make(map[reactionsPage][]int64)
+			for _, key := range keys {
+				ids[key.reactionsPage] = append(ids[key.reactionsPage], key.postID)
+			}
+
+			reactions := make(map[reactionsKey][]types.Reaction, len(keys))
+			for page, postIDs := range ids {
+				got, err := reader.ListPostsReactions(ctx, postIDs, page.kind, page.afterID, page.limit)
+				if err != nil {
+					return nil, err
+				}
+
+				for _, id := range postIDs {
+					reactions[reactionsKey{postID: id, reactionsPage: page}] = got[id]
+				}
+			}
+
+			return reactions, nil
+		})),
+	}
+}
+
+// forget убирает из кэша пост id после мутации, чтобы он загрузился заново.
+func (l *loaders) forget(ctx context.Context, id int64) {
+	l.posts.Clear(ctx, id)
+	l.reactionCounts.Clear(ctx, id)
+	l.reactions.ClearAll()
+}
+
+// batch превращает получение значений по списку ключей в функцию пачки загрузчика.
+// Значение ключа, которого нет в ответе, - нулевое.
+func batch[K comparable, V any](get func(ctx context.Context, keys []K) (map[K]V, error)) dataloader.BatchFunc[K, V] {
+	return func(ctx context.Context, keys []K) []*dataloader.Result[V] {
+		values, err := get(ctx, keys)
+
+		results := make([]*dataloader.Result[V], len(keys))
+		for i, key := range keys {
+			results[i] = &dataloader.Result[V]{Data: values[key], Error: err}
+		}
+
+		return results
+	}
+}
+
+func pointers[K comparable, V any](values map[K]V) map[K]*V {
+	result := make(map[K]*V, len(values))
+	for key := range values {
+		value := values[key]
+		result[key] = &value
+	}
+
+	return result
+}
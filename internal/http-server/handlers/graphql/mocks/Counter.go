@@ -0,0 +1,34 @@
+// Code generated by mockery v2.30.0. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// Counter is an autogenerated mock type for the Counter type
+type Counter struct {
+	mock.Mock
+}
+
+// PostCreated provides a mock function with given fields:
+func (_m *Counter) PostCreated() {
+	_m.Called()
+}
+
+// PostLiked provides a mock function with given fields:
+func (_m *Counter) PostLiked() {
+	_m.Called()
+}
+
+// NewCounter creates a new instance of Counter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCounter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Counter {
+	mock := &Counter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
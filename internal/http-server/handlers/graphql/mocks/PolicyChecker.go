@@ -0,0 +1,54 @@
+// Code generated by mockery v2.30.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	policy "github.com/solumD/go-blog-api/internal/lib/policy"
+)
+
+// PolicyChecker is an autogenerated mock type for the PolicyChecker type
+type PolicyChecker struct {
+	mock.Mock
+}
+
+// Check provides a mock function with given fields: ctx, c
+func (_m *PolicyChecker) Check(ctx context.Context, c policy.Content) (policy.Decision, error) {
+	ret := _m.Called(ctx, c)
+
+	var r0 policy.Decision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, policy.Content) (policy.Decision, error)); ok {
+		return rf(ctx, c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, policy.Content) policy.Decision); ok {
+		r0 = rf(ctx, c)
+	} else {
+		r0 = ret.Get(0).(policy.Decision)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, policy.Content) error); ok {
+		r1 = rf(ctx, c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPolicyChecker creates a new instance of PolicyChecker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPolicyChecker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PolicyChecker {
+	mock := &PolicyChecker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
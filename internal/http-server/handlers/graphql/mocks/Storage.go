@@ -0,0 +1,376 @@
+// Code generated by mockery v2.30.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	types "github.com/solumD/go-blog-api/internal/types"
+)
+
+// Storage is an autogenerated mock type for the Storage type
+type Storage struct {
+	mock.Mock
+}
+
+// CanViewPost provides a mock function with given fields: ctx, id, viewer
+func (_m *Storage) CanViewPost(ctx context.Context, id int, viewer string) (bool, error) {
+	ret := _m.Called(ctx, id, viewer)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (bool, error)); ok {
+		return rf(ctx, id, viewer)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) bool); ok {
+		r0 = rf(ctx, id, viewer)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, id, viewer)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountPostsReactions provides a mock function with given fields: ctx, ids
+func (_m *Storage) CountPostsReactions(ctx context.Context, ids []int64) (map[int64]map[string]int, error) {
+	ret := _m.Called(ctx, ids)
+
+	var r0 map[int64]map[string]int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) (map[int64]map[string]int, error)); ok {
+		return rf(ctx, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) map[int64]map[string]int); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int64]map[string]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPostCreator provides a mock function with given fields: ctx, id
+func (_m *Storage) GetPostCreator(ctx context.Context, id int) (string, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (string, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) string); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPostsByIDs provides a mock function with given fields: ctx, ids, viewer
+func (_m *Storage) GetPostsByIDs(ctx context.Context, ids []int64, viewer string) (map[int64]types.Post, error) {
+	ret := _m.Called(ctx, ids, viewer)
+
+	var r0 map[int64]types.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64, string) (map[int64]types.Post, error)); ok {
+		return rf(ctx, ids, viewer)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int64, string) map[int64]types.Post); ok {
+		r0 = rf(ctx, ids, viewer)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int64]types.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int64, string) error); ok {
+		r1 = rf(ctx, ids, viewer)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProfiles provides a mock function with given fields: ctx, logins
+func (_m *Storage) GetProfiles(ctx context.Context, logins []string) (map[string]types.Profile, error) {
+	ret := _m.Called(ctx, logins)
+
+	var r0 map[string]types.Profile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) (map[string]types.Profile, error)); ok {
+		return rf(ctx, logins)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) map[string]types.Profile); ok {
+		r0 = rf(ctx, logins)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]types.Profile)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, logins)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsPostLikedByUser provides a mock function with given fields: ctx, id, liked_by
+func (_m *Storage) IsPostLikedByUser(ctx context.Context, id int, liked_by string) (bool, error) {
+	ret := _m.Called(ctx, id, liked_by)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (bool, error)); ok {
+		return rf(ctx, id, liked_by)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) bool); ok {
+		r0 = rf(ctx, id, liked_by)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, id, liked_by)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsUserExist provides a mock function with given fields: ctx, login
+func (_m *Storage) IsUserExist(ctx context.Context, login string) (bool, error) {
+	ret := _m.Called(ctx, login)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, login)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, login)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, login)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LikePost provides a mock function with given fields: ctx, id, liked_by
+func (_m *Storage) LikePost(ctx context.Context, id int, liked_by string) error {
+	ret := _m.Called(ctx, id, liked_by)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, id, liked_by)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListPosts provides a mock function with given fields: ctx, created_by, viewer, after_date, after_id, limit
+func (_m *Storage) ListPosts(ctx context.Context, created_by string, viewer string, after_date string, after_id int64, limit int) ([]types.Post, error) {
+	ret := _m.Called(ctx, created_by, viewer, after_date, after_id, limit)
+
+	var r0 []types.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, int64, int) ([]types.Post, error)); ok {
+		return rf(ctx, created_by, viewer, after_date, after_id, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, int64, int) []types.Post); ok {
+		r0 = rf(ctx, created_by, viewer, after_date, after_id, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, int64, int) error); ok {
+		r1 = rf(ctx, created_by, viewer, after_date, after_id, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPostsReactions provides a mock function with given fields: ctx, ids, kind, after_id, limit
+func (_m *Storage) ListPostsReactions(ctx context.Context, ids []int64, kind string, after_id int64, limit int) (map[int64][]types.Reaction, error) {
+	ret := _m.Called(ctx, ids, kind, after_id, limit)
+
+	var r0 map[int64][]types.Reaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64, string, int64, int) (map[int64][]types.Reaction, error)); ok {
+		return rf(ctx, ids, kind, after_id, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int64, string, int64, int) map[int64][]types.Reaction); ok {
+		r0 = rf(ctx, ids, kind, after_id, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int64][]types.Reaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int64, string, int64, int) error); ok {
+		r1 = rf(ctx, ids, kind, after_id, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemovePost provides a mock function with given fields: ctx, id
+func (_m *Storage) RemovePost(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReportPost provides a mock function with given fields: ctx, id, reporter, reason, note, date_created
+func (_m *Storage) ReportPost(ctx context.Context, id int, reporter string, reason string, note string, date_created string) error {
+	ret := _m.Called(ctx, id, reporter, reason, note, date_created)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string, string, string) error); ok {
+		r0 = rf(ctx, id, reporter, reason, note, date_created)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SavePost provides a mock function with given fields: ctx, created_by, title, text, format, visibility, media, date_created
+func (_m *Storage) SavePost(ctx context.Context, created_by string, title string, text string, format string, visibility string, media []int64, date_created string) (int64, error) {
+	ret := _m.Called(ctx, created_by, title, text, format, visibility, media, date_created)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, string, []int64, string) (int64, error)); ok {
+		return rf(ctx, created_by, title, text, format, visibility, media, date_created)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, string, []int64, string) int64); ok {
+		r0 = rf(ctx, created_by, title, text, format, visibility, media, date_created)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string, string, []int64, string) error); ok {
+		r1 = rf(ctx, created_by, title, text, format, visibility, media, date_created)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetPostMentions provides a mock function with given fields: ctx, id, logins, date_created
+func (_m *Storage) SetPostMentions(ctx context.Context, id int64, logins []string, date_created string) ([]string, error) {
+	ret := _m.Called(ctx, id, logins, date_created)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []string, string) ([]string, error)); ok {
+		return rf(ctx, id, logins, date_created)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []string, string) []string); ok {
+		r0 = rf(ctx, id, logins, date_created)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, []string, string) error); ok {
+		r1 = rf(ctx, id, logins, date_created)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnlikePost provides a mock function with given fields: ctx, id, liked_by
+func (_m *Storage) UnlikePost(ctx context.Context, id int, liked_by string) error {
+	ret := _m.Called(ctx, id, liked_by)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, id, liked_by)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePost provides a mock function with given fields: ctx, id, title, text, format, visibility, date_updated
+func (_m *Storage) UpdatePost(ctx context.Context, id int, title string, text string, format string, visibility string, date_updated string) error {
+	ret := _m.Called(ctx, id, title, text, format, visibility, date_updated)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string, string, string, string) error); ok {
+		r0 = rf(ctx, id, title, text, format, visibility, date_updated)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewStorage creates a new instance of Storage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStorage(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Storage {
+	mock := &Storage{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
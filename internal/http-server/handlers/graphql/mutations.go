@@ -0,0 +1,246 @@
+package graphql
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	gql "github.com/graph-gophers/graphql-go"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/like"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/remove"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/save"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/update"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/broker"
+	"github.com/solumD/go-blog-api/internal/lib/notifier"
+	"github.com/solumD/go-blog-api/internal/lib/policy"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+// Мутации проверяют запрос и сообщают о событиях теми же функциями, что и REST
+// обработчики /v1/posts, и возвращают те же коды ошибок.
+
+type createPostInput struct {
+	Title      string
+	Text       string
+	Format     *string
+	Visibility *string
+}
+
+type updatePostInput struct {
+	Title      *string
+	Text       *string
+	Format     *string
+	Visibility *string
+}
+
+func (r *resolver) CreatePost(ctx context.Context, args struct{ Input createPostInput }) (*postResolver, error) {
+	req := fromContext(ctx)
+	log := req.log.With(slog.String("mutation", "createPost"))
+
+	if err := req.authorized(); err != nil {
+		return nil, err
+	}
+
+	// формат и видимость по умолчанию задает save.Validate
+	post := save.Request{
+		Title:      args.Input.Title,
+		Text:       args.Input.Text,
+		Format:     valueOf(args.Input.Format),
+		Visibility: valueOf(args.Input.Visibility),
+	}
+
+	if failure := save.Validate(&post); failure != nil {
+		return nil, failed(failure)
+	}
+
+	decision, failure := save.CheckContent(ctx, log, r.policy, policy.Content{Author: req.login, Title: post.Title, Text: post.Text})
+	if failure != nil {
+		return nil, failed(failure)
+	}
+
+	date_created := time.Now().Format("2006-01-02 15:04:05")
+
+	id, err := r.storage.SavePost(ctx, req.login, post.Title, post.Text, post.Format, post.Visibility, nil, date_created)
+	if err != nil {
+		return nil, req.internal("failed to save post", err)
+	}
+
+	log.Info("post created", slog.Int64("id", id))
+
+	r.effects().Created(ctx, log, req.login, id, post, decision, date_created)
+
+	return mutated(ctx, id)
+}
+
+func (r *resolver) UpdatePost(ctx context.Context, args struct {
+	ID    gql.ID
+	Input updatePostInput
+}) (*postResolver, error) {
+	req := fromContext(ctx)
+	log := req.log.With(slog.String("mutation", "updatePost"))
+
+	if err := req.authorized(); err != nil {
+		return nil, err
+	}
+
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	post := update.Request{
+		ID:         int(id),
+		Title:      valueOf(args.Input.Title),
+		Text:       valueOf(args.Input.Text),
+		Format:     valueOf(args.Input.Format),
+		Visibility: valueOf(args.Input.Visibility),
+	}
+
+	if failure := update.Validate(&post); failure != nil {
+		return nil, failed(failure)
+	}
+
+	date_updated := time.Now().Format("2006-01-02 15:04:05")
+
+	decision, failure := update.Apply(ctx, log, r.storage, r.policy, req.login, post, date_updated)
+	if failure != nil {
+		return nil, failed(failure)
+	}
+
+	log.Info("post updated", slog.Int64("id", id))
+
+	update.Updated(ctx, log, r.effects(), req.login, id, post, decision, date_updated)
+
+	return mutated(ctx, id)
+}
+
+func (r *resolver) DeletePost(ctx context.Context, args struct{ ID gql.ID }) (gql.ID, error) {
+	req := fromContext(ctx)
+	log := req.log.With(slog.String("mutation", "deletePost"))
+
+	if err := req.authorized(); err != nil {
+		return "", err
+	}
+
+	id, err := parseID(args.ID)
+	if err != nil {
+		return "", err
+	}
+
+	if failure := update.CheckAuthor(ctx, log, r.storage, id, req.login); failure != nil {
+		return "", failed(failure)
+	}
+
+	if err := r.storage.RemovePost(ctx, int(id)); err != nil {
+		return "", req.internal("failed to remove post", err)
+	}
+
+	log.Info("post removed", slog.Int64("id", id))
+	req.loaders.forget(ctx, id)
+
+	remove.Removed(r.publisher, req.login, id)
+
+	return args.ID, nil
+}
+
+func (r *resolver) LikePost(ctx context.Context, args struct{ ID gql.ID }) (*postResolver, error) {
+	req := fromContext(ctx)
+	log := req.log.With(slog.String("mutation", "likePost"))
+
+	if err := req.authorized(); err != nil {
+		return nil, err
+	}
+
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if failure := like.Like(ctx, log, r.storage, id, req.login); failure != nil {
+		return nil, failed(failure)
+	}
+
+	log.Info("post liked", slog.Int64("id", id))
+	r.counter.PostLiked()
+
+	r.notifier.Notify(notifier.Event{
+		Type:   types.NotificationLike,
+		Actor:  req.login,
+		PostID: id,
+	})
+
+	r.publisher.Publish(broker.PostTopic(id), broker.EventLike, broker.ReactionData{PostID: id, Login: req.login})
+
+	return mutated(ctx, id)
+}
+
+func (r *resolver) UnlikePost(ctx context.Context, args struct{ ID gql.ID }) (*postResolver, error) {
+	req := fromContext(ctx)
+	log := req.log.With(slog.String("mutation", "unlikePost"))
+
+	if err := req.authorized(); err != nil {
+		return nil, err
+	}
+
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if failure := like.Unlike(ctx, log, r.storage, id, req.login); failure != nil {
+		return nil, failed(failure)
+	}
+
+	log.Info("post unliked", slog.Int64("id", id))
+
+	r.publisher.Publish(broker.PostTopic(id), broker.EventUnlike, broker.ReactionData{PostID: id, Login: req.login})
+
+	return mutated(ctx, id)
+}
+
+// authorized проверяет, что запрос сделан с токеном: без него доступны только запросы.
+func (req *request) authorized() error {
+	if req.login == "" {
+		return fail(resp.ErrUnauthorized, resp.CodeUnauthorized, "authorization is required for mutations")
+	}
+
+	return nil
+}
+
+// effects - то, что делается после сохранения поста, как в REST обработчиках.
+func (r *resolver) effects() save.Effects {
+	return save.Effects{
+		MentionSaver: r.storage,
+		PostFlagger:  r.storage,
+		PostCounter:  r.counter,
+		Notifier:     r.notifier,
+		Publisher:    r.publisher,
+	}
+}
+
+// mutated загружает пост заново после мутации.
+func mutated(ctx context.Context, id int64) (*postResolver, error) {
+	fromContext(ctx).loaders.forget(ctx, id)
+
+	post, err := loadPost(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if post == nil {
+		return nil, fail(storage.ErrNotFound, resp.CodePostNotFound, "post doesn't exist")
+	}
+
+	return post, nil
+}
+
+// valueOf возвращает значение необязательного аргумента, пустую строку - если его нет.
+func valueOf(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
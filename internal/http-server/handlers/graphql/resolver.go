@@ -0,0 +1,395 @@
+package graphql
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	gql "github.com/graph-gophers/graphql-go"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+// resolver - корень схемы: поля Query и Mutation.
+type resolver struct {
+	storage   Storage
+	policy    PolicyChecker
+	counter   Counter
+	notifier  Notifier
+	publisher Publisher
+}
+
+// queryError - ошибка поля. В extensions попадают тот же код, что и у ошибки
+// REST обработчика, и HTTP-статус, с которым она вернулась бы оттуда.
+type queryError struct {
+	kind error
+	code string
+	msg  string
+}
+
+func (e *queryError) Error() string {
+	return e.msg
+}
+
+func (e *queryError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":   e.code,
+		"status": resp.StatusOf(e.kind),
+	}
+}
+
+func fail(kind error, code string, msg string) error {
+	return &queryError{kind: kind, code: code, msg: msg}
+}
+
+// failed возвращает ошибку проверки, общей с REST обработчиками, как ошибку поля.
+func failed(failure *resp.Failure) error {
+	return fail(failure.Kind, failure.Code, failure.Msg)
+}
+
+// internal логирует ошибку хранилища и возвращает ошибку поля без подробностей.
+func (req *request) internal(msg string, err error) error {
+	req.log.Error(msg, sl.Err(err))
+
+	return fail(resp.ErrInternal, resp.CodeInternal, msg)
+}
+
+func (r *resolver) Me(ctx context.Context) (*userResolver, error) {
+	req := fromContext(ctx)
+	if req.login == "" {
+		return nil, nil
+	}
+
+	return loadUser(ctx, req.login)
+}
+
+func (r *resolver) User(ctx context.Context, args struct{ Login string }) (*userResolver, error) {
+	return loadUser(ctx, strings.TrimSpace(args.Login))
+}
+
+func (r *resolver) Post(ctx context.Context, args struct{ ID gql.ID }) (*postResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadPost(ctx, id)
+}
+
+// loadUser загружает пользователя login, nil - если его нет.
+func loadUser(ctx context.Context, login string) (*userResolver, error) {
+	req := fromContext(ctx)
+
+	profile, err := req.loaders.users.Load(ctx, login)()
+	if err != nil {
+		return nil, req.internal("failed to get user", err)
+	}
+
+	if profile == nil {
+		return nil, nil
+	}
+
+	return &userResolver{profile: profile}, nil
+}
+
+// loadPost загружает пост id, nil - если его нет или он скрыт от пользователя.
+func loadPost(ctx context.Context, id int64) (*postResolver, error) {
+	req := fromContext(ctx)
+
+	post, err := req.loaders.posts.Load(ctx, id)()
+	if err != nil {
+		return nil, req.internal("failed to get post", err)
+	}
+
+	if post == nil {
+		return nil, nil
+	}
+
+	return &postResolver{post: post}, nil
+}
+
+func parseID(id gql.ID) (int64, error) {
+	parsed, err := strconv.ParseInt(string(id), 10, 64)
+	if err != nil || parsed <= 0 {
+		return 0, fail(resp.ErrInvalid, resp.CodeInvalidID, "invalid post id")
+	}
+
+	return parsed, nil
+}
+
+// pageArgs - аргументы страницы. У first в схеме есть значение по умолчанию.
+type pageArgs struct {
+	First int32
+	After *string
+}
+
+// size проверяет размер страницы.
+func (a pageArgs) size() (int, error) {
+	first := int(a.First)
+	if first < 1 || first > maxPageSize {
+		return 0, fail(resp.ErrInvalid, resp.CodeValidationFailed, fmt.Sprintf("first must be from 1 to %d", maxPageSize))
+	}
+
+	return first, nil
+}
+
+// Курсоры непрозрачны для клиента: это позиция последнего элемента страницы в base64.
+func encodeCursor(position string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(position))
+}
+
+func decodeCursor(cursor string) (string, error) {
+	position, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", fail(resp.ErrInvalid, resp.CodeValidationFailed, "invalid cursor")
+	}
+
+	return string(position), nil
+}
+
+type connection[T any] struct {
+	edges       []*edge[T]
+	hasNextPage bool
+}
+
+func (c *connection[T]) Edges() []*edge[T] {
+	return c.edges
+}
+
+func (c *connection[T]) PageInfo() *pageInfo {
+	info := &pageInfo{hasNextPage: c.hasNextPage}
+	if len(c.edges) > 0 {
+		info.endCursor = &c.edges[len(c.edges)-1].cursor
+	}
+
+	return info
+}
+
+type edge[T any] struct {
+	cursor string
+	node   T
+}
+
+func (e *edge[T]) Cursor() string {
+	return e.cursor
+}
+
+func (e *edge[T]) Node() T {
+	return e.node
+}
+
+type pageInfo struct {
+	hasNextPage bool
+	endCursor   *string
+}
+
+func (p *pageInfo) HasNextPage() bool {
+	return p.hasNextPage
+}
+
+func (p *pageInfo) EndCursor() *string {
+	return p.endCursor
+}
+
+type userResolver struct {
+	profile *types.Profile
+}
+
+func (u *userResolver) Login() string       { return u.profile.Login }
+func (u *userResolver) DisplayName() string { return u.profile.DisplayName }
+func (u *userResolver) Bio() string         { return u.profile.Bio }
+func (u *userResolver) Website() string     { return u.profile.Website }
+func (u *userResolver) JoinedAt() string    { return u.profile.Joined_at }
+func (u *userResolver) Followers() int32    { return int32(u.profile.Followers) }
+func (u *userResolver) Following() int32    { return int32(u.profile.Following) }
+
+// Posts получает страницу постов пользователя. Курсор - дата и id последнего поста страницы.
+func (u *userResolver) Posts(ctx context.Context, args pageArgs) (*connection[*postResolver], error) {
+	req := fromContext(ctx)
+
+	first, err := args.size()
+	if err != nil {
+		return nil, err
+	}
+
+	var afterDate string
+	var afterID int64
+	if args.After != nil {
+		position, err := decodeCursor(*args.After)
+		if err != nil {
+			return nil, err
+		}
+
+		date, id, ok := strings.Cut(position, "|")
+		afterID, err = strconv.ParseInt(id, 10, 64)
+		if !ok || err != nil || afterID <= 0 {
+			return nil, fail(resp.ErrInvalid, resp.CodeValidationFailed, "invalid cursor")
+		}
+		afterDate = date
+	}
+
+	// лишний пост показывает, есть ли следующая страница
+	posts, err := req.reader.ListPosts(ctx, u.profile.Login, req.login, afterDate, afterID, first+1)
+	if err != nil {
+		return nil, req.internal("failed to get posts", err)
+	}
+
+	conn := &connection[*postResolver]{hasNextPage: len(posts) > first}
+	posts = posts[:min(len(posts), first)]
+
+	for i := range posts {
+		post := &posts[i]
+
+		// если пост страницы - оригинал репоста с нее же, он не загружается второй раз
+		req.loaders.posts.Prime(ctx, post.ID, post)
+
+		conn.edges = append(conn.edges, &edge[*postResolver]{
+			cursor: encodeCursor(post.Created_at + "|" + strconv.FormatInt(post.ID, 10)),
+			node:   &postResolver{post: post},
+		})
+	}
+
+	return conn, nil
+}
+
+type postResolver struct {
+	post *types.Post
+}
+
+func (p *postResolver) ID() gql.ID {
+	return gql.ID(strconv.FormatInt(p.post.ID, 10))
+}
+
+func (p *postResolver) Title() string      { return p.post.Title }
+func (p *postResolver) Text() string       { return p.post.Text }
+func (p *postResolver) Format() string     { return p.post.Format }
+func (p *postResolver) Visibility() string { return p.post.Visibility }
+func (p *postResolver) Likes() int32       { return int32(p.post.Likes) }
+func (p *postResolver) Reposts() int32     { return int32(p.post.Reposts) }
+func (p *postResolver) CreatedAt() string  { return p.post.Created_at }
+func (p *postResolver) UpdatedAt() string  { return p.post.Updated_at }
+
+func (p *postResolver) Author(ctx context.Context) (*userResolver, error) {
+	user, err := loadUser(ctx, p.post.Created_by)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, fail(storage.ErrNotFound, resp.CodeUserNotFound, "user doesn't exist")
+	}
+
+	return user, nil
+}
+
+func (p *postResolver) RepostOf(ctx context.Context) (*postResolver, error) {
+	if p.post.RepostOfID == 0 {
+		return nil, nil
+	}
+
+	return loadPost(ctx, p.post.RepostOfID)
+}
+
+func (p *postResolver) ReactionCounts(ctx context.Context) ([]*reactionCount, error) {
+	req := fromContext(ctx)
+
+	counts, err := req.loaders.reactionCounts.Load(ctx, p.post.ID)()
+	if err != nil {
+		return nil, req.internal("failed to count reactions", err)
+	}
+
+	result := make([]*reactionCount, 0, len(counts))
+	for kind, count := range counts {
+		result = append(result, &reactionCount{kind: kind, count: count})
+	}
+
+	slices.SortFunc(result, func(a, b *reactionCount) int {
+		return strings.Compare(a.kind, b.kind)
+	})
+
+	return result, nil
+}
+
+// Reactions получает страницу реакций вида kind. Курсор - id последней реакции страницы.
+func (p *postResolver) Reactions(ctx context.Context, args struct {
+	Kind  string
+	First int32
+	After *string
+}) (*connection[*reactionResolver], error) {
+	req := fromContext(ctx)
+
+	first, err := pageArgs{First: args.First, After: args.After}.size()
+	if err != nil {
+		return nil, err
+	}
+
+	var afterID int64
+	if args.After != nil {
+		position, err := decodeCursor(*args.After)
+		if err != nil {
+			return nil, err
+		}
+
+		afterID, err = strconv.ParseInt(position, 10, 64)
+		if err != nil || afterID <= 0 {
+			return nil, fail(resp.ErrInvalid, resp.CodeValidationFailed, "invalid cursor")
+		}
+	}
+
+	// лишняя реакция показывает, есть ли следующая страница
+	reactions, err := req.loaders.reactions.Load(ctx, reactionsKey{
+		postID:        p.post.ID,
+		reactionsPage: reactionsPage{kind: args.Kind, afterID: afterID, limit: first + 1},
+	})()
+	if err != nil {
+		return nil, req.internal("failed to get reactions", err)
+	}
+
+	conn := &connection[*reactionResolver]{hasNextPage: len(reactions) > first}
+	reactions = reactions[:min(len(reactions), first)]
+
+	for i := range reactions {
+		reaction := &reactions[i]
+
+		conn.edges = append(conn.edges, &edge[*reactionResolver]{
+			cursor: encodeCursor(strconv.FormatInt(reaction.ID, 10)),
+			node:   &reactionResolver{reaction: reaction},
+		})
+	}
+
+	return conn, nil
+}
+
+type reactionCount struct {
+	kind  string
+	count int
+}
+
+func (c *reactionCount) Kind() string { return c.kind }
+func (c *reactionCount) Count() int32 { return int32(c.count) }
+
+type reactionResolver struct {
+	reaction *types.Reaction
+}
+
+func (r *reactionResolver) Kind() string {
+	return r.reaction.Kind
+}
+
+func (r *reactionResolver) User(ctx context.Context) (*userResolver, error) {
+	user, err := loadUser(ctx, r.reaction.Login)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, fail(storage.ErrNotFound, resp.CodeUserNotFound, "user doesn't exist")
+	}
+
+	return user, nil
+}
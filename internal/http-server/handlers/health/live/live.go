@@ -0,0 +1,28 @@
+package live
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+)
+
+type Response struct {
+	resp.Response
+}
+
+// @Summary     Liveness
+// @Tags        health
+// @Description check that the process is alive; doesn't check storage or other dependencies
+// @ID          healthz
+// @Produde     json
+// @Success     200 {object} models.HealthSuccess
+// @Router      /healthz [get]
+func New() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+		})
+	}
+}
@@ -0,0 +1,43 @@
+// Code generated by mockery v2.30.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	health "github.com/solumD/go-blog-api/internal/lib/health"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ReadinessChecker is an autogenerated mock type for the ReadinessChecker type
+type ReadinessChecker struct {
+	mock.Mock
+}
+
+// Check provides a mock function with given fields: ctx
+func (_m *ReadinessChecker) Check(ctx context.Context) health.Result {
+	ret := _m.Called(ctx)
+
+	var r0 health.Result
+	if rf, ok := ret.Get(0).(func(context.Context) health.Result); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(health.Result)
+	}
+
+	return r0
+}
+
+// NewReadinessChecker creates a new instance of ReadinessChecker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReadinessChecker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReadinessChecker {
+	mock := &ReadinessChecker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
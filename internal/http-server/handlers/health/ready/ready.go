@@ -0,0 +1,80 @@
+package ready
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/health"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+)
+
+type Response struct {
+	resp.Response
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=ReadinessChecker
+type ReadinessChecker interface {
+	Check(ctx context.Context) health.Result
+}
+
+// @Summary     Readiness
+// @Tags        health
+// @Description check that the service can serve requests: storage is available, migrations are applied
+// @Description and background workers are running; fails while the server is shutting down
+// @ID          readyz
+// @Produde     json
+// @Success     200 {object} models.ReadySuccess
+// @Failure     503 {object} models.ReadyError
+// @Router      /readyz [get]
+func New(log *slog.Logger, readinessChecker ReadinessChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.health.ready.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		res := readinessChecker.Check(ctx)
+
+		if res.Stopping {
+			log.Info("not ready: server is shutting down")
+
+			render.Status(r, http.StatusServiceUnavailable)
+			render.JSON(w, r, Response{
+				Response: resp.Error("server is shutting down"),
+				Checks:   res.Checks,
+			})
+
+			return
+		}
+
+		if !res.Ready {
+			log.Error("not ready", slog.Any("checks", res.Checks))
+
+			render.Status(r, http.StatusServiceUnavailable)
+			render.JSON(w, r, Response{
+				Response: resp.Error("service is not ready"),
+				Checks:   res.Checks,
+			})
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Checks:   res.Checks,
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package ready_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/health/ready"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/health/ready/mocks"
+	"github.com/solumD/go-blog-api/internal/lib/health"
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadyHandler(t *testing.T) {
+	testCases := []struct {
+		name       string
+		result     health.Result
+		respError  string
+		statusCode int
+	}{
+		{
+			name:       "Ready",
+			result:     health.Result{Ready: true, Checks: map[string]string{"storage": "ok"}},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Check failed",
+			result:     health.Result{Checks: map[string]string{"storage": "database is closed"}},
+			respError:  "service is not ready",
+			statusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:       "Shutting down",
+			result:     health.Result{Stopping: true, Checks: map[string]string{"storage": "ok"}},
+			respError:  "server is shutting down",
+			statusCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			readinessCheckerMock := mocks.NewReadinessChecker(t)
+			readinessCheckerMock.On("Check", mock.Anything).Return(tc.result).Once()
+
+			handler := ready.New(loggerdiscard.NewDiscardLogger(), readinessCheckerMock)
+
+			req, err := http.NewRequest(http.MethodGet, "/readyz", nil)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			require.Equal(t, tc.statusCode, recorder.Code)
+
+			var resp ready.Response
+
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+
+			require.Equal(t, tc.respError, resp.Error)
+			require.Equal(t, tc.result.Checks, resp.Checks)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package version
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/buildinfo"
+)
+
+type Response struct {
+	resp.Response
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// @Summary     Version
+// @Tags        health
+// @Description get git commit, build time and Go version of the running build
+// @ID          version
+// @Produde     json
+// @Success     200 {object} models.VersionSuccess
+// @Router      /version [get]
+func New() http.HandlerFunc {
+	info := buildinfo.Get()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		render.JSON(w, r, Response{
+			Response:  resp.OK(),
+			Commit:    info.Commit,
+			BuildTime: info.BuildTime,
+			GoVersion: info.GoVersion,
+		})
+	}
+}
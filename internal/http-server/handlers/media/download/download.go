@@ -0,0 +1,128 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+type MediaGetter interface {
+	GetMedia(ctx context.Context, id int64) (*types.Media, error)
+	CanViewPost(ctx context.Context, id int, viewer string) (bool, error)
+}
+
+type BlobGetter interface {
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// @Summary     Download
+// @Security    ApiKeyAuth
+// @Tags        media
+// @Description get an uploaded image or its thumbnail; authorization is optional
+// @Description media attached to a post is visible to everyone who can see the post,
+// @Description media that is not attached yet is visible only to its owner
+// @ID          download
+// @Produce     png,jpeg,gif
+// @Param       id        path     int  true  "id of media"
+// @Param       thumbnail query    bool false "return a thumbnail instead of the original"
+// @Success     200       {file}   binary
+// @Failure     400,404,500 {object} models.Problem
+// @Router      /v1/media/{id} [get]
+func New(log *slog.Logger, mediaGetter MediaGetter, blobGetter BlobGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.media.download.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+		defer cancel()
+
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidID, "invalid media id")
+
+			return
+		}
+
+		m, err := mediaGetter.GetMedia(ctx, id)
+		if errors.Is(err, storage.ErrMediaNotFound) {
+			log.Error("invalid request", sl.Err(fmt.Errorf("media doesn't exist: %d", id)))
+
+			resp.Fail(w, r, storage.ErrNotFound, resp.CodeMediaNotFound, "media doesn't exist")
+
+			return
+		} else if err != nil {
+			log.Error("failed to get media", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to get media")
+
+			return
+		}
+
+		viewer := r.Header.Get("login")
+
+		// аватар пользователя виден всем
+		visible := m.Owner == viewer || m.Avatar
+		if !visible && m.PostID != 0 {
+			visible, err = mediaGetter.CanViewPost(ctx, int(m.PostID), viewer)
+			if err != nil {
+				log.Error("failed to check if post is visible", sl.Err(err))
+
+				resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to get media")
+
+				return
+			}
+		}
+
+		if !visible {
+			log.Error("invalid request", sl.Err(fmt.Errorf("media is hidden from viewer: %d", id)))
+
+			resp.Fail(w, r, storage.ErrNotFound, resp.CodeMediaNotFound, "media doesn't exist")
+
+			return
+		}
+
+		key, contentType := m.BlobKey, m.ContentType
+		if thumb, _ := strconv.ParseBool(r.URL.Query().Get("thumbnail")); thumb {
+			key, contentType = m.ThumbnailKey, m.ThumbnailType
+		}
+
+		blob, err := blobGetter.Get(ctx, key)
+		if err != nil {
+			log.Error("failed to get file", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to get file")
+
+			return
+		}
+		defer blob.Close()
+
+		// содержимое загрузки никогда не меняется, поэтому его можно кэшировать навсегда
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+
+		if _, err := io.Copy(w, blob); err != nil {
+			log.Error("failed to write file", sl.Err(err))
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// Code generated by mockery v2.30.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	types "github.com/solumD/go-blog-api/internal/types"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MediaSaver is an autogenerated mock type for the MediaSaver type
+type MediaSaver struct {
+	mock.Mock
+}
+
+// SaveMedia provides a mock function with given fields: ctx, media, date_created
+func (_m *MediaSaver) SaveMedia(ctx context.Context, media types.Media, date_created string) (int64, error) {
+	ret := _m.Called(ctx, media, date_created)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.Media, string) (int64, error)); ok {
+		return rf(ctx, media, date_created)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.Media, string) int64); ok {
+		r0 = rf(ctx, media, date_created)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.Media, string) error); ok {
+		r1 = rf(ctx, media, date_created)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMediaSaver creates a new instance of MediaSaver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMediaSaver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MediaSaver {
+	mock := &MediaSaver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
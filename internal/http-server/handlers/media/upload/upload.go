@@ -0,0 +1,196 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/lib/media"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+// formField - имя поля multipart-формы с файлом
+const formField = "file"
+
+type Response struct {
+	resp.Response
+	*types.Media
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=MediaSaver
+type MediaSaver interface {
+	SaveMedia(ctx context.Context, media types.Media, date_created string) (int64, error)
+}
+
+type BlobSaver interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Delete(ctx context.Context, key string) error
+}
+
+// @Summary     Upload
+// @Security    ApiKeyAuth
+// @Tags        media
+// @Description upload an image to attach it to a post later
+// @ID          upload
+// @Accept      mpfd
+// @Produde     json
+// @Param       file        formData file true "image (png, jpeg, gif or webp)"
+// @Success     200         {object} models.UploadSuccess
+// @Failure     400,413,415,500 {object} models.Problem
+// @Router      /v1/media [post]
+func New(maxSize int64, thumbnailSize int, log *slog.Logger, mediaSaver MediaSaver, blobSaver BlobSaver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.media.upload.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+		defer cancel()
+
+		// запас на заголовки multipart-формы
+		r.Body = http.MaxBytesReader(w, r.Body, maxSize+64<<10)
+
+		file, _, err := r.FormFile(formField)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				log.Error("invalid request", sl.Err(err))
+
+				resp.Fail(w, r, resp.ErrTooLarge, resp.CodeFileTooLarge, fmt.Sprintf("file can't be larger than %d bytes", maxSize))
+
+				return
+			}
+
+			log.Error("failed to read file from request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidFile, "failed to read file from request")
+
+			return
+		}
+		defer file.Close()
+
+		// читаем на байт больше лимита, чтобы понять, что файл слишком большой
+		data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+		if err != nil {
+			log.Error("failed to read file from request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidFile, "failed to read file from request")
+
+			return
+		}
+
+		if int64(len(data)) > maxSize {
+			log.Error("invalid request", sl.Err(fmt.Errorf("file is too large")))
+
+			resp.Fail(w, r, resp.ErrTooLarge, resp.CodeFileTooLarge, fmt.Sprintf("file can't be larger than %d bytes", maxSize))
+
+			return
+		}
+
+		img, err := media.Process(data, thumbnailSize)
+		if errors.Is(err, media.ErrUnsupportedType) {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrUnsupportedMedia, resp.CodeUnsupportedImage, "only png, jpeg, gif and webp images are supported")
+
+			return
+		} else if errors.Is(err, media.ErrTooManyPixels) {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeImageTooLarge, err.Error())
+
+			return
+		} else if err != nil {
+			log.Error("failed to process image", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to process image")
+
+			return
+		}
+
+		key, err := newKey()
+		if err != nil {
+			log.Error("failed to generate blob key", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to save file")
+
+			return
+		}
+
+		m := types.Media{
+			Owner:         r.Header.Get("login"),
+			ContentType:   img.ContentType,
+			Size:          int64(len(data)),
+			Width:         img.Width,
+			Height:        img.Height,
+			BlobKey:       key,
+			ThumbnailKey:  key + ".thumb",
+			ThumbnailType: img.ThumbnailType,
+		}
+
+		if err := blobSaver.Put(ctx, m.BlobKey, bytes.NewReader(data)); err != nil {
+			log.Error("failed to save file", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to save file")
+
+			return
+		}
+
+		if err := blobSaver.Put(ctx, m.ThumbnailKey, bytes.NewReader(img.Thumbnail)); err != nil {
+			log.Error("failed to save thumbnail", sl.Err(err))
+			blobSaver.Delete(ctx, m.BlobKey)
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to save file")
+
+			return
+		}
+
+		m.Created_at = time.Now().Format("2006-01-02 15:04:05")
+
+		id, err := mediaSaver.SaveMedia(ctx, m, m.Created_at)
+		if err != nil {
+			log.Error("failed to save media", sl.Err(err))
+			blobSaver.Delete(ctx, m.BlobKey)
+			blobSaver.Delete(ctx, m.ThumbnailKey)
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to save file")
+
+			return
+		}
+
+		m.ID = id
+
+		log.Info("media uploaded", slog.Int64("id", id), slog.String("content_type", m.ContentType))
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Media:    &m,
+		})
+	}
+}
+
+// newKey генерирует случайный ключ, по которому файл сохраняется в BlobStore.
+func newKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,138 @@
+package upload_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/media/upload"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/media/upload/mocks"
+	"github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/solumD/go-blog-api/internal/storage/blob"
+	"github.com/solumD/go-blog-api/internal/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+
+	return buf.Bytes()
+}
+
+func TestUploadHandler(t *testing.T) {
+	const maxSize = 1 << 20
+
+	testCases := []struct {
+		name       string
+		file       []byte
+		respError  string
+		mockError  error
+		statusCode int
+		blobs      int
+	}{
+		{
+			name:       "Success",
+			file:       testPNG(t, 600, 300),
+			statusCode: http.StatusOK,
+			blobs:      2,
+		},
+		{
+			name:       "Not an image",
+			file:       []byte("<html><script>alert(1)</script></html>"),
+			respError:  "only png, jpeg, gif and webp images are supported",
+			statusCode: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:       "Too large",
+			file:       make([]byte, maxSize+1),
+			respError:  "file can't be larger than 1048576 bytes",
+			statusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:       "SaveMedia Error",
+			file:       testPNG(t, 10, 10),
+			respError:  "failed to save file",
+			mockError:  errors.New("unexpected error"),
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mediaSaverMock := mocks.NewMediaSaver(t)
+			blobs := blob.NewMemory()
+
+			if tc.respError == "" || tc.mockError != nil {
+				mediaSaverMock.On("SaveMedia", mock.Anything, mock.AnythingOfType("types.Media"), mock.AnythingOfType("string")).
+					Return(int64(1), tc.mockError).
+					Once()
+			}
+
+			handler := upload.New(maxSize, 128, loggerdiscard.NewDiscardLogger(), mediaSaverMock, blobs)
+
+			var body bytes.Buffer
+			mw := multipart.NewWriter(&body)
+			fw, err := mw.CreateFormFile("file", "image.png")
+			require.NoError(t, err)
+			_, err = fw.Write(tc.file)
+			require.NoError(t, err)
+			require.NoError(t, mw.Close())
+
+			req, err := http.NewRequest(http.MethodPost, "/media", &body)
+			require.NoError(t, err)
+
+			req.Header.Set("Content-Type", mw.FormDataContentType())
+			req.Header.Add("login", "test_user")
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			require.Equal(t, tc.statusCode, recorder.Code)
+
+			require.Equal(t, tc.blobs, blobs.Len())
+
+			if tc.respError != "" {
+				var problem response.Problem
+
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+
+				require.Equal(t, tc.respError, problem.Detail)
+
+				return
+			}
+
+			var resp struct {
+				upload.Response
+				types.Media
+			}
+
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+
+			if tc.statusCode == http.StatusOK {
+				require.Equal(t, "image/png", resp.ContentType)
+				require.Equal(t, 600, resp.Width)
+				require.Equal(t, 300, resp.Height)
+				require.Equal(t, "test_user", resp.Owner)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package actions
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	"github.com/solumD/go-blog-api/internal/lib/api/pagination"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+type Response struct {
+	resp.Response
+	Actions    []types.ModerationAction `json:"actions"`
+	NextOffset *int                     `json:"next_offset,omitempty"`
+}
+
+type ActionsGetter interface {
+	GetModerationActions(ctx context.Context, limit int, offset int) ([]types.ModerationAction, error)
+}
+
+// @Summary     Moderation actions
+// @Security    ApiKeyAuth
+// @Tags        moderation
+// @Description get the log of moderators' decisions on reports with their notes, the latest first;
+// @Description available to moderators only
+// @ID          moderation-actions
+// @Accept      json
+// @Produde     json
+// @Param       limit       query    int false "page size, 20 by default, 100 at most"
+// @Param       offset      query    int false "offset of the page"
+// @Success     200         {object} models.ModerationActionsSuccess
+// @Failure     400,403,500 {object} models.Problem
+// @Router      /v1/mod/actions [get]
+func New(log *slog.Logger, actionsGetter ActionsGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.mod.actions.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		page, err := pagination.Parse(r)
+		if err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, err.Error())
+
+			return
+		}
+
+		// запрашиваем на одно решение больше, чтобы понять, есть ли следующая страница
+		actions, err := actionsGetter.GetModerationActions(ctx, page.Limit+1, page.Offset)
+		if err != nil {
+			log.Error("failed to get moderation actions", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to get moderation actions")
+
+			return
+		}
+
+		next := page.Next(len(actions))
+		if next != nil {
+			actions = actions[:page.Limit]
+		}
+
+		log.Info("moderation actions got", slog.Int("count", len(actions)))
+
+		render.JSON(w, r, Response{
+			Response:   resp.OK(),
+			Actions:    actions,
+			NextOffset: next,
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package reports
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	"github.com/solumD/go-blog-api/internal/lib/api/pagination"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+type Response struct {
+	resp.Response
+	Reports    []types.ReportGroup `json:"reports"`
+	NextOffset *int                `json:"next_offset,omitempty"`
+}
+
+type ReportsGetter interface {
+	GetReportGroups(ctx context.Context, limit int, offset int) ([]types.ReportGroup, error)
+}
+
+// @Summary     Reports
+// @Security    ApiKeyAuth
+// @Tags        moderation
+// @Description get the moderation queue: open reports grouped by post, posts with the most reports first;
+// @Description available to moderators only
+// @ID          reports
+// @Accept      json
+// @Produde     json
+// @Param       limit       query    int false "page size, 20 by default, 100 at most"
+// @Param       offset      query    int false "offset of the page"
+// @Success     200         {object} models.ReportsSuccess
+// @Failure     400,403,500 {object} models.Problem
+// @Router      /v1/mod/reports [get]
+func New(log *slog.Logger, reportsGetter ReportsGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.mod.reports.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		page, err := pagination.Parse(r)
+		if err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, err.Error())
+
+			return
+		}
+
+		// запрашиваем на одну группу больше, чтобы понять, есть ли следующая страница
+		reports, err := reportsGetter.GetReportGroups(ctx, page.Limit+1, page.Offset)
+		if err != nil {
+			log.Error("failed to get reports", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to get reports")
+
+			return
+		}
+
+		next := page.Next(len(reports))
+		if next != nil {
+			reports = reports[:page.Limit]
+		}
+
+		log.Info("reports got", slog.Int("count", len(reports)))
+
+		render.JSON(w, r, Response{
+			Response:   resp.OK(),
+			Reports:    reports,
+			NextOffset: next,
+		})
+	}
+}
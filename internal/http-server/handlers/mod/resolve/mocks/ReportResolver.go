@@ -0,0 +1,52 @@
+// Code generated by mockery v2.30.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ReportResolver is an autogenerated mock type for the ReportResolver type
+type ReportResolver struct {
+	mock.Mock
+}
+
+// ResolveReports provides a mock function with given fields: ctx, id, moderator, action, note, date_created
+func (_m *ReportResolver) ResolveReports(ctx context.Context, id int64, moderator string, action string, note string, date_created string) (string, error) {
+	ret := _m.Called(ctx, id, moderator, action, note, date_created)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string, string, string) (string, error)); ok {
+		return rf(ctx, id, moderator, action, note, date_created)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string, string, string) string); ok {
+		r0 = rf(ctx, id, moderator, action, note, date_created)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string, string, string) error); ok {
+		r1 = rf(ctx, id, moderator, action, note, date_created)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewReportResolver creates a new instance of ReportResolver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReportResolver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReportResolver {
+	mock := &ReportResolver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
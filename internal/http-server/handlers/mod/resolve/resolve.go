@@ -0,0 +1,138 @@
+package resolve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/broker"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/lib/validator"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+type Request struct {
+	Action string `json:"action"`
+	Note   string `json:"note"`
+}
+
+type Response struct {
+	resp.Response
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=ReportResolver
+type ReportResolver interface {
+	ResolveReports(ctx context.Context, id int64, moderator string, action string, note string, date_created string) (string, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=Publisher
+type Publisher interface {
+	Publish(topic string, eventType string, data any)
+}
+
+// @Summary     Resolve reports
+// @Security    ApiKeyAuth
+// @Tags        moderation
+// @Description resolve all open reports on a post: dismiss them, hide the post, delete the post or suspend
+// @Description its author (the post is hidden too); the decision is recorded with the moderator and the note;
+// @Description available to moderators only
+// @ID          resolve-reports
+// @Accept      json
+// @Produde     json
+// @Param       id              path     int     true "id of a reported post"
+// @Param       input           body     Request true "action: dismiss, hide, delete or suspend, and a note, 500 characters at most"
+// @Success     200             {object} models.ResolveReportsSuccess
+// @Failure     400,403,404,500 {object} models.Problem
+// @Router      /v1/mod/reports/{id}/resolve [post]
+func New(log *slog.Logger, reportResolver ReportResolver, eventPublisher Publisher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.mod.resolve.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidID, "invalid post id")
+
+			return
+		}
+
+		var req Request
+
+		err = render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidJSON, "failed to decode request")
+
+			return
+		}
+
+		req.Note = strings.TrimSpace(req.Note)
+
+		if err := validator.ValidateModerationAction(req.Action); err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, err.Error())
+
+			return
+		}
+
+		if err := validator.ValidateNote(req.Note); err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, err.Error())
+
+			return
+		}
+
+		moderator := r.Header.Get("login")
+		date_created := time.Now().Format("2006-01-02 15:04:05")
+
+		author, err := reportResolver.ResolveReports(ctx, id, moderator, req.Action, req.Note, date_created)
+		if errors.Is(err, storage.ErrReportsNotFound) {
+			log.Error("invalid request", sl.Err(fmt.Errorf("no open reports on post %d", id)))
+
+			resp.Fail(w, r, storage.ErrNotFound, resp.CodeReportsNotFound, "no open reports on the post")
+
+			return
+		} else if err != nil {
+			log.Error("failed to resolve reports", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to resolve reports")
+
+			return
+		}
+
+		if req.Action == types.ModerationDelete {
+			eventPublisher.Publish(broker.PostTopic(id), broker.EventPostDeleted, broker.PostData{PostID: id})
+			eventPublisher.Publish(broker.UserTopic(author), broker.EventPostDeleted, broker.PostData{PostID: id})
+		}
+
+		log.Info("reports resolved", slog.Int64("id", id), slog.String("action", req.Action))
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package resolve_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/mod/resolve"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/mod/resolve/mocks"
+	"github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/broker"
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveHandler(t *testing.T) {
+	testCases := []struct {
+		name       string
+		id         string
+		body       string
+		action     string
+		note       string
+		respError  string
+		mockError  error
+		statusCode int
+	}{
+		{
+			name:       "Dismiss",
+			id:         "1",
+			body:       `{"action": "dismiss", "note": " not spam "}`,
+			action:     "dismiss",
+			note:       "not spam",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Delete",
+			id:         "1",
+			body:       `{"action": "delete", "note": "spam"}`,
+			action:     "delete",
+			note:       "spam",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Suspend",
+			id:         "1",
+			body:       `{"action": "suspend", "note": "repeated harassment"}`,
+			action:     "suspend",
+			note:       "repeated harassment",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid id",
+			id:         "abc",
+			body:       `{"action": "hide"}`,
+			respError:  "invalid post id",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Unknown action",
+			id:         "1",
+			body:       `{"action": "ban"}`,
+			respError:  "action must be one of: dismiss, hide, delete, suspend",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Too long note",
+			id:         "1",
+			body:       `{"action": "hide", "note": "` + strings.Repeat("a", 501) + `"}`,
+			respError:  "note cannot be longer than 500 characters",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "No open reports",
+			id:         "1",
+			body:       `{"action": "hide"}`,
+			action:     "hide",
+			respError:  "no open reports on the post",
+			mockError:  storage.ErrReportsNotFound,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "ResolveReports Error",
+			id:         "1",
+			body:       `{"action": "hide"}`,
+			action:     "hide",
+			respError:  "failed to resolve reports",
+			mockError:  errors.New("unexpected error"),
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			reportResolverMock := mocks.NewReportResolver(t)
+
+			if tc.action != "" {
+				reportResolverMock.On("ResolveReports", mock.Anything, int64(1), "mod_user", tc.action, tc.note, mock.AnythingOfType("string")).
+					Return("author_user", tc.mockError).
+					Once()
+			}
+
+			publisherMock := mocks.NewPublisher(t)
+
+			// об удалении поста узнают подписчики поста и автора
+			if tc.action == "delete" && tc.mockError == nil {
+				publisherMock.On("Publish", "post:1", broker.EventPostDeleted, broker.PostData{PostID: 1}).Once()
+				publisherMock.On("Publish", "user:author_user", broker.EventPostDeleted, broker.PostData{PostID: 1}).Once()
+			}
+
+			router := chi.NewRouter()
+			router.Post("/mod/reports/{id}/resolve", resolve.New(loggerdiscard.NewDiscardLogger(), reportResolverMock, publisherMock))
+
+			req, err := http.NewRequest(http.MethodPost, "/mod/reports/"+tc.id+"/resolve", strings.NewReader(tc.body))
+			require.NoError(t, err)
+
+			req.Header.Add("login", "mod_user")
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			require.Equal(t, tc.statusCode, recorder.Code)
+
+			if tc.respError != "" {
+				var problem response.Problem
+
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+
+				require.Equal(t, tc.respError, problem.Detail)
+
+				return
+			}
+
+			var resp resolve.Response
+
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+
+			require.Equal(t, response.StatusOK, resp.Status)
+		})
+	}
+}
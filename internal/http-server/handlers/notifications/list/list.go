@@ -0,0 +1,104 @@
+package list
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	"github.com/solumD/go-blog-api/internal/lib/api/pagination"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/lib/notifier"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+type Response struct {
+	resp.Response
+	Notifications []types.Notification `json:"notifications"`
+	Unread        int                  `json:"unread"`
+	NextOffset    *int                 `json:"next_offset,omitempty"`
+}
+
+type NotificationsGetter interface {
+	GetNotifications(ctx context.Context, login string, limit int, offset int) ([]types.Notification, error)
+	CountUnreadNotifications(ctx context.Context, login string) (int, error)
+}
+
+// @Summary     Notifications
+// @Security    ApiKeyAuth
+// @Tags        notifications
+// @Description get notifications grouped by type and post, newest first, and the number of unread groups
+// @ID          notifications
+// @Accept      json
+// @Produde     json
+// @Param       limit   query    int false "page size, 20 by default, 100 at most"
+// @Param       offset  query    int false "offset of the page"
+// @Success     200     {object} models.NotificationsSuccess
+// @Failure     400,500 {object} models.Problem
+// @Router      /v1/user/me/notifications [get]
+func New(log *slog.Logger, notificationsGetter NotificationsGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.notifications.list.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		page, err := pagination.Parse(r)
+		if err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, err.Error())
+
+			return
+		}
+
+		login := r.Header.Get("login")
+
+		// запрашиваем на одну группу больше, чтобы понять, есть ли следующая страница
+		notifications, err := notificationsGetter.GetNotifications(ctx, login, page.Limit+1, page.Offset)
+		if err != nil {
+			log.Error("failed to get notifications", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to get notifications")
+
+			return
+		}
+
+		next := page.Next(len(notifications))
+		if next != nil {
+			notifications = notifications[:page.Limit]
+		}
+
+		for i := range notifications {
+			notifications[i].Text = notifier.Describe(notifications[i])
+		}
+
+		unread, err := notificationsGetter.CountUnreadNotifications(ctx, login)
+		if err != nil {
+			log.Error("failed to count unread notifications", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to get notifications")
+
+			return
+		}
+
+		log.Info("notifications got", slog.Int("count", len(notifications)))
+
+		render.JSON(w, r, Response{
+			Response:      resp.OK(),
+			Notifications: notifications,
+			Unread:        unread,
+			NextOffset:    next,
+		})
+	}
+}
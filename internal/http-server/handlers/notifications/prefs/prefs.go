@@ -0,0 +1,62 @@
+package prefs
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+)
+
+type Response struct {
+	resp.Response
+	Preferences map[string]bool `json:"preferences"`
+}
+
+type PrefsGetter interface {
+	GetNotificationPrefs(ctx context.Context, login string) (map[string]bool, error)
+}
+
+// @Summary     Notification preferences
+// @Security    ApiKeyAuth
+// @Tags        notifications
+// @Description get which types of notifications are enabled
+// @ID          notification-preferences
+// @Accept      json
+// @Produde     json
+// @Success     200 {object} models.NotificationPrefsSuccess
+// @Failure     500 {object} models.Problem
+// @Router      /v1/user/me/notifications/preferences [get]
+func New(log *slog.Logger, prefsGetter PrefsGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.notifications.prefs.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		prefs, err := prefsGetter.GetNotificationPrefs(ctx, r.Header.Get("login"))
+		if err != nil {
+			log.Error("failed to get notification preferences", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to get notification preferences")
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response:    resp.OK(),
+			Preferences: prefs,
+		})
+	}
+}
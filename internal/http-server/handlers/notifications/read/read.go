@@ -0,0 +1,61 @@
+package read
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+)
+
+type Response struct {
+	resp.Response
+}
+
+type NotificationsReader interface {
+	ReadNotifications(ctx context.Context, login string) error
+}
+
+// @Summary     Read notifications
+// @Security    ApiKeyAuth
+// @Tags        notifications
+// @Description mark all notifications as read
+// @ID          read-notifications
+// @Accept      json
+// @Produde     json
+// @Success     200 {object} models.ReadNotificationsSuccess
+// @Failure     500 {object} models.Problem
+// @Router      /v1/user/me/notifications/read [post]
+func New(log *slog.Logger, notificationsReader NotificationsReader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.notifications.read.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		if err := notificationsReader.ReadNotifications(ctx, r.Header.Get("login")); err != nil {
+			log.Error("failed to read notifications", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to read notifications")
+
+			return
+		}
+
+		log.Info("notifications read")
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package setprefs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+type Response struct {
+	resp.Response
+}
+
+type PrefsSetter interface {
+	SetNotificationPrefs(ctx context.Context, login string, prefs map[string]bool) error
+}
+
+// @Summary     Set notification preferences
+// @Security    ApiKeyAuth
+// @Tags        notifications
+// @Description enable or disable types of notifications: like, reaction, follow, mention;
+// @Description types missing in the request are not changed
+// @ID          set-notification-preferences
+// @Accept      json
+// @Produde     json
+// @Param       input   body     map[string]bool true "types of notifications and whether they are enabled"
+// @Success     200     {object} models.SetNotificationPrefsSuccess
+// @Failure     400,500 {object} models.Problem
+// @Router      /v1/user/me/notifications/preferences [put]
+func New(log *slog.Logger, prefsSetter PrefsSetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.notifications.setprefs.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		var req map[string]bool
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidJSON, "failed to decode request")
+
+			return
+		}
+
+		for t := range req {
+			if !slices.Contains(types.NotificationTypes, t) {
+				log.Error("invalid request", sl.Err(fmt.Errorf("unknown notification type: %s", t)))
+
+				resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, fmt.Sprintf("notification type must be one of: %s",
+					strings.Join(types.NotificationTypes, ", ")))
+
+				return
+			}
+		}
+
+		if err := prefsSetter.SetNotificationPrefs(ctx, r.Header.Get("login"), req); err != nil {
+			log.Error("failed to set notification preferences", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to set notification preferences")
+
+			return
+		}
+
+		log.Info("notification preferences set", slog.Any("preferences", req))
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+		})
+	}
+}
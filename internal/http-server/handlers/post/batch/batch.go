@@ -0,0 +1,375 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/remove"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/save"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/update"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/lib/notifier"
+	"github.com/solumD/go-blog-api/internal/lib/policy"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+// Режимы выполнения пакета
+const (
+	// пакет выполняется целиком или не выполняется совсем
+	ModeAtomic = "atomic"
+	// выполняются все операции без ошибок
+	ModeBestEffort = "best_effort"
+)
+
+type Operation struct {
+	Op         string  `json:"op"`
+	ID         int64   `json:"id,omitempty"`
+	Title      string  `json:"title,omitempty"`
+	Text       string  `json:"text,omitempty"`
+	Format     string  `json:"format,omitempty"`
+	Visibility string  `json:"visibility,omitempty"`
+	Media      []int64 `json:"media,omitempty"`
+}
+
+type Request struct {
+	Mode       string      `json:"mode,omitempty"`
+	Operations []Operation `json:"operations"`
+}
+
+// Result - результат операции в том же порядке, что и в запросе.
+// Error - та же ошибка, что вернул бы одиночный запрос.
+type Result struct {
+	Op    string        `json:"op"`
+	ID    int64         `json:"id,omitempty"`
+	Error *resp.Problem `json:"error,omitempty"`
+}
+
+type Response struct {
+	resp.Response
+	Results []Result `json:"results"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=PostBatcher
+type PostBatcher interface {
+	GetPostCreator(ctx context.Context, id int) (string, error)
+	ApplyPostOperations(ctx context.Context, created_by string, ops []types.PostOperation, atomic bool, date string) ([]int64, []error, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=MentionSaver
+type MentionSaver interface {
+	IsUserExist(ctx context.Context, login string) (bool, error)
+	SetPostMentions(ctx context.Context, id int64, logins []string, date_created string) ([]string, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=PolicyChecker
+type PolicyChecker interface {
+	Check(ctx context.Context, c policy.Content) (policy.Decision, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=PostFlagger
+type PostFlagger interface {
+	ReportPost(ctx context.Context, id int, reporter string, reason string, note string, date_created string) error
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=PostCounter
+type PostCounter interface {
+	PostCreated()
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=Notifier
+type Notifier interface {
+	Notify(e notifier.Event)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=Publisher
+type Publisher interface {
+	Publish(topic string, eventType string, data any)
+}
+
+// @Summary     Batch
+// @Security    ApiKeyAuth
+// @Tags        post
+// @Description create, update and delete posts in one transaction
+// @ID          batch
+// @Accept      json
+// @Produde     json
+// @Param       input   body     Request true "operations and mode: atomic (default) or best_effort"
+// @Success     200     {object} Response
+// @Failure     400,500 {object} models.Problem
+// @Failure     409     {object} models.BatchAborted
+// @Router      /v1/posts:batch [post]
+func New(maxOperations int, log *slog.Logger, postBatcher PostBatcher, mentionSaver MentionSaver, policyChecker PolicyChecker, postFlagger PostFlagger, postCounter PostCounter, eventNotifier Notifier, eventPublisher Publisher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.post.batch.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidJSON, "failed to decode request")
+
+			return
+		}
+
+		log.Info("request body decoded", slog.String("mode", req.Mode), slog.Int("operations", len(req.Operations)))
+
+		// по умолчанию пакет выполняется целиком или не выполняется совсем
+		if req.Mode == "" {
+			req.Mode = ModeAtomic
+		}
+
+		if req.Mode != ModeAtomic && req.Mode != ModeBestEffort {
+			log.Error("invalid request", sl.Err(fmt.Errorf("invalid mode: %s", req.Mode)))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, fmt.Sprintf("mode must be %s or %s", ModeAtomic, ModeBestEffort))
+
+			return
+		}
+
+		if len(req.Operations) == 0 || len(req.Operations) > maxOperations {
+			log.Error("invalid request", sl.Err(fmt.Errorf("invalid number of operations: %d", len(req.Operations))))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, fmt.Sprintf("batch must have from 1 to %d operations", maxOperations))
+
+			return
+		}
+
+		login := r.Header.Get("login")
+		atomic := req.Mode == ModeAtomic
+
+		results := make([]Result, len(req.Operations))
+		decisions := make([]policy.Decision, len(req.Operations))
+
+		// операции, прошедшие проверки, и их номера в запросе
+		var ops []types.PostOperation
+		var indexes []int
+
+		for i, op := range req.Operations {
+			results[i] = Result{Op: op.Op, ID: op.ID}
+
+			checked, decision, problem := check(ctx, r, log.With(slog.Int("operation", i)), postBatcher, policyChecker, login, op)
+			if problem != nil {
+				results[i].Error = problem
+				continue
+			}
+
+			decisions[i] = decision
+			ops = append(ops, checked)
+			indexes = append(indexes, i)
+		}
+
+		failed := len(ops) < len(req.Operations)
+
+		var ids []int64
+		var errs []error
+
+		if len(ops) > 0 && !(atomic && failed) {
+			date := time.Now().Format("2006-01-02 15:04:05")
+
+			ids, errs, err = postBatcher.ApplyPostOperations(ctx, login, ops, atomic, date)
+			if err != nil {
+				log.Error("failed to apply operations", sl.Err(err))
+
+				resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to apply operations")
+
+				return
+			}
+
+			for j, err := range errs {
+				if err == nil {
+					continue
+				}
+
+				failed = true
+				i := indexes[j]
+
+				log.Error("failed to apply operation", slog.Int("operation", i), sl.Err(err))
+
+				results[i].Error = storageProblem(r, results[i].Op, err)
+			}
+		}
+
+		// в атомарном режиме из-за одной ошибки не выполняется ни одна операция
+		if atomic && failed {
+			for i := range results {
+				if results[i].Error == nil {
+					results[i].Error = problem(r, resp.ErrFailedDependency, resp.CodeBatchAborted, "another operation in the batch failed")
+				}
+			}
+
+			log.Info("batch aborted")
+
+			resp.FailWith(w, r, storage.ErrConflict, resp.CodeBatchAborted, "batch aborted, no operations were applied", "results", results)
+
+			return
+		}
+
+		effects := save.Effects{
+			MentionSaver: mentionSaver,
+			PostFlagger:  postFlagger,
+			PostCounter:  postCounter,
+			Notifier:     eventNotifier,
+			Publisher:    eventPublisher,
+		}
+
+		for j, i := range indexes {
+			if j >= len(ids) || errs[j] != nil {
+				continue
+			}
+
+			results[i].ID = ids[j]
+
+			applied(ctx, log, effects, ops[j], ids[j], decisions[i], login)
+		}
+
+		log.Info("batch applied", slog.Int("applied", len(ids)-countErrors(errs)), slog.Int("operations", len(results)))
+
+		response := resp.OK()
+		if failed {
+			response = resp.Error("some operations failed")
+		}
+
+		render.JSON(w, r, Response{
+			Response: response,
+			Results:  results,
+		})
+	}
+}
+
+// check проверяет операцию теми же проверками, что и save, update и remove,
+// и возвращает ее в виде для хранилища и решение политики для создания и изменения.
+func check(ctx context.Context, r *http.Request, log *slog.Logger, postBatcher PostBatcher, policyChecker PolicyChecker, login string, op Operation) (types.PostOperation, policy.Decision, *resp.Problem) {
+	checked := types.PostOperation{Op: op.Op, ID: op.ID}
+	allow := policy.Decision{Verdict: policy.Allow}
+
+	failed := func(failure *resp.Failure) (types.PostOperation, policy.Decision, *resp.Problem) {
+		return checked, allow, problem(r, failure.Kind, failure.Code, failure.Msg)
+	}
+
+	invalid := func(failure *resp.Failure) (types.PostOperation, policy.Decision, *resp.Problem) {
+		log.Error("invalid operation", sl.Err(failure))
+
+		return failed(failure)
+	}
+
+	switch op.Op {
+	case types.OperationCreate:
+		req := save.Request{Title: op.Title, Text: op.Text, Format: op.Format, Visibility: op.Visibility, Media: op.Media}
+		if failure := save.Validate(&req); failure != nil {
+			return invalid(failure)
+		}
+
+		checked.Title, checked.Text, checked.Format, checked.Visibility, checked.Media = req.Title, req.Text, req.Format, req.Visibility, req.Media
+	case types.OperationUpdate:
+		req := update.Request{ID: int(op.ID), Title: op.Title, Text: op.Text, Format: op.Format, Visibility: op.Visibility}
+		if failure := update.Validate(&req); failure != nil {
+			return invalid(failure)
+		}
+
+		if len(op.Media) > 0 {
+			return invalid(&resp.Failure{Kind: resp.ErrInvalid, Code: resp.CodeValidationFailed, Msg: "media can't be changed"})
+		}
+
+		checked.Title, checked.Text, checked.Format, checked.Visibility = req.Title, req.Text, req.Format, req.Visibility
+	case types.OperationDelete:
+	default:
+		return invalid(&resp.Failure{
+			Kind: resp.ErrInvalid,
+			Code: resp.CodeValidationFailed,
+			Msg:  fmt.Sprintf("op must be one of: %s, %s, %s", types.OperationCreate, types.OperationUpdate, types.OperationDelete),
+		})
+	}
+
+	if op.Op != types.OperationCreate {
+		if failure := update.CheckAuthor(ctx, log, postBatcher, op.ID, login); failure != nil {
+			return failed(failure)
+		}
+	}
+
+	// как и в update, формат и видимость не проверяются
+	if len(checked.Title) == 0 && len(checked.Text) == 0 {
+		return checked, allow, nil
+	}
+
+	content := policy.Content{Author: login, Title: checked.Title, Text: checked.Text}
+	if op.Op == types.OperationUpdate {
+		content.PostID = op.ID
+	}
+
+	decision, failure := save.CheckContent(ctx, log, policyChecker, content)
+	if failure != nil {
+		return failed(failure)
+	}
+
+	return checked, decision, nil
+}
+
+// storageProblem возвращает ошибку операции, которую вернуло хранилище.
+func storageProblem(r *http.Request, op string, err error) *resp.Problem {
+	switch {
+	case errors.Is(err, storage.ErrMediaNotAvailable):
+		return problem(r, storage.ErrConflict, resp.CodeMediaNotAvailable, "media doesn't exist or is already attached")
+	case errors.Is(err, storage.ErrPostNotFound):
+		return problem(r, storage.ErrNotFound, resp.CodePostNotFound, "post doesn't exist")
+	case errors.Is(err, storage.ErrForbidden):
+		return problem(r, storage.ErrForbidden, resp.CodeNotPostAuthor, "invalid user")
+	default:
+		return problem(r, resp.ErrInternal, resp.CodeInternal, fmt.Sprintf("failed to %s post", op))
+	}
+}
+
+// applied делает после сохранения то же, что одиночные обработчики: помечает пост
+// для модерации, сохраняет упоминания и отправляет уведомления и события.
+func applied(ctx context.Context, log *slog.Logger, effects save.Effects, op types.PostOperation, id int64, decision policy.Decision, login string) {
+	date := time.Now().Format("2006-01-02 15:04:05")
+
+	switch op.Op {
+	case types.OperationCreate:
+		req := save.Request{Title: op.Title, Text: op.Text, Format: op.Format, Visibility: op.Visibility, Media: op.Media}
+
+		effects.Created(ctx, log, login, id, req, decision, date)
+	case types.OperationUpdate:
+		req := update.Request{ID: int(id), Title: op.Title, Text: op.Text, Format: op.Format, Visibility: op.Visibility}
+
+		update.Updated(ctx, log, effects, login, id, req, decision, date)
+	case types.OperationDelete:
+		remove.Removed(effects.Publisher, login, id)
+	}
+}
+
+func problem(r *http.Request, kind error, code string, msg string) *resp.Problem {
+	p := resp.NewProblem(r, kind, code, msg)
+
+	return &p
+}
+
+func countErrors(errs []error) int {
+	n := 0
+	for _, err := range errs {
+		if err != nil {
+			n++
+		}
+	}
+
+	return n
+}
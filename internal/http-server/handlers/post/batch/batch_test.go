@@ -0,0 +1,248 @@
+package batch_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/batch"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/batch/mocks"
+	"github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/broker"
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/solumD/go-blog-api/internal/lib/notifier"
+	"github.com/solumD/go-blog-api/internal/lib/policy"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type handlerMocks struct {
+	postBatcher   *mocks.PostBatcher
+	mentionSaver  *mocks.MentionSaver
+	policyChecker *mocks.PolicyChecker
+	postFlagger   *mocks.PostFlagger
+	postCounter   *mocks.PostCounter
+	notifier      *mocks.Notifier
+	publisher     *mocks.Publisher
+}
+
+func TestBatchHandler(t *testing.T) {
+	created := types.PostOperation{Op: types.OperationCreate, Title: "Very Cool Title", Text: "Very Cool Text", Format: "plain", Visibility: "public"}
+	deleted := types.PostOperation{Op: types.OperationDelete, ID: 7}
+
+	testCases := []struct {
+		name       string
+		input      string
+		setup      func(m handlerMocks)
+		statusCode int
+		// коды ошибок операций, пустой - операция выполнена
+		codes     []string
+		respError string
+	}{
+		{
+			name: "Success",
+			input: `{"operations": [
+				{"op": "create", "title": " Very Cool Title ", "text": "Very Cool Text"},
+				{"op": "delete", "id": 7}
+			]}`,
+			setup: func(m handlerMocks) {
+				m.policyChecker.On("Check", mock.Anything, policy.Content{Author: "test_user", Title: "Very Cool Title", Text: "Very Cool Text"}).
+					Return(policy.Decision{}, nil).Once()
+				m.postBatcher.On("GetPostCreator", mock.Anything, 7).Return("test_user", nil).Once()
+				m.postBatcher.On("ApplyPostOperations", mock.Anything, "test_user", []types.PostOperation{created, deleted}, true, mock.AnythingOfType("string")).
+					Return([]int64{1, 7}, []error{nil, nil}, nil).Once()
+				m.postCounter.On("PostCreated").Once()
+				m.notifier.On("Notify", notifier.Event{Type: notifier.EventPost, Actor: "test_user", PostID: 1}).Once()
+				m.publisher.On("Publish", "user:test_user", broker.EventPostCreated, broker.PostData{PostID: 1, Author: "test_user"}).Once()
+				m.publisher.On("Publish", "post:7", broker.EventPostDeleted, broker.PostData{PostID: 7}).Once()
+				m.publisher.On("Publish", "user:test_user", broker.EventPostDeleted, broker.PostData{PostID: 7}).Once()
+			},
+			statusCode: http.StatusOK,
+			codes:      []string{"", ""},
+		},
+		{
+			name: "Atomic aborted by check",
+			input: `{"operations": [
+				{"op": "delete", "id": 7},
+				{"op": "delete", "id": 8}
+			]}`,
+			setup: func(m handlerMocks) {
+				m.postBatcher.On("GetPostCreator", mock.Anything, 7).Return("test_user", nil).Once()
+				m.postBatcher.On("GetPostCreator", mock.Anything, 8).Return("other_user", nil).Once()
+			},
+			statusCode: http.StatusConflict,
+			codes:      []string{response.CodeBatchAborted, response.CodeNotPostAuthor},
+		},
+		{
+			name: "Atomic aborted by storage",
+			input: `{"operations": [
+				{"op": "delete", "id": 7},
+				{"op": "create", "title": "Very Cool Title", "text": "Very Cool Text", "media": [3]}
+			]}`,
+			setup: func(m handlerMocks) {
+				m.postBatcher.On("GetPostCreator", mock.Anything, 7).Return("test_user", nil).Once()
+				m.policyChecker.On("Check", mock.Anything, mock.Anything).Return(policy.Decision{}, nil).Once()
+				m.postBatcher.On("ApplyPostOperations", mock.Anything, "test_user", mock.Anything, true, mock.AnythingOfType("string")).
+					Return([]int64{7, 0}, []error{nil, fmt.Errorf("storage.sqlite.ApplyPostOperations: %w", storage.ErrMediaNotAvailable)}, nil).Once()
+			},
+			statusCode: http.StatusConflict,
+			codes:      []string{response.CodeBatchAborted, response.CodeMediaNotAvailable},
+		},
+		{
+			name: "Best effort",
+			input: `{"mode": "best_effort", "operations": [
+				{"op": "delete", "id": 7},
+				{"op": "update", "id": 9, "format": "html"},
+				{"op": "delete", "id": 10},
+				{"op": "repost", "id": 11}
+			]}`,
+			setup: func(m handlerMocks) {
+				m.postBatcher.On("GetPostCreator", mock.Anything, 7).Return("test_user", nil).Once()
+				m.postBatcher.On("GetPostCreator", mock.Anything, 10).Return("", storage.ErrPostNotFound).Once()
+				m.postBatcher.On("ApplyPostOperations", mock.Anything, "test_user", []types.PostOperation{deleted}, false, mock.AnythingOfType("string")).
+					Return([]int64{7}, []error{nil}, nil).Once()
+				m.publisher.On("Publish", mock.Anything, broker.EventPostDeleted, broker.PostData{PostID: 7}).Twice()
+			},
+			statusCode: http.StatusOK,
+			codes:      []string{"", response.CodeValidationFailed, response.CodePostNotFound, response.CodeValidationFailed},
+		},
+		{
+			name: "Rejected by policy",
+			input: `{"mode": "best_effort", "operations": [
+				{"op": "update", "id": 7, "text": "Very Cool Text"}
+			]}`,
+			setup: func(m handlerMocks) {
+				m.postBatcher.On("GetPostCreator", mock.Anything, 7).Return("test_user", nil).Once()
+				m.policyChecker.On("Check", mock.Anything, policy.Content{PostID: 7, Author: "test_user", Text: "Very Cool Text"}).
+					Return(policy.Decision{Verdict: policy.Reject, Rule: "blocklist", Reason: `post contains blocked word "cool"`}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+			codes:      []string{response.CodeContentRejected},
+		},
+		{
+			name:       "Invalid mode",
+			input:      `{"mode": "sometimes", "operations": [{"op": "delete", "id": 7}]}`,
+			statusCode: http.StatusBadRequest,
+			respError:  "mode must be atomic or best_effort",
+		},
+		{
+			name:       "Too many operations",
+			input:      `{"operations": [{"op": "delete", "id": 1}, {"op": "delete", "id": 2}, {"op": "delete", "id": 3}, {"op": "delete", "id": 4}, {"op": "delete", "id": 5}]}`,
+			statusCode: http.StatusBadRequest,
+			respError:  "batch must have from 1 to 4 operations",
+		},
+		{
+			name:       "No operations",
+			input:      `{"operations": []}`,
+			statusCode: http.StatusBadRequest,
+			respError:  "batch must have from 1 to 4 operations",
+		},
+		{
+			name:  "ApplyPostOperations Error",
+			input: `{"operations": [{"op": "delete", "id": 7}]}`,
+			setup: func(m handlerMocks) {
+				m.postBatcher.On("GetPostCreator", mock.Anything, 7).Return("test_user", nil).Once()
+				m.postBatcher.On("ApplyPostOperations", mock.Anything, "test_user", []types.PostOperation{deleted}, true, mock.AnythingOfType("string")).
+					Return(nil, nil, errors.New("unexpected error")).Once()
+			},
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to apply operations",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := handlerMocks{
+				postBatcher:   mocks.NewPostBatcher(t),
+				mentionSaver:  mocks.NewMentionSaver(t),
+				policyChecker: mocks.NewPolicyChecker(t),
+				postFlagger:   mocks.NewPostFlagger(t),
+				postCounter:   mocks.NewPostCounter(t),
+				notifier:      mocks.NewNotifier(t),
+				publisher:     mocks.NewPublisher(t),
+			}
+
+			if tc.setup != nil {
+				tc.setup(m)
+			}
+
+			handler := batch.New(4, loggerdiscard.NewDiscardLogger(), m.postBatcher, m.mentionSaver, m.policyChecker, m.postFlagger, m.postCounter, m.notifier, m.publisher)
+
+			req, err := http.NewRequest(http.MethodPost, "/v1/posts:batch", bytes.NewReader([]byte(tc.input)))
+			require.NoError(t, err)
+
+			req.Header.Add("login", "test_user")
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			require.Equal(t, tc.statusCode, recorder.Code)
+
+			body := recorder.Body.Bytes()
+
+			if tc.respError != "" {
+				var problem response.Problem
+
+				require.NoError(t, json.Unmarshal(body, &problem))
+
+				require.Equal(t, tc.respError, problem.Detail)
+
+				return
+			}
+
+			if tc.statusCode == http.StatusConflict {
+				require.Equal(t, response.ContentTypeProblem, recorder.Header().Get("Content-Type"))
+
+				var aborted struct {
+					response.Problem
+					Results []batch.Result `json:"results"`
+				}
+
+				require.NoError(t, json.Unmarshal(body, &aborted))
+				require.Equal(t, response.CodeBatchAborted, aborted.Code)
+				require.Equal(t, http.StatusConflict, aborted.Status)
+				require.Len(t, aborted.Results, len(tc.codes))
+
+				for i, code := range tc.codes {
+					require.NotNil(t, aborted.Results[i].Error, i)
+					require.Equal(t, code, aborted.Results[i].Error.Code, i)
+				}
+
+				return
+			}
+
+			var resp batch.Response
+
+			require.NoError(t, json.Unmarshal(body, &resp))
+			require.Len(t, resp.Results, len(tc.codes))
+
+			failed := false
+			for i, code := range tc.codes {
+				if code == "" {
+					require.Nil(t, resp.Results[i].Error, i)
+					continue
+				}
+
+				failed = true
+				require.NotNil(t, resp.Results[i].Error, i)
+				require.Equal(t, code, resp.Results[i].Error.Code, i)
+			}
+
+			if failed {
+				require.Equal(t, response.StatusError, resp.Status)
+			} else {
+				require.Equal(t, response.StatusOK, resp.Status)
+			}
+		})
+	}
+}
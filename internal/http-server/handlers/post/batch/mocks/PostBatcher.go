@@ -0,0 +1,88 @@
+// Code generated by mockery v2.30.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	types "github.com/solumD/go-blog-api/internal/types"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PostBatcher is an autogenerated mock type for the PostBatcher type
+type PostBatcher struct {
+	mock.Mock
+}
+
+// ApplyPostOperations provides a mock function with given fields: ctx, created_by, ops, atomic, date
+func (_m *PostBatcher) ApplyPostOperations(ctx context.Context, created_by string, ops []types.PostOperation, atomic bool, date string) ([]int64, []error, error) {
+	ret := _m.Called(ctx, created_by, ops, atomic, date)
+
+	var r0 []int64
+	var r1 []error
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []types.PostOperation, bool, string) ([]int64, []error, error)); ok {
+		return rf(ctx, created_by, ops, atomic, date)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []types.PostOperation, bool, string) []int64); ok {
+		r0 = rf(ctx, created_by, ops, atomic, date)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []types.PostOperation, bool, string) []error); ok {
+		r1 = rf(ctx, created_by, ops, atomic, date)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]error)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, []types.PostOperation, bool, string) error); ok {
+		r2 = rf(ctx, created_by, ops, atomic, date)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetPostCreator provides a mock function with given fields: ctx, id
+func (_m *PostBatcher) GetPostCreator(ctx context.Context, id int) (string, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (string, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) string); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPostBatcher creates a new instance of PostBatcher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPostBatcher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PostBatcher {
+	mock := &PostBatcher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
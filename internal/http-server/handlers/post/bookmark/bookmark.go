@@ -0,0 +1,130 @@
+package bookmark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/lib/validator"
+	"github.com/solumD/go-blog-api/internal/storage"
+)
+
+type Request struct {
+	Collection string `json:"collection,omitempty"`
+}
+
+type Response struct {
+	resp.Response
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=PostBookmarker
+type PostBookmarker interface {
+	CanViewPost(ctx context.Context, id int, viewer string) (bool, error)
+	BookmarkPost(ctx context.Context, id int, login string, collection string, date_created string) error
+}
+
+// @Summary     Bookmark
+// @Security    ApiKeyAuth
+// @Tags        post
+// @Description save a post to bookmarks; bookmarking it again moves it to another collection
+// @ID          bookmark
+// @Accept      json
+// @Produde     json
+// @Param       id          path     int     true  "id of post to be bookmarked"
+// @Param       input       body     Request false "name of a collection, empty by default"
+// @Success     200         {object} models.BookmarkSuccess
+// @Failure     400,404,500 {object} models.Problem
+// @Router      /v1/posts/{id}/bookmark [put]
+func New(log *slog.Logger, postBookmarker PostBookmarker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.post.bookmark.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidID, "invalid post id")
+
+			return
+		}
+
+		// тело запроса необязательное: без него пост попадает в закладки без коллекции
+		var req Request
+
+		err = render.DecodeJSON(r.Body, &req)
+		if err != nil && !errors.Is(err, io.EOF) {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidJSON, "failed to decode request")
+
+			return
+		}
+
+		req.Collection = strings.TrimSpace(req.Collection)
+
+		if err := validator.ValidateCollectionName(req.Collection); err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, err.Error())
+
+			return
+		}
+
+		login := r.Header.Get("login")
+
+		// добавить в закладки можно только видимый пользователю пост
+		visible, err := postBookmarker.CanViewPost(ctx, id, login)
+		if err != nil {
+			log.Error("failed to check if post is visible", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to check if post exists")
+
+			return
+		}
+
+		if !visible {
+			log.Error("invalid request", sl.Err(fmt.Errorf("post doesn't exist: %d", id)))
+
+			resp.Fail(w, r, storage.ErrNotFound, resp.CodePostNotFound, "post doesn't exist")
+
+			return
+		}
+
+		date_created := time.Now().Format("2006-01-02 15:04:05")
+
+		if err := postBookmarker.BookmarkPost(ctx, id, login, req.Collection, date_created); err != nil {
+			log.Error("failed to bookmark post", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to bookmark post")
+
+			return
+		}
+
+		log.Info("post bookmarked", slog.Int("id", id), slog.String("collection", req.Collection))
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package bookmark_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/bookmark"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/bookmark/mocks"
+	"github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookmarkHandler(t *testing.T) {
+	testCases := []struct {
+		name       string
+		id         string
+		body       string
+		collection string
+		visible    bool
+		respError  string
+		mockError  error
+		statusCode int
+	}{
+		{
+			name:       "Success without body",
+			id:         "1",
+			visible:    true,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Success with collection",
+			id:         "1",
+			body:       `{"collection": " read later "}`,
+			collection: "read later",
+			visible:    true,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid id",
+			id:         "abc",
+			respError:  "invalid post id",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Too long collection",
+			id:         "1",
+			body:       `{"collection": "` + strings.Repeat("a", 51) + `"}`,
+			respError:  "collection name cannot be longer than 50 characters",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Hidden post",
+			id:         "1",
+			respError:  "post doesn't exist",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "BookmarkPost Error",
+			id:         "1",
+			visible:    true,
+			respError:  "failed to bookmark post",
+			mockError:  errors.New("unexpected error"),
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			postBookmarkerMock := mocks.NewPostBookmarker(t)
+
+			if tc.visible || tc.statusCode == http.StatusNotFound {
+				postBookmarkerMock.On("CanViewPost", mock.Anything, 1, "test_user").
+					Return(tc.visible, nil).
+					Once()
+			}
+
+			if tc.visible {
+				postBookmarkerMock.On("BookmarkPost", mock.Anything, 1, "test_user", tc.collection, mock.AnythingOfType("string")).
+					Return(tc.mockError).
+					Once()
+			}
+
+			router := chi.NewRouter()
+			router.Put("/post/{id}/bookmark", bookmark.New(loggerdiscard.NewDiscardLogger(), postBookmarkerMock))
+
+			req, err := http.NewRequest(http.MethodPut, "/post/"+tc.id+"/bookmark", strings.NewReader(tc.body))
+			require.NoError(t, err)
+
+			req.Header.Add("login", "test_user")
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			require.Equal(t, tc.statusCode, recorder.Code)
+
+			if tc.respError != "" {
+				var problem response.Problem
+
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+
+				require.Equal(t, tc.respError, problem.Detail)
+
+				return
+			}
+
+			var resp bookmark.Response
+
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+
+			require.Equal(t, response.StatusOK, resp.Status)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Code generated by mockery v2.30.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PostBookmarker is an autogenerated mock type for the PostBookmarker type
+type PostBookmarker struct {
+	mock.Mock
+}
+
+// BookmarkPost provides a mock function with given fields: ctx, id, login, collection, date_created
+func (_m *PostBookmarker) BookmarkPost(ctx context.Context, id int, login string, collection string, date_created string) error {
+	ret := _m.Called(ctx, id, login, collection, date_created)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string, string) error); ok {
+		r0 = rf(ctx, id, login, collection, date_created)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CanViewPost provides a mock function with given fields: ctx, id, viewer
+func (_m *PostBookmarker) CanViewPost(ctx context.Context, id int, viewer string) (bool, error) {
+	ret := _m.Called(ctx, id, viewer)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (bool, error)); ok {
+		return rf(ctx, id, viewer)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) bool); ok {
+		r0 = rf(ctx, id, viewer)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, id, viewer)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPostBookmarker creates a new instance of PostBookmarker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPostBookmarker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PostBookmarker {
+	mock := &PostBookmarker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
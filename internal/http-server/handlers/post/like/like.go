@@ -2,16 +2,23 @@ package like
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
 	_ "github.com/solumD/go-blog-api/internal/http-server/models"
 	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/broker"
 	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/lib/notifier"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
 )
 
 type Request struct {
@@ -22,12 +29,37 @@ type Response struct {
 	resp.Response
 }
 
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=PostLiker
 type PostLiker interface {
-	IsPostExist(ctx context.Context, id int) (bool, error)
+	CanViewPost(ctx context.Context, id int, viewer string) (bool, error)
 	IsPostLikedByUser(ctx context.Context, id int, liked_by string) (bool, error)
 	LikePost(ctx context.Context, id int, liked_by string) error
 }
 
+// PostUnliker нужен Unlike, которую вызывают обработчик unlike и мутация GraphQL.
+//
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=PostUnliker
+type PostUnliker interface {
+	CanViewPost(ctx context.Context, id int, viewer string) (bool, error)
+	IsPostLikedByUser(ctx context.Context, id int, liked_by string) (bool, error)
+	UnlikePost(ctx context.Context, id int, liked_by string) error
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=LikeCounter
+type LikeCounter interface {
+	PostLiked()
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=Notifier
+type Notifier interface {
+	Notify(e notifier.Event)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=Publisher
+type Publisher interface {
+	Publish(topic string, eventType string, data any)
+}
+
 // @Summary     Like
 // @Security    ApiKeyAuth
 // @Tags        post
@@ -35,87 +67,155 @@ type PostLiker interface {
 // @ID          like
 // @Accept      json
 // @Produde     json
-// @Param       input body     Request true "id of post to be liked"
+// @Param       id    path     int true "id of post to be liked"
 // @Success     200   {object} models.LikeSuccess
-// @Failure     400,500      {object} models.LikeError
-// @Router      /post/like [put]
-func New(ctx context.Context, log *slog.Logger, postLiker PostLiker) http.HandlerFunc {
+// @Failure     400,404,409,500 {object} models.Problem
+// @Router      /v1/posts/{id}/like [put]
+func New(log *slog.Logger, postLiker PostLiker, likeCounter LikeCounter, eventNotifier Notifier, eventPublisher Publisher) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const fn = "handlers.post.like.New"
 
-		log = log.With(
+		log := log.With(
 			slog.String("fn", fn),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
 		)
 
-		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 		defer cancel()
 
 		var req Request
+		var err error
+
+		// в /v1 ID поста в пути, в прежних маршрутах - в теле запроса
+		if id := chi.URLParam(r, "id"); id != "" {
+			req.ID, err = strconv.Atoi(id)
+			if err != nil {
+				log.Error("invalid request", sl.Err(err))
+
+				resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidID, "invalid post id")
 
-		err := render.DecodeJSON(r.Body, &req)
-		if err != nil {
+				return
+			}
+		} else if err = render.DecodeJSON(r.Body, &req); err != nil {
 			log.Error("failed to decode request body", sl.Err(err))
 
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to decode request"))
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidJSON, "failed to decode request")
 
 			return
 		}
 
-		log.Info("request body decoded", slog.Any("request", req))
+		log.Info("request decoded", slog.Any("request", req))
 
-		exist, err := postLiker.IsPostExist(ctx, req.ID)
-		if err != nil {
-			log.Error("failed to check if post exists", sl.Err(err))
+		login := r.Header.Get("login")
 
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to check if post exists"))
+		if failure := Like(ctx, log, postLiker, int64(req.ID), login); failure != nil {
+			resp.Fail(w, r, failure.Kind, failure.Code, failure.Msg)
 
 			return
 		}
 
-		if !exist {
-			log.Error("invalid request", sl.Err(fmt.Errorf("post doesn't exist")))
+		likeCounter.PostLiked()
 
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("post doesn't exist"))
+		// автор поста определяется уже при доставке уведомления
+		eventNotifier.Notify(notifier.Event{
+			Type:   types.NotificationLike,
+			Actor:  login,
+			PostID: int64(req.ID),
+		})
 
-			return
-		}
+		eventPublisher.Publish(broker.PostTopic(int64(req.ID)), broker.EventLike, broker.ReactionData{
+			PostID: int64(req.ID),
+			Login:  login,
+		})
 
-		login := r.Header.Get("login")
-		liked, err := postLiker.IsPostLikedByUser(ctx, req.ID, login)
-		if err != nil {
-			log.Error("failed to check if post liked by user", sl.Err(err))
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+		})
+	}
+}
 
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to check if post liked by user"))
+// Like ставит лайк пользователя login на пост id.
+func Like(ctx context.Context, log *slog.Logger, postLiker PostLiker, id int64, login string) *resp.Failure {
+	liked, failure := checkPost(ctx, log, postLiker, id, login)
+	if failure != nil {
+		return failure
+	}
 
-			return
-		}
+	if liked {
+		return alreadyLiked(log, id)
+	}
 
-		if liked {
-			log.Error("invalid request", sl.Err(fmt.Errorf("you have already liked post %d", req.ID)))
+	// лайк мог поставить параллельный запрос уже после проверки
+	err := postLiker.LikePost(ctx, int(id), login)
+	if errors.Is(err, storage.ErrAlreadyReacted) {
+		return alreadyLiked(log, id)
+	} else if err != nil {
+		log.Error("failed to like post", sl.Err(err))
 
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(fmt.Sprintf("you have already liked post %d", req.ID)))
+		return &resp.Failure{Kind: resp.ErrInternal, Code: resp.CodeInternal, Msg: "failed to like post"}
+	}
 
-			return
-		}
+	return nil
+}
 
-		err = postLiker.LikePost(ctx, req.ID, login)
-		if err != nil {
-			log.Error("failed to like post", sl.Err(err))
+// Unlike убирает лайк пользователя login с поста id.
+func Unlike(ctx context.Context, log *slog.Logger, postUnliker PostUnliker, id int64, login string) *resp.Failure {
+	liked, failure := checkPost(ctx, log, postUnliker, id, login)
+	if failure != nil {
+		return failure
+	}
 
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to like post"))
+	if !liked {
+		log.Error("invalid request", sl.Err(fmt.Errorf("you haven't liked post %d", id)))
 
-			return
-		}
+		return &resp.Failure{Kind: storage.ErrConflict, Code: resp.CodeNotLiked, Msg: fmt.Sprintf("you haven't liked post %d", id)}
+	}
 
-		render.JSON(w, r, Response{
-			Response: resp.OK(),
-		})
+	if err := postUnliker.UnlikePost(ctx, int(id), login); err != nil {
+		log.Error("failed to unlike post", sl.Err(err))
+
+		return &resp.Failure{Kind: resp.ErrInternal, Code: resp.CodeInternal, Msg: "failed to unlike post"}
+	}
+
+	return nil
+}
+
+// likeChecker - общая часть PostLiker и PostUnliker.
+type likeChecker interface {
+	CanViewPost(ctx context.Context, id int, viewer string) (bool, error)
+	IsPostLikedByUser(ctx context.Context, id int, liked_by string) (bool, error)
+}
+
+// checkPost проверяет, что пост id виден пользователю login, и возвращает, лайкнул ли он пост.
+// Скрытый от пользователя пост для него не существует, в том числе пост
+// пользователя, с которым есть блокировка.
+func checkPost(ctx context.Context, log *slog.Logger, checker likeChecker, id int64, login string) (bool, *resp.Failure) {
+	exist, err := checker.CanViewPost(ctx, int(id), login)
+	if err != nil {
+		log.Error("failed to check if post exists", sl.Err(err))
+
+		return false, &resp.Failure{Kind: resp.ErrInternal, Code: resp.CodeInternal, Msg: "failed to check if post exists"}
+	}
+
+	if !exist {
+		log.Error("invalid request", sl.Err(fmt.Errorf("post doesn't exist")))
+
+		return false, &resp.Failure{Kind: storage.ErrNotFound, Code: resp.CodePostNotFound, Msg: "post doesn't exist"}
 	}
+
+	liked, err := checker.IsPostLikedByUser(ctx, int(id), login)
+	if err != nil {
+		log.Error("failed to check if post liked by user", sl.Err(err))
+
+		return false, &resp.Failure{Kind: resp.ErrInternal, Code: resp.CodeInternal, Msg: "failed to check if post liked by user"}
+	}
+
+	return liked, nil
+}
+
+func alreadyLiked(log *slog.Logger, id int64) *resp.Failure {
+	log.Error("invalid request", sl.Err(fmt.Errorf("you have already liked post %d", id)))
+
+	return &resp.Failure{Kind: storage.ErrConflict, Code: resp.CodeAlreadyLiked, Msg: fmt.Sprintf("you have already liked post %d", id)}
 }
@@ -0,0 +1,250 @@
+package like_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/like"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/like/mocks"
+	"github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/broker"
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/solumD/go-blog-api/internal/lib/notifier"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLikeHandler(t *testing.T) {
+	testCases := []struct {
+		name       string
+		path       string
+		body       string
+		visible    bool
+		liked      bool
+		respError  string
+		mockError  error
+		statusCode int
+	}{
+		{
+			name:       "Success",
+			body:       `{"id": 1}`,
+			visible:    true,
+			statusCode: http.StatusOK,
+		},
+		{
+			// в /v1 ID поста в пути, тело не нужно
+			name:       "Success with id in path",
+			path:       "/v1/posts/1/like",
+			visible:    true,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid id in path",
+			path:       "/v1/posts/abc/like",
+			respError:  "invalid post id",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Invalid body",
+			body:       `{"id": "abc"}`,
+			respError:  "failed to decode request",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			// пост пользователя, с которым есть блокировка, скрыт так же, как приватный
+			name:       "Hidden or blocked post",
+			body:       `{"id": 1}`,
+			respError:  "post doesn't exist",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Already liked",
+			body:       `{"id": 1}`,
+			visible:    true,
+			liked:      true,
+			respError:  "you have already liked post 1",
+			statusCode: http.StatusConflict,
+		},
+		{
+			// одновременный такой же запрос успел поставить лайк после проверки
+			name:       "Liked concurrently",
+			body:       `{"id": 1}`,
+			visible:    true,
+			respError:  "you have already liked post 1",
+			mockError:  storage.ErrAlreadyReacted,
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "LikePost Error",
+			body:       `{"id": 1}`,
+			visible:    true,
+			respError:  "failed to like post",
+			mockError:  errors.New("unexpected error"),
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			postLikerMock := mocks.NewPostLiker(t)
+
+			if tc.visible || tc.respError == "post doesn't exist" {
+				postLikerMock.On("CanViewPost", mock.Anything, 1, "test_user").
+					Return(tc.visible, nil).
+					Once()
+			}
+
+			if tc.visible {
+				postLikerMock.On("IsPostLikedByUser", mock.Anything, 1, "test_user").
+					Return(tc.liked, nil).
+					Once()
+			}
+
+			if tc.visible && !tc.liked {
+				postLikerMock.On("LikePost", mock.Anything, 1, "test_user").
+					Return(tc.mockError).
+					Once()
+			}
+
+			likeCounterMock := mocks.NewLikeCounter(t)
+			notifierMock := mocks.NewNotifier(t)
+			publisherMock := mocks.NewPublisher(t)
+
+			if tc.statusCode == http.StatusOK {
+				likeCounterMock.On("PostLiked").Once()
+
+				notifierMock.On("Notify", notifier.Event{
+					Type:   types.NotificationLike,
+					Actor:  "test_user",
+					PostID: 1,
+				}).Once()
+
+				publisherMock.On("Publish", "post:1", broker.EventLike, broker.ReactionData{PostID: 1, Login: "test_user"}).Once()
+			}
+
+			handler := like.New(loggerdiscard.NewDiscardLogger(), postLikerMock, likeCounterMock, notifierMock, publisherMock)
+
+			router := chi.NewRouter()
+			router.Put("/post/like", handler)
+			router.Put("/v1/posts/{id}/like", handler)
+
+			path := tc.path
+			if path == "" {
+				path = "/post/like"
+			}
+
+			req, err := http.NewRequest(http.MethodPut, path, strings.NewReader(tc.body))
+			require.NoError(t, err)
+
+			req.Header.Add("login", "test_user")
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			require.Equal(t, tc.statusCode, recorder.Code)
+
+			if tc.respError != "" {
+				var problem response.Problem
+
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+
+				require.Equal(t, tc.respError, problem.Detail)
+
+				return
+			}
+
+			var resp like.Response
+
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+
+			require.Equal(t, response.StatusOK, resp.Status)
+		})
+	}
+}
+
+func TestUnlike(t *testing.T) {
+	testCases := []struct {
+		name      string
+		visible   bool
+		liked     bool
+		mockError error
+		code      string
+		kind      error
+	}{
+		{
+			name:    "Success",
+			visible: true,
+			liked:   true,
+		},
+		{
+			// как и лайк, скрытый пост для пользователя не существует
+			name: "Hidden or blocked post",
+			code: response.CodePostNotFound,
+			kind: storage.ErrNotFound,
+		},
+		{
+			name:    "Not liked",
+			visible: true,
+			code:    response.CodeNotLiked,
+			kind:    storage.ErrConflict,
+		},
+		{
+			name:      "UnlikePost Error",
+			visible:   true,
+			liked:     true,
+			mockError: errors.New("unexpected error"),
+			code:      response.CodeInternal,
+			kind:      response.ErrInternal,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			postUnlikerMock := mocks.NewPostUnliker(t)
+
+			postUnlikerMock.On("CanViewPost", mock.Anything, 1, "test_user").
+				Return(tc.visible, nil).
+				Once()
+
+			if tc.visible {
+				postUnlikerMock.On("IsPostLikedByUser", mock.Anything, 1, "test_user").
+					Return(tc.liked, nil).
+					Once()
+			}
+
+			if tc.liked {
+				postUnlikerMock.On("UnlikePost", mock.Anything, 1, "test_user").
+					Return(tc.mockError).
+					Once()
+			}
+
+			failure := like.Unlike(context.Background(), loggerdiscard.NewDiscardLogger(), postUnlikerMock, 1, "test_user")
+
+			if tc.code == "" {
+				require.Nil(t, failure)
+
+				return
+			}
+
+			require.NotNil(t, failure)
+			require.Equal(t, tc.code, failure.Code)
+			require.ErrorIs(t, failure.Kind, tc.kind)
+		})
+	}
+}
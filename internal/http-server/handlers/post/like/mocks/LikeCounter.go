@@ -0,0 +1,29 @@
+// Code generated by mockery v2.30.0. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// LikeCounter is an autogenerated mock type for the LikeCounter type
+type LikeCounter struct {
+	mock.Mock
+}
+
+// PostLiked provides a mock function with given fields:
+func (_m *LikeCounter) PostLiked() {
+	_m.Called()
+}
+
+// NewLikeCounter creates a new instance of LikeCounter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLikeCounter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LikeCounter {
+	mock := &LikeCounter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,90 @@
+// Code generated by mockery v2.30.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PostUnliker is an autogenerated mock type for the PostUnliker type
+type PostUnliker struct {
+	mock.Mock
+}
+
+// CanViewPost provides a mock function with given fields: ctx, id, viewer
+func (_m *PostUnliker) CanViewPost(ctx context.Context, id int, viewer string) (bool, error) {
+	ret := _m.Called(ctx, id, viewer)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (bool, error)); ok {
+		return rf(ctx, id, viewer)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) bool); ok {
+		r0 = rf(ctx, id, viewer)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, id, viewer)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsPostLikedByUser provides a mock function with given fields: ctx, id, liked_by
+func (_m *PostUnliker) IsPostLikedByUser(ctx context.Context, id int, liked_by string) (bool, error) {
+	ret := _m.Called(ctx, id, liked_by)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (bool, error)); ok {
+		return rf(ctx, id, liked_by)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) bool); ok {
+		r0 = rf(ctx, id, liked_by)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, id, liked_by)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnlikePost provides a mock function with given fields: ctx, id, liked_by
+func (_m *PostUnliker) UnlikePost(ctx context.Context, id int, liked_by string) error {
+	ret := _m.Called(ctx, id, liked_by)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, id, liked_by)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPostUnliker creates a new instance of PostUnliker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPostUnliker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PostUnliker {
+	mock := &PostUnliker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
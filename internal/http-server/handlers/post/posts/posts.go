@@ -14,6 +14,7 @@ import (
 	_ "github.com/solumD/go-blog-api/internal/http-server/models"
 	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
 	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/storage"
 	"github.com/solumD/go-blog-api/internal/types"
 )
 
@@ -25,29 +26,35 @@ type Response struct {
 
 type PostsGetter interface {
 	IsUserExist(ctx context.Context, login string) (bool, error)
-	GetPosts(ctx context.Context, created_by string) (*types.UsersPosts, error)
+	GetPosts(ctx context.Context, created_by string, viewer string) (*types.UsersPosts, error)
+}
+
+type PostRenderer interface {
+	RenderPost(post *types.Post) error
 }
 
 // @Summary     Get posts
+// @Security    ApiKeyAuth
 // @Tags        user
-// @Description get posts of a user
+// @Description get posts of a user visible to the viewer; authorization is optional
 // @ID          get
 // @Accept      json
 // @Produde     json
 // @Param       user    path     string true "username of a user"
 // @Success     200     {object} models.PostsSuccess
-// @Failure     400,500 {object} models.PostsError
-// @Router      /user/{user} [get]
-func New(ctx context.Context, log *slog.Logger, postsGetter PostsGetter) http.HandlerFunc {
+// @Failure     404,500 {object} models.Problem
+// @Router      /v1/user/{user} [get]
+func New(log *slog.Logger, postsGetter PostsGetter, postRenderer PostRenderer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const fn = "handlers.post.posts.New"
 
-		log = log.With(
+		log := log.With(
 			slog.String("fn", fn),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
 		)
 
-		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 		defer cancel()
 
 		// получаем логин пользователя из параметров запроса
@@ -57,27 +64,27 @@ func New(ctx context.Context, log *slog.Logger, postsGetter PostsGetter) http.Ha
 		if err != nil {
 			log.Error("failed to check if user exists", sl.Err(err))
 
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to check if user exists"))
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to check if user exists")
 
 			return
 		}
 		if !exist {
 			log.Error("invalid request", sl.Err(fmt.Errorf("user doesn't exist: %s", login)))
 
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("user doesn't exist"))
+			resp.Fail(w, r, storage.ErrNotFound, resp.CodeUserNotFound, "user doesn't exist")
 
 			return
 		}
 
-		// получаем посты пользователя
-		posts, err := postsGetter.GetPosts(ctx, login)
+		// получаем посты пользователя, которые видны тому, кто их запрашивает;
+		// у анонимного запроса логин пустой
+		viewer := r.Header.Get("login")
+
+		posts, err := postsGetter.GetPosts(ctx, login, viewer)
 		if err != nil {
 			log.Error("failed to get users's posts", sl.Err(err))
 
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to get users's posts"))
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to get users's posts")
 
 			return
 		}
@@ -94,6 +101,17 @@ func New(ctx context.Context, log *slog.Logger, postsGetter PostsGetter) http.Ha
 			return
 		}
 
+		// рендерим HTML для каждого поста в соответствии с его форматом
+		for i := range posts.Posts {
+			if err := postRenderer.RenderPost(&posts.Posts[i]); err != nil {
+				log.Error("failed to render post", slog.Int64("id", posts.Posts[i].ID), sl.Err(err))
+
+				resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to render post")
+
+				return
+			}
+		}
+
 		log.Info("user's posts got", slog.String("user", login))
 
 		render.JSON(w, r, Response{
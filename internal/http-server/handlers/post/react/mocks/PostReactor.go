@@ -0,0 +1,90 @@
+// Code generated by mockery v2.30.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PostReactor is an autogenerated mock type for the PostReactor type
+type PostReactor struct {
+	mock.Mock
+}
+
+// AddReaction provides a mock function with given fields: ctx, id, login, kind
+func (_m *PostReactor) AddReaction(ctx context.Context, id int, login string, kind string) error {
+	ret := _m.Called(ctx, id, login, kind)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) error); ok {
+		r0 = rf(ctx, id, login, kind)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CanViewPost provides a mock function with given fields: ctx, id, viewer
+func (_m *PostReactor) CanViewPost(ctx context.Context, id int, viewer string) (bool, error) {
+	ret := _m.Called(ctx, id, viewer)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (bool, error)); ok {
+		return rf(ctx, id, viewer)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) bool); ok {
+		r0 = rf(ctx, id, viewer)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, id, viewer)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsPostReactedByUser provides a mock function with given fields: ctx, id, login, kind
+func (_m *PostReactor) IsPostReactedByUser(ctx context.Context, id int, login string, kind string) (bool, error) {
+	ret := _m.Called(ctx, id, login, kind)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) (bool, error)); ok {
+		return rf(ctx, id, login, kind)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) bool); ok {
+		r0 = rf(ctx, id, login, kind)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, string) error); ok {
+		r1 = rf(ctx, id, login, kind)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPostReactor creates a new instance of PostReactor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPostReactor(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PostReactor {
+	mock := &PostReactor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
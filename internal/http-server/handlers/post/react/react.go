@@ -0,0 +1,172 @@
+package react
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/broker"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/lib/notifier"
+	"github.com/solumD/go-blog-api/internal/lib/validator"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+type Response struct {
+	resp.Response
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=PostReactor
+type PostReactor interface {
+	CanViewPost(ctx context.Context, id int, viewer string) (bool, error)
+	IsPostReactedByUser(ctx context.Context, id int, login string, kind string) (bool, error)
+	AddReaction(ctx context.Context, id int, login string, kind string) error
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=LikeCounter
+type LikeCounter interface {
+	PostLiked()
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=Notifier
+type Notifier interface {
+	Notify(e notifier.Event)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=Publisher
+type Publisher interface {
+	Publish(topic string, eventType string, data any)
+}
+
+// @Summary     React
+// @Security    ApiKeyAuth
+// @Tags        post
+// @Description react to a post; kinds of reactions are set in the config,
+// @Description a reaction of kind "like" is the same as PUT /post/like
+// @ID          react
+// @Accept      json
+// @Produde     json
+// @Param       id          path     int    true "id of a post"
+// @Param       kind        path     string true "kind of a reaction"
+// @Success     200         {object} models.ReactSuccess
+// @Failure     400,404,409,500 {object} models.Problem
+// @Router      /v1/posts/{id}/reactions/{kind} [put]
+func New(kinds []string, log *slog.Logger, postReactor PostReactor, likeCounter LikeCounter, eventNotifier Notifier, eventPublisher Publisher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.post.react.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidID, "invalid post id")
+
+			return
+		}
+
+		kind := chi.URLParam(r, "kind")
+		if err := validator.ValidateReaction(kind, kinds); err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, err.Error())
+
+			return
+		}
+
+		login := r.Header.Get("login")
+
+		// реагировать можно только на видимый пользователю пост
+		visible, err := postReactor.CanViewPost(ctx, id, login)
+		if err != nil {
+			log.Error("failed to check if post is visible", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to check if post exists")
+
+			return
+		}
+
+		if !visible {
+			log.Error("invalid request", sl.Err(fmt.Errorf("post doesn't exist: %d", id)))
+
+			resp.Fail(w, r, storage.ErrNotFound, resp.CodePostNotFound, "post doesn't exist")
+
+			return
+		}
+
+		reacted, err := postReactor.IsPostReactedByUser(ctx, id, login, kind)
+		if err != nil {
+			log.Error("failed to check if post has user's reaction", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to check if post has your reaction")
+
+			return
+		}
+
+		if reacted {
+			log.Error("invalid request", sl.Err(fmt.Errorf("you have already reacted with %s to post %d", kind, id)))
+
+			resp.Fail(w, r, storage.ErrConflict, resp.CodeAlreadyReacted, fmt.Sprintf("you have already reacted with %s to post %d", kind, id))
+
+			return
+		}
+
+		err = postReactor.AddReaction(ctx, id, login, kind)
+		if errors.Is(err, storage.ErrAlreadyReacted) {
+			log.Error("invalid request", sl.Err(fmt.Errorf("you have already reacted with %s to post %d", kind, id)))
+
+			resp.Fail(w, r, storage.ErrConflict, resp.CodeAlreadyReacted, fmt.Sprintf("you have already reacted with %s to post %d", kind, id))
+
+			return
+		} else if err != nil {
+			log.Error("failed to add reaction", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to add reaction")
+
+			return
+		}
+
+		notificationType := types.NotificationReaction
+		if kind == types.ReactionLike {
+			notificationType = types.NotificationLike
+			likeCounter.PostLiked()
+		}
+
+		eventNotifier.Notify(notifier.Event{
+			Type:   notificationType,
+			Actor:  login,
+			PostID: int64(id),
+		})
+
+		log.Info("reaction added", slog.Int("id", id), slog.String("kind", kind))
+
+		eventType, data := broker.EventLike, broker.ReactionData{PostID: int64(id), Login: login}
+		if kind != types.ReactionLike {
+			eventType, data.Kind = broker.EventReaction, kind
+		}
+
+		eventPublisher.Publish(broker.PostTopic(int64(id)), eventType, data)
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package react_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/react"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/react/mocks"
+	"github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/broker"
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/solumD/go-blog-api/internal/lib/notifier"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var kinds = []string{"like", "love", "laugh"}
+
+func TestReactHandler(t *testing.T) {
+	testCases := []struct {
+		name         string
+		id           string
+		kind         string
+		visible      bool
+		reacted      bool
+		notification string
+		respError    string
+		mockError    error
+		statusCode   int
+	}{
+		{
+			name:         "Success",
+			id:           "1",
+			kind:         "love",
+			visible:      true,
+			notification: "reaction",
+			statusCode:   http.StatusOK,
+		},
+		{
+			name:         "Success like",
+			id:           "1",
+			kind:         "like",
+			visible:      true,
+			notification: "like",
+			statusCode:   http.StatusOK,
+		},
+		{
+			name:       "Invalid id",
+			id:         "abc",
+			kind:       "love",
+			respError:  "invalid post id",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Unknown kind",
+			id:         "1",
+			kind:       "angry",
+			respError:  "reaction must be one of: like, love, laugh",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Hidden post",
+			id:         "1",
+			kind:       "love",
+			respError:  "post doesn't exist",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Already reacted",
+			id:         "1",
+			kind:       "like",
+			visible:    true,
+			reacted:    true,
+			respError:  "you have already reacted with like to post 1",
+			statusCode: http.StatusConflict,
+		},
+		{
+			// одновременный такой же запрос успел сохранить реакцию после проверки
+			name:       "Reacted concurrently",
+			id:         "1",
+			kind:       "love",
+			visible:    true,
+			respError:  "you have already reacted with love to post 1",
+			mockError:  storage.ErrAlreadyReacted,
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "AddReaction Error",
+			id:         "1",
+			kind:       "laugh",
+			visible:    true,
+			respError:  "failed to add reaction",
+			mockError:  errors.New("unexpected error"),
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			postReactorMock := mocks.NewPostReactor(t)
+
+			if tc.visible || tc.statusCode == http.StatusNotFound {
+				postReactorMock.On("CanViewPost", mock.Anything, 1, "test_user").
+					Return(tc.visible, nil).
+					Once()
+			}
+
+			if tc.visible {
+				postReactorMock.On("IsPostReactedByUser", mock.Anything, 1, "test_user", tc.kind).
+					Return(tc.reacted, nil).
+					Once()
+			}
+
+			if tc.visible && !tc.reacted {
+				postReactorMock.On("AddReaction", mock.Anything, 1, "test_user", tc.kind).
+					Return(tc.mockError).
+					Once()
+			}
+
+			likeCounterMock := mocks.NewLikeCounter(t)
+
+			if tc.statusCode == http.StatusOK && tc.kind == types.ReactionLike {
+				likeCounterMock.On("PostLiked").Once()
+			}
+
+			notifierMock := mocks.NewNotifier(t)
+
+			if tc.statusCode == http.StatusOK {
+				notifierMock.On("Notify", notifier.Event{
+					Type:   tc.notification,
+					Actor:  "test_user",
+					PostID: 1,
+				}).Once()
+			}
+
+			publisherMock := mocks.NewPublisher(t)
+
+			if tc.statusCode == http.StatusOK {
+				eventType, data := broker.EventLike, broker.ReactionData{PostID: 1, Login: "test_user"}
+				if tc.kind != types.ReactionLike {
+					eventType, data.Kind = broker.EventReaction, tc.kind
+				}
+
+				publisherMock.On("Publish", "post:1", eventType, data).Once()
+			}
+
+			router := chi.NewRouter()
+			router.Put("/post/{id}/reactions/{kind}", react.New(kinds, loggerdiscard.NewDiscardLogger(), postReactorMock, likeCounterMock, notifierMock, publisherMock))
+
+			req, err := http.NewRequest(http.MethodPut, "/post/"+tc.id+"/reactions/"+tc.kind, nil)
+			require.NoError(t, err)
+
+			req.Header.Add("login", "test_user")
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			require.Equal(t, tc.statusCode, recorder.Code)
+
+			if tc.respError != "" {
+				var problem response.Problem
+
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+
+				require.Equal(t, tc.respError, problem.Detail)
+
+				return
+			}
+
+			var resp react.Response
+
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+
+			require.Equal(t, response.StatusOK, resp.Status)
+		})
+	}
+}
@@ -2,16 +2,18 @@ package remove
 
 import (
 	"context"
-	"database/sql"
-	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/update"
 	_ "github.com/solumD/go-blog-api/internal/http-server/models"
 	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/broker"
 	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
 )
 
@@ -28,6 +30,10 @@ type PostRemover interface {
 	RemovePost(ctx context.Context, id int) error
 }
 
+type Publisher interface {
+	Publish(topic string, eventType string, data any)
+}
+
 // @Summary     Delete
 // @Security    ApiKeyAuth
 // @Tags        post
@@ -35,59 +41,50 @@ type PostRemover interface {
 // @ID          delete
 // @Accept      json
 // @Produde     json
-// @Param       input   body     Request true "id of a post to be deleted"
+// @Param       id      path     int     true "id of a post to be deleted"
 // @Success     200     {object} models.DeleteSuccess
-// @Failure     400,500 {object} models.DeleteError
-// @Router      /post/delete [delete]
-func New(ctx context.Context, log *slog.Logger, postRemover PostRemover) http.HandlerFunc {
+// @Failure     400,403,404,500 {object} models.Problem
+// @Router      /v1/posts/{id} [delete]
+func New(log *slog.Logger, postRemover PostRemover, eventPublisher Publisher) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const fn = "handlers.post.remove.New"
 
-		log = log.With(
+		log := log.With(
 			slog.String("fn", fn),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
 		)
 
-		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 		defer cancel()
 
 		var req Request
+		var err error
 
-		err := render.DecodeJSON(r.Body, &req)
-		if err != nil {
-			log.Error("failed to decode request body", sl.Err(err))
-
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to decode request"))
-
-			return
-		}
-
-		log.Info("request body decoded", slog.Any("request", req))
-
-		created_by, err := postRemover.GetPostCreator(ctx, req.ID)
-		if err == sql.ErrNoRows {
-			log.Error("invalid request", sl.Err(fmt.Errorf("post doesn't exist: %d", req.ID)))
+		// в /v1 ID поста в пути, в прежних маршрутах - в теле запроса
+		if id := chi.URLParam(r, "id"); id != "" {
+			req.ID, err = strconv.Atoi(id)
+			if err != nil {
+				log.Error("invalid request", sl.Err(err))
 
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("post doesn't exist"))
+				resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidID, "invalid post id")
 
-			return
-		} else if err != nil {
-			log.Error("failed to check if post exists", sl.Err(err))
+				return
+			}
+		} else if err = render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
 
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to check if post exists"))
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidJSON, "failed to decode request")
 
 			return
 		}
 
+		log.Info("request decoded", slog.Any("request", req))
+
 		login := r.Header.Get("login")
-		if created_by != login {
-			log.Error("invalid request", sl.Err(fmt.Errorf("invalid user: %s", login)))
 
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid user"))
+		if failure := update.CheckAuthor(ctx, log, postRemover, int64(req.ID), login); failure != nil {
+			resp.Fail(w, r, failure.Kind, failure.Code, failure.Msg)
 
 			return
 		}
@@ -96,16 +93,23 @@ func New(ctx context.Context, log *slog.Logger, postRemover PostRemover) http.Ha
 		if err != nil {
 			log.Error("failed to remove post", sl.Err(err))
 
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to remove post"))
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to remove post")
 
 			return
 		}
 
 		log.Info("post removed", slog.Int("id", req.ID))
 
+		Removed(eventPublisher, login, int64(req.ID))
+
 		render.JSON(w, r, Response{
 			Response: resp.OK(),
 		})
 	}
 }
+
+// Removed сообщает об удалении поста тем, кто смотрит пост, и тем, кто смотрит его автора.
+func Removed(eventPublisher Publisher, login string, id int64) {
+	eventPublisher.Publish(broker.PostTopic(id), broker.EventPostDeleted, broker.PostData{PostID: id})
+	eventPublisher.Publish(broker.UserTopic(login), broker.EventPostDeleted, broker.PostData{PostID: id})
+}
@@ -0,0 +1,145 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/lib/validator"
+	"github.com/solumD/go-blog-api/internal/storage"
+)
+
+type Request struct {
+	Reason string `json:"reason"`
+	Note   string `json:"note,omitempty"`
+}
+
+type Response struct {
+	resp.Response
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=PostReporter
+type PostReporter interface {
+	CanViewPost(ctx context.Context, id int, viewer string) (bool, error)
+	ReportPost(ctx context.Context, id int, reporter string, reason string, note string, date_created string) error
+}
+
+// @Summary     Report
+// @Security    ApiKeyAuth
+// @Tags        post
+// @Description report a post to moderators; reason is one of: spam, harassment, hate, violence, nudity,
+// @Description misinformation, other; a user can report a post only once
+// @ID          report
+// @Accept      json
+// @Produde     json
+// @Param       id          path     int     true "id of post to be reported"
+// @Param       input       body     Request true "reason of the report and an optional note, 500 characters at most"
+// @Success     200         {object} models.ReportSuccess
+// @Failure     400,404,409,500 {object} models.Problem
+// @Router      /v1/posts/{id}/report [post]
+func New(log *slog.Logger, postReporter PostReporter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.post.report.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidID, "invalid post id")
+
+			return
+		}
+
+		var req Request
+
+		err = render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidJSON, "failed to decode request")
+
+			return
+		}
+
+		req.Note = strings.TrimSpace(req.Note)
+
+		if err := validator.ValidateReportReason(req.Reason); err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, err.Error())
+
+			return
+		}
+
+		if err := validator.ValidateNote(req.Note); err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, err.Error())
+
+			return
+		}
+
+		login := r.Header.Get("login")
+
+		// пожаловаться можно только на видимый пользователю пост
+		visible, err := postReporter.CanViewPost(ctx, id, login)
+		if err != nil {
+			log.Error("failed to check if post is visible", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to check if post exists")
+
+			return
+		}
+
+		if !visible {
+			log.Error("invalid request", sl.Err(fmt.Errorf("post doesn't exist: %d", id)))
+
+			resp.Fail(w, r, storage.ErrNotFound, resp.CodePostNotFound, "post doesn't exist")
+
+			return
+		}
+
+		date_created := time.Now().Format("2006-01-02 15:04:05")
+
+		err = postReporter.ReportPost(ctx, id, login, req.Reason, req.Note, date_created)
+		if errors.Is(err, storage.ErrAlreadyReported) {
+			log.Error("invalid request", sl.Err(fmt.Errorf("you have already reported post %d", id)))
+
+			resp.Fail(w, r, storage.ErrConflict, resp.CodeAlreadyReported, fmt.Sprintf("you have already reported post %d", id))
+
+			return
+		} else if err != nil {
+			log.Error("failed to report post", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to report post")
+
+			return
+		}
+
+		log.Info("post reported", slog.Int("id", id), slog.String("reason", req.Reason))
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package report_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/report"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/report/mocks"
+	"github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportHandler(t *testing.T) {
+	testCases := []struct {
+		name       string
+		id         string
+		body       string
+		reason     string
+		note       string
+		visible    bool
+		respError  string
+		mockError  error
+		statusCode int
+	}{
+		{
+			name:       "Success",
+			id:         "1",
+			body:       `{"reason": "spam", "note": " buy now "}`,
+			reason:     "spam",
+			note:       "buy now",
+			visible:    true,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid id",
+			id:         "abc",
+			body:       `{"reason": "spam"}`,
+			respError:  "invalid post id",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Unknown reason",
+			id:         "1",
+			body:       `{"reason": "boring"}`,
+			respError:  "reason must be one of: spam, harassment, hate, violence, nudity, misinformation, other",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Too long note",
+			id:         "1",
+			body:       `{"reason": "other", "note": "` + strings.Repeat("a", 501) + `"}`,
+			respError:  "note cannot be longer than 500 characters",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Hidden post",
+			id:         "1",
+			body:       `{"reason": "spam"}`,
+			respError:  "post doesn't exist",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Already reported",
+			id:         "1",
+			body:       `{"reason": "hate"}`,
+			reason:     "hate",
+			visible:    true,
+			respError:  "you have already reported post 1",
+			mockError:  storage.ErrAlreadyReported,
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "ReportPost Error",
+			id:         "1",
+			body:       `{"reason": "hate"}`,
+			reason:     "hate",
+			visible:    true,
+			respError:  "failed to report post",
+			mockError:  errors.New("unexpected error"),
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			postReporterMock := mocks.NewPostReporter(t)
+
+			if tc.visible || tc.statusCode == http.StatusNotFound {
+				postReporterMock.On("CanViewPost", mock.Anything, 1, "test_user").
+					Return(tc.visible, nil).
+					Once()
+			}
+
+			if tc.visible {
+				postReporterMock.On("ReportPost", mock.Anything, 1, "test_user", tc.reason, tc.note, mock.AnythingOfType("string")).
+					Return(tc.mockError).
+					Once()
+			}
+
+			router := chi.NewRouter()
+			router.Post("/post/{id}/report", report.New(loggerdiscard.NewDiscardLogger(), postReporterMock))
+
+			req, err := http.NewRequest(http.MethodPost, "/post/"+tc.id+"/report", strings.NewReader(tc.body))
+			require.NoError(t, err)
+
+			req.Header.Add("login", "test_user")
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			require.Equal(t, tc.statusCode, recorder.Code)
+
+			if tc.respError != "" {
+				var problem response.Problem
+
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+
+				require.Equal(t, tc.respError, problem.Detail)
+
+				return
+			}
+
+			var resp report.Response
+
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+
+			require.Equal(t, response.StatusOK, resp.Status)
+		})
+	}
+}
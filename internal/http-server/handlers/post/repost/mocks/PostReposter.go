@@ -0,0 +1,104 @@
+// Code generated by mockery v2.30.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	types "github.com/solumD/go-blog-api/internal/types"
+)
+
+// PostReposter is an autogenerated mock type for the PostReposter type
+type PostReposter struct {
+	mock.Mock
+}
+
+// GetPost provides a mock function with given fields: ctx, id, viewer
+func (_m *PostReposter) GetPost(ctx context.Context, id int, viewer string) (*types.Post, error) {
+	ret := _m.Called(ctx, id, viewer)
+
+	var r0 *types.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (*types.Post, error)); ok {
+		return rf(ctx, id, viewer)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) *types.Post); ok {
+		r0 = rf(ctx, id, viewer)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, id, viewer)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsPostRepostedByUser provides a mock function with given fields: ctx, id, created_by
+func (_m *PostReposter) IsPostRepostedByUser(ctx context.Context, id int, created_by string) (bool, error) {
+	ret := _m.Called(ctx, id, created_by)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (bool, error)); ok {
+		return rf(ctx, id, created_by)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) bool); ok {
+		r0 = rf(ctx, id, created_by)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, id, created_by)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repost provides a mock function with given fields: ctx, id, created_by, text, format, visibility, date_created
+func (_m *PostReposter) Repost(ctx context.Context, id int, created_by string, text string, format string, visibility string, date_created string) (int64, error) {
+	ret := _m.Called(ctx, id, created_by, text, format, visibility, date_created)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string, string, string, string) (int64, error)); ok {
+		return rf(ctx, id, created_by, text, format, visibility, date_created)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string, string, string, string) int64); ok {
+		r0 = rf(ctx, id, created_by, text, format, visibility, date_created)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, string, string, string, string) error); ok {
+		r1 = rf(ctx, id, created_by, text, format, visibility, date_created)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPostReposter creates a new instance of PostReposter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPostReposter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PostReposter {
+	mock := &PostReposter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
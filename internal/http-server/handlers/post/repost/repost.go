@@ -0,0 +1,204 @@
+package repost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/broker"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/lib/validator"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+type Request struct {
+	Text       string `json:"text,omitempty"`
+	Format     string `json:"format,omitempty"`
+	Visibility string `json:"visibility,omitempty"`
+}
+
+type Response struct {
+	resp.Response
+	ID int64 `json:"id,omitempty"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=PostReposter
+type PostReposter interface {
+	GetPost(ctx context.Context, id int, viewer string) (*types.Post, error)
+	IsPostRepostedByUser(ctx context.Context, id int, created_by string) (bool, error)
+	Repost(ctx context.Context, id int, created_by string, text string, format string, visibility string, date_created string) (int64, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=Publisher
+type Publisher interface {
+	Publish(topic string, eventType string, data any)
+}
+
+// @Summary     Repost
+// @Security    ApiKeyAuth
+// @Tags        post
+// @Description share a public post; without text it is a plain repost, with text - a quote.
+// @Description reposting a plain repost shares its original post
+// @ID          repost
+// @Accept      json
+// @Produde     json
+// @Param       id          path     int     true  "id of post to be reposted"
+// @Param       input       body     Request false "text of a quote and its format and visibility"
+// @Success     200         {object} models.RepostSuccess
+// @Failure     400,403,404,409,500 {object} models.Problem
+// @Router      /v1/posts/{id}/repost [post]
+func New(log *slog.Logger, postReposter PostReposter, eventPublisher Publisher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.post.repost.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidID, "invalid post id")
+
+			return
+		}
+
+		// тело запроса необязательное: без него получается простой репост
+		var req Request
+
+		err = render.DecodeJSON(r.Body, &req)
+		if err != nil && !errors.Is(err, io.EOF) {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidJSON, "failed to decode request")
+
+			return
+		}
+
+		req.Text = strings.TrimSpace(req.Text)
+
+		if req.Format == "" {
+			req.Format = types.FormatPlain
+		}
+
+		if err := validator.ValidatePostFormat(req.Format); err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, err.Error())
+
+			return
+		}
+
+		if req.Visibility == "" {
+			req.Visibility = types.VisibilityPublic
+		}
+
+		if err := validator.ValidateVisibility(req.Visibility); err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, err.Error())
+
+			return
+		}
+
+		login := r.Header.Get("login")
+
+		original, err := postReposter.GetPost(ctx, id, login)
+		if errors.Is(err, storage.ErrPostNotFound) {
+			log.Error("invalid request", sl.Err(fmt.Errorf("post doesn't exist: %d", id)))
+
+			resp.Fail(w, r, storage.ErrNotFound, resp.CodePostNotFound, "post doesn't exist")
+
+			return
+		} else if err != nil {
+			log.Error("failed to get post", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to get post")
+
+			return
+		}
+
+		// простой репост сам по себе ничего не добавляет, поэтому репостим его оригинал
+		if original.IsPlainRepost() {
+			original = original.RepostOf
+		}
+
+		if original == nil || original.Tombstone {
+			log.Error("invalid request", sl.Err(fmt.Errorf("original post doesn't exist: %d", id)))
+
+			resp.Fail(w, r, storage.ErrNotFound, resp.CodePostNotFound, "post doesn't exist")
+
+			return
+		}
+
+		// иначе репост показал бы пост тем, от кого автор его скрыл
+		if original.Visibility != types.VisibilityPublic {
+			log.Error("invalid request", sl.Err(fmt.Errorf("post isn't public: %d", original.ID)))
+
+			resp.Fail(w, r, storage.ErrForbidden, resp.CodePostNotPublic, "only public posts can be reposted")
+
+			return
+		}
+
+		originalID := int(original.ID)
+
+		if req.Text == "" {
+			reposted, err := postReposter.IsPostRepostedByUser(ctx, originalID, login)
+			if err != nil {
+				log.Error("failed to check if post reposted by user", sl.Err(err))
+
+				resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to check if post reposted by user")
+
+				return
+			}
+
+			if reposted {
+				log.Error("invalid request", sl.Err(fmt.Errorf("you have already reposted post %d", originalID)))
+
+				resp.Fail(w, r, storage.ErrConflict, resp.CodeAlreadyReposted, fmt.Sprintf("you have already reposted post %d", originalID))
+
+				return
+			}
+		}
+
+		date_created := time.Now().Format("2006-01-02 15:04:05")
+
+		repostID, err := postReposter.Repost(ctx, originalID, login, req.Text, req.Format, req.Visibility, date_created)
+		if err != nil {
+			log.Error("failed to repost post", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to repost post")
+
+			return
+		}
+
+		log.Info("post reposted", slog.Int("original", originalID), slog.Int64("id", repostID))
+
+		if req.Visibility == types.VisibilityPublic {
+			eventPublisher.Publish(broker.UserTopic(login), broker.EventPostCreated, broker.PostData{PostID: repostID, Author: login})
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			ID:       repostID,
+		})
+	}
+}
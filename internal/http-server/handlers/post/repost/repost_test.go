@@ -0,0 +1,171 @@
+package repost_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/repost"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/repost/mocks"
+	"github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/broker"
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepostHandler(t *testing.T) {
+	public := &types.Post{ID: 1, Text: "text", Visibility: types.VisibilityPublic}
+
+	testCases := []struct {
+		name       string
+		body       string
+		post       *types.Post
+		getError   error
+		reposted   bool
+		repostOf   int
+		text       string
+		respError  string
+		mockError  error
+		statusCode int
+	}{
+		{
+			name:       "Plain repost",
+			post:       public,
+			repostOf:   1,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Quote",
+			body:       `{"text": " my thoughts "}`,
+			post:       public,
+			repostOf:   1,
+			text:       "my thoughts",
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "Repost of plain repost shares original",
+			post: &types.Post{
+				ID:         2,
+				Visibility: types.VisibilityPublic,
+				RepostOfID: 1,
+				RepostOf:   public,
+			},
+			repostOf:   1,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Missing post",
+			getError:   storage.ErrPostNotFound,
+			respError:  "post doesn't exist",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name: "Plain repost of deleted post",
+			post: &types.Post{
+				ID:         2,
+				Visibility: types.VisibilityPublic,
+				RepostOfID: 1,
+				RepostOf:   &types.Post{ID: 1, Tombstone: true},
+			},
+			respError:  "post doesn't exist",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Not public post",
+			post:       &types.Post{ID: 1, Text: "text", Visibility: types.VisibilityFollowers},
+			respError:  "only public posts can be reposted",
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "Already reposted",
+			post:       public,
+			reposted:   true,
+			respError:  "you have already reposted post 1",
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "Invalid visibility",
+			body:       `{"visibility": "everyone"}`,
+			respError:  "post's visibility must be one of: public, followers, unlisted, private",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Repost Error",
+			post:       public,
+			repostOf:   1,
+			respError:  "failed to repost post",
+			mockError:  errors.New("unexpected error"),
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			postReposterMock := mocks.NewPostReposter(t)
+
+			if tc.post != nil || tc.getError != nil {
+				postReposterMock.On("GetPost", mock.Anything, 1, "test_user").
+					Return(tc.post, tc.getError).
+					Once()
+			}
+
+			if tc.text == "" && (tc.repostOf != 0 || tc.reposted) {
+				postReposterMock.On("IsPostRepostedByUser", mock.Anything, 1, "test_user").
+					Return(tc.reposted, nil).
+					Once()
+			}
+
+			if tc.repostOf != 0 {
+				postReposterMock.On("Repost", mock.Anything, tc.repostOf, "test_user", tc.text, "plain", "public", mock.AnythingOfType("string")).
+					Return(int64(3), tc.mockError).
+					Once()
+			}
+
+			publisherMock := mocks.NewPublisher(t)
+
+			if tc.statusCode == http.StatusOK {
+				publisherMock.On("Publish", "user:test_user", broker.EventPostCreated, broker.PostData{PostID: 3, Author: "test_user"}).Once()
+			}
+
+			router := chi.NewRouter()
+			router.Post("/post/{id}/repost", repost.New(loggerdiscard.NewDiscardLogger(), postReposterMock, publisherMock))
+
+			req, err := http.NewRequest(http.MethodPost, "/post/1/repost", strings.NewReader(tc.body))
+			require.NoError(t, err)
+
+			req.Header.Add("login", "test_user")
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			require.Equal(t, tc.statusCode, recorder.Code)
+
+			if tc.respError != "" {
+				var problem response.Problem
+
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+
+				require.Equal(t, tc.respError, problem.Detail)
+
+				return
+			}
+
+			var resp repost.Response
+
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+
+			require.Equal(t, response.StatusOK, resp.Status)
+		})
+	}
+}
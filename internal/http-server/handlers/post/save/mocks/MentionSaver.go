@@ -0,0 +1,78 @@
+// Code generated by mockery v2.30.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MentionSaver is an autogenerated mock type for the MentionSaver type
+type MentionSaver struct {
+	mock.Mock
+}
+
+// IsUserExist provides a mock function with given fields: ctx, login
+func (_m *MentionSaver) IsUserExist(ctx context.Context, login string) (bool, error) {
+	ret := _m.Called(ctx, login)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, login)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, login)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, login)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetPostMentions provides a mock function with given fields: ctx, id, logins, date_created
+func (_m *MentionSaver) SetPostMentions(ctx context.Context, id int64, logins []string, date_created string) ([]string, error) {
+	ret := _m.Called(ctx, id, logins, date_created)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []string, string) ([]string, error)); ok {
+		return rf(ctx, id, logins, date_created)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []string, string) []string); ok {
+		r0 = rf(ctx, id, logins, date_created)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, []string, string) error); ok {
+		r1 = rf(ctx, id, logins, date_created)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMentionSaver creates a new instance of MentionSaver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMentionSaver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MentionSaver {
+	mock := &MentionSaver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
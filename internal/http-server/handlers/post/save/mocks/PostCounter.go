@@ -0,0 +1,29 @@
+// Code generated by mockery v2.30.0. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// PostCounter is an autogenerated mock type for the PostCounter type
+type PostCounter struct {
+	mock.Mock
+}
+
+// PostCreated provides a mock function with given fields:
+func (_m *PostCounter) PostCreated() {
+	_m.Called()
+}
+
+// NewPostCounter creates a new instance of PostCounter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPostCounter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PostCounter {
+	mock := &PostCounter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -13,23 +13,23 @@ type PostSaver struct {
 	mock.Mock
 }
 
-// SavePost provides a mock function with given fields: ctx, created_by, title, text, date_created
-func (_m *PostSaver) SavePost(ctx context.Context, created_by string, title string, text string, date_created string) (int64, error) {
-	ret := _m.Called(ctx, created_by, title, text, date_created)
+// SavePost provides a mock function with given fields: ctx, created_by, title, text, format, visibility, media, date_created
+func (_m *PostSaver) SavePost(ctx context.Context, created_by string, title string, text string, format string, visibility string, media []int64, date_created string) (int64, error) {
+	ret := _m.Called(ctx, created_by, title, text, format, visibility, media, date_created)
 
 	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) (int64, error)); ok {
-		return rf(ctx, created_by, title, text, date_created)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, string, []int64, string) (int64, error)); ok {
+		return rf(ctx, created_by, title, text, format, visibility, media, date_created)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) int64); ok {
-		r0 = rf(ctx, created_by, title, text, date_created)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, string, []int64, string) int64); ok {
+		r0 = rf(ctx, created_by, title, text, format, visibility, media, date_created)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) error); ok {
-		r1 = rf(ctx, created_by, title, text, date_created)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string, string, []int64, string) error); ok {
+		r1 = rf(ctx, created_by, title, text, format, visibility, media, date_created)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -3,6 +3,7 @@ package save
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"strings"
@@ -12,12 +13,25 @@ import (
 	"github.com/go-chi/render"
 	_ "github.com/solumD/go-blog-api/internal/http-server/models"
 	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/broker"
 	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/lib/mentions"
+	"github.com/solumD/go-blog-api/internal/lib/notifier"
+	"github.com/solumD/go-blog-api/internal/lib/policy"
+	"github.com/solumD/go-blog-api/internal/lib/validator"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
 )
 
+// MaxMedia - сколько загрузок можно прикрепить к одному посту
+const MaxMedia = 10
+
 type Request struct {
-	Title string `json:"title"`
-	Text  string `json:"text"`
+	Title      string  `json:"title"`
+	Text       string  `json:"text"`
+	Format     string  `json:"format,omitempty"`
+	Visibility string  `json:"visibility,omitempty"`
+	Media      []int64 `json:"media,omitempty"`
 }
 
 type Response struct {
@@ -27,7 +41,38 @@ type Response struct {
 
 //go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=PostSaver
 type PostSaver interface {
-	SavePost(ctx context.Context, created_by string, title string, text string, date_created string) (int64, error)
+	SavePost(ctx context.Context, created_by string, title string, text string, format string, visibility string, media []int64, date_created string) (int64, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=MentionSaver
+type MentionSaver interface {
+	IsUserExist(ctx context.Context, login string) (bool, error)
+	SetPostMentions(ctx context.Context, id int64, logins []string, date_created string) ([]string, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=PolicyChecker
+type PolicyChecker interface {
+	Check(ctx context.Context, c policy.Content) (policy.Decision, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=PostFlagger
+type PostFlagger interface {
+	ReportPost(ctx context.Context, id int, reporter string, reason string, note string, date_created string) error
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=PostCounter
+type PostCounter interface {
+	PostCreated()
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=Notifier
+type Notifier interface {
+	Notify(e notifier.Event)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=Publisher
+type Publisher interface {
+	Publish(topic string, eventType string, data any)
 }
 
 // @Summary     Create
@@ -39,18 +84,19 @@ type PostSaver interface {
 // @Produde     json
 // @Param       input   body     Request true "post info"
 // @Success     200     {object} models.SaveSuccess
-// @Failure     400,500 {object} models.SaveError
-// @Router      /post/create [post]
-func New(ctx context.Context, log *slog.Logger, postSaver PostSaver) http.HandlerFunc {
+// @Failure     400,409,500 {object} models.Problem
+// @Router      /v1/posts [post]
+func New(log *slog.Logger, postSaver PostSaver, mentionSaver MentionSaver, policyChecker PolicyChecker, postFlagger PostFlagger, postCounter PostCounter, eventNotifier Notifier, eventPublisher Publisher) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const fn = "handlers.post.save.New"
 
-		log = log.With(
+		log := log.With(
 			slog.String("fn", fn),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
 		)
 
-		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 		defer cancel()
 
 		var req Request
@@ -59,44 +105,194 @@ func New(ctx context.Context, log *slog.Logger, postSaver PostSaver) http.Handle
 		if err != nil {
 			log.Error("failed to decode request body", sl.Err(err))
 
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("failed to decode request"))
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidJSON, "failed to decode request")
 
 			return
 		}
 
-		req.Title = strings.TrimSpace(req.Title)
-		req.Text = strings.TrimSpace(req.Text)
-		log.Info("request body decoded", slog.Any("request", req))
-
-		if len(req.Title) == 0 || len(req.Text) == 0 {
-			log.Error("invalid request", sl.Err(errors.New("post's title and text can't be empty")))
+		if failure := Validate(&req); failure != nil {
+			log.Error("invalid request", sl.Err(failure))
 
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("post's title and text can't be empty"))
+			resp.Fail(w, r, failure.Kind, failure.Code, failure.Msg)
 
 			return
 		}
 
+		log.Info("request body decoded", slog.Any("request", req))
+
 		login := r.Header.Get("login")
 
+		decision, failure := CheckContent(ctx, log, policyChecker, policy.Content{Author: login, Title: req.Title, Text: req.Text})
+		if failure != nil {
+			resp.Fail(w, r, failure.Kind, failure.Code, failure.Msg)
+
+			return
+		}
+
 		date_created := time.Now().Format("2006-01-02 15:04:05")
 
-		id, err := postSaver.SavePost(ctx, login, req.Title, req.Text, date_created)
-		if err != nil {
+		id, err := postSaver.SavePost(ctx, login, req.Title, req.Text, req.Format, req.Visibility, req.Media, date_created)
+		if errors.Is(err, storage.ErrMediaNotAvailable) {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, storage.ErrConflict, resp.CodeMediaNotAvailable, "media doesn't exist or is already attached")
+
+			return
+		} else if err != nil {
 			log.Error("failed to save post", sl.Err(err))
 
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to save post"))
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to save post")
 
 			return
 		}
 
 		log.Info("post created", slog.Int64("id", id))
 
+		Effects{
+			MentionSaver: mentionSaver,
+			PostFlagger:  postFlagger,
+			PostCounter:  postCounter,
+			Notifier:     eventNotifier,
+			Publisher:    eventPublisher,
+		}.Created(ctx, log, login, id, req, decision, date_created)
+
 		render.JSON(w, r, Response{
 			Response: resp.OK(),
 			ID:       id,
 		})
 	}
 }
+
+// Validate проверяет новый пост: убирает пробелы по краям названия и текста
+// и задает формат и видимость по умолчанию.
+func Validate(req *Request) *resp.Failure {
+	req.Title = strings.TrimSpace(req.Title)
+	req.Text = strings.TrimSpace(req.Text)
+
+	if len(req.Title) == 0 || len(req.Text) == 0 {
+		return invalid("post's title and text can't be empty")
+	}
+
+	// по умолчанию пост считается обычным текстом
+	if req.Format == "" {
+		req.Format = types.FormatPlain
+	}
+
+	if err := validator.ValidatePostFormat(req.Format); err != nil {
+		return invalid(err.Error())
+	}
+
+	// по умолчанию пост виден всем
+	if req.Visibility == "" {
+		req.Visibility = types.VisibilityPublic
+	}
+
+	if err := validator.ValidateVisibility(req.Visibility); err != nil {
+		return invalid(err.Error())
+	}
+
+	if len(req.Media) > MaxMedia {
+		return invalid(fmt.Sprintf("post can't have more than %d media", MaxMedia))
+	}
+
+	return nil
+}
+
+func invalid(msg string) *resp.Failure {
+	return &resp.Failure{Kind: resp.ErrInvalid, Code: resp.CodeValidationFailed, Msg: msg}
+}
+
+// CheckContent проверяет название и текст поста политикой содержимого.
+// Отклоненный пост - ошибка с кодом content_rejected.
+func CheckContent(ctx context.Context, log *slog.Logger, policyChecker PolicyChecker, content policy.Content) (policy.Decision, *resp.Failure) {
+	decision, err := policyChecker.Check(ctx, content)
+	if err != nil {
+		log.Error("failed to check post", sl.Err(err))
+
+		return decision, &resp.Failure{Kind: resp.ErrInternal, Code: resp.CodeInternal, Msg: "failed to check post"}
+	}
+
+	if decision.Verdict == policy.Reject {
+		log.Error("post rejected", slog.String("rule", decision.Rule), slog.String("reason", decision.Reason))
+
+		return decision, &resp.Failure{Kind: resp.ErrInvalid, Code: resp.CodeContentRejected, Msg: decision.Reason}
+	}
+
+	return decision, nil
+}
+
+// Effects - то, что нужно после сохранения поста, одинаковое для обработчиков постов,
+// пакетных запросов и мутаций GraphQL.
+type Effects struct {
+	MentionSaver MentionSaver
+	PostFlagger  PostFlagger
+	// нужен только для новых постов
+	PostCounter PostCounter
+	Notifier    Notifier
+	Publisher   Publisher
+}
+
+// Created помечает новый пост для модерации, сохраняет упоминания
+// и отправляет уведомления и события о нем.
+func (e Effects) Created(ctx context.Context, log *slog.Logger, login string, id int64, req Request, decision policy.Decision, date_created string) {
+	e.PostCounter.PostCreated()
+
+	e.Flag(ctx, log, id, decision, date_created)
+
+	// подписчики видят публичные посты и посты для подписчиков
+	if req.Visibility == types.VisibilityPublic || req.Visibility == types.VisibilityFollowers {
+		e.Notifier.Notify(notifier.Event{
+			Type:   notifier.EventPost,
+			Actor:  login,
+			PostID: id,
+		})
+	}
+
+	e.Mention(ctx, log, id, req.Text, login, date_created, false)
+
+	// топик пользователя может слушать кто угодно, поэтому в него попадают только публичные посты
+	if req.Visibility == types.VisibilityPublic {
+		e.Publisher.Publish(broker.UserTopic(login), broker.EventPostCreated, broker.PostData{PostID: id, Author: login})
+	}
+}
+
+// Flag отправляет помеченный политикой пост в очередь модерации: пост публикуется,
+// но его проверит модератор. На пост можно пожаловаться только один раз,
+// поэтому повторная пометка не ошибка.
+func (e Effects) Flag(ctx context.Context, log *slog.Logger, id int64, decision policy.Decision, date string) {
+	if decision.Verdict != policy.Flag {
+		return
+	}
+
+	log.Info("post flagged", slog.Int64("id", id), slog.String("rule", decision.Rule), slog.String("reason", decision.Reason))
+
+	err := e.PostFlagger.ReportPost(ctx, int(id), policy.Reporter, decision.ReportReason(), decision.Note(), date)
+	if err != nil && !errors.Is(err, storage.ErrAlreadyReported) {
+		log.Error("failed to flag post", sl.Err(err))
+	}
+}
+
+// Mention сохраняет упоминания существующих пользователей в тексте поста
+// и уведомляет тех, кто упомянут в нем впервые. При изменении текста (replace)
+// упоминания заменяются, даже если новых нет, чтобы убранные стали неактивными.
+// Пост уже сохранен, поэтому ошибка с упоминаниями не делает запрос неуспешным.
+func (e Effects) Mention(ctx context.Context, log *slog.Logger, id int64, text string, author string, date string, replace bool) {
+	var mentioned []string
+
+	logins, err := mentions.Resolve(ctx, e.MentionSaver, text, author)
+	if err == nil && (len(logins) > 0 || replace) {
+		mentioned, err = e.MentionSaver.SetPostMentions(ctx, id, logins, date)
+	}
+	if err != nil {
+		log.Error("failed to save mentions", slog.Int64("id", id), sl.Err(err))
+	}
+
+	for _, recipient := range mentioned {
+		e.Notifier.Notify(notifier.Event{
+			Type:      types.NotificationMention,
+			Recipient: recipient,
+			Actor:     author,
+			PostID:    id,
+		})
+	}
+}
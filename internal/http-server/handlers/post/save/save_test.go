@@ -2,7 +2,6 @@ package save_test
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,7 +11,12 @@ import (
 
 	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/save"
 	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/save/mocks"
+	"github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/broker"
 	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/solumD/go-blog-api/internal/lib/notifier"
+	"github.com/solumD/go-blog-api/internal/lib/policy"
+	"github.com/solumD/go-blog-api/internal/types"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -22,6 +26,9 @@ func TestSavePostHandler(t *testing.T) {
 		name       string
 		title      string
 		text       string
+		format     string
+		mentions   []string
+		decision   policy.Decision
 		respError  string
 		mockError  error
 		statusCode int
@@ -32,6 +39,43 @@ func TestSavePostHandler(t *testing.T) {
 			text:       "Very Cool Text",
 			statusCode: http.StatusOK,
 		},
+		{
+			name:       "Success markdown",
+			title:      "Very Cool Title",
+			text:       "Very **Cool** Text",
+			format:     "markdown",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Success with mentions",
+			title:      "Very Cool Title",
+			text:       "Hi @cool_user and @ghost_user, says @test_user",
+			mentions:   []string{"cool_user"},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Flagged by policy",
+			title:      "Very Cool Title",
+			text:       "Very Cool Text",
+			decision:   policy.Decision{Verdict: policy.Flag, Rule: "links", Reason: "post has 3 links"},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Rejected by policy",
+			title:      "Very Cool Title",
+			text:       "Very Cool Text",
+			decision:   policy.Decision{Verdict: policy.Reject, Rule: "blocklist", Reason: `post contains blocked word "cool"`},
+			respError:  `post contains blocked word "cool"`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Invalid format",
+			title:      "Very Cool Title",
+			text:       "Very Cool Text",
+			format:     "html",
+			respError:  "post's format must be plain or markdown",
+			statusCode: http.StatusBadRequest,
+		},
 		{
 			name:       "Empty title",
 			title:      "",
@@ -64,15 +108,77 @@ func TestSavePostHandler(t *testing.T) {
 
 			postSaverMock := mocks.NewPostSaver(t)
 
+			format := tc.format
+			if format == "" {
+				format = "plain"
+			}
+
+			policyCheckerMock := mocks.NewPolicyChecker(t)
+
+			if tc.respError == "" || tc.mockError != nil || tc.decision.Verdict == policy.Reject {
+				policyCheckerMock.On("Check", mock.Anything, policy.Content{Author: "test_user", Title: tc.title, Text: tc.text}).
+					Return(tc.decision, nil).
+					Once()
+			}
+
+			postFlaggerMock := mocks.NewPostFlagger(t)
+
+			if tc.decision.Verdict == policy.Flag {
+				postFlaggerMock.On("ReportPost", mock.Anything, 1, policy.Reporter, types.ReportSpam, "links: post has 3 links", mock.AnythingOfType("string")).
+					Return(nil).
+					Once()
+			}
+
 			if tc.respError == "" || tc.mockError != nil {
-				postSaverMock.On("SavePost", mock.Anything, mock.AnythingOfType("string"), tc.title, tc.text, mock.AnythingOfType("string")).
+				postSaverMock.On("SavePost", mock.Anything, mock.AnythingOfType("string"), tc.title, tc.text, format, "public", []int64(nil), mock.AnythingOfType("string")).
 					Return(int64(1), tc.mockError).
 					Once()
 			}
 
-			handler := save.New(context.Background(), loggerdiscard.NewDiscardLogger(), postSaverMock)
+			mentionSaverMock := mocks.NewMentionSaver(t)
+
+			if tc.mentions != nil {
+				mentionSaverMock.On("IsUserExist", mock.Anything, "cool_user").Return(true, nil).Once()
+				mentionSaverMock.On("IsUserExist", mock.Anything, "ghost_user").Return(false, nil).Once()
+				mentionSaverMock.On("SetPostMentions", mock.Anything, int64(1), tc.mentions, mock.AnythingOfType("string")).
+					Return(tc.mentions, nil).
+					Once()
+			}
+
+			postCounterMock := mocks.NewPostCounter(t)
+
+			if tc.respError == "" {
+				postCounterMock.On("PostCreated").Once()
+			}
+
+			notifierMock := mocks.NewNotifier(t)
 
-			input := fmt.Sprintf(`{"title": "%s", "text": "%s"}`, tc.title, tc.text)
+			if tc.respError == "" {
+				notifierMock.On("Notify", notifier.Event{
+					Type:   notifier.EventPost,
+					Actor:  "test_user",
+					PostID: 1,
+				}).Once()
+			}
+
+			for _, recipient := range tc.mentions {
+				notifierMock.On("Notify", notifier.Event{
+					Type:      types.NotificationMention,
+					Recipient: recipient,
+					Actor:     "test_user",
+					PostID:    1,
+				}).Once()
+			}
+
+			publisherMock := mocks.NewPublisher(t)
+
+			if tc.respError == "" {
+				publisherMock.On("Publish", "user:test_user", broker.EventPostCreated, broker.PostData{PostID: 1, Author: "test_user"}).Once()
+			}
+
+			handler := save.New(loggerdiscard.NewDiscardLogger(), postSaverMock, mentionSaverMock, policyCheckerMock, postFlaggerMock, postCounterMock, notifierMock, publisherMock)
+
+			input := fmt.Sprintf(`{"title": "%s", "text": "%s", "format": "%s"}`, tc.title, tc.text, tc.format)
 
 			req, err := http.NewRequest(http.MethodPost, "/post/save", bytes.NewReader([]byte(input)))
 			require.NoError(t, err)
@@ -86,11 +192,21 @@ func TestSavePostHandler(t *testing.T) {
 
 			body := recorder.Body.String()
 
+			if tc.respError != "" {
+				var problem response.Problem
+
+				require.NoError(t, json.Unmarshal([]byte(body), &problem))
+
+				require.Equal(t, tc.respError, problem.Detail)
+
+				return
+			}
+
 			var resp save.Response
 
 			require.NoError(t, json.Unmarshal([]byte(body), &resp))
 
-			require.Equal(t, tc.respError, resp.Error)
+			require.Equal(t, response.StatusOK, resp.Status)
 		})
 	}
 }
@@ -0,0 +1,101 @@
+package single
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+type Response struct {
+	resp.Response
+	Post *types.Post `json:"post,omitempty"`
+}
+
+type PostGetter interface {
+	GetPost(ctx context.Context, id int, viewer string) (*types.Post, error)
+}
+
+type PostRenderer interface {
+	RenderPost(post *types.Post) error
+}
+
+// @Summary     Get post
+// @Security    ApiKeyAuth
+// @Tags        post
+// @Description get a single post if it is visible to the viewer; authorization is optional
+// @ID          get-post
+// @Accept      json
+// @Produde     json
+// @Param       id          path     int true "id of a post"
+// @Success     200         {object} models.PostSuccess
+// @Failure     400,404,500 {object} models.Problem
+// @Router      /v1/posts/{id} [get]
+func New(log *slog.Logger, postGetter PostGetter, postRenderer PostRenderer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.post.single.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidID, "invalid post id")
+
+			return
+		}
+
+		viewer := r.Header.Get("login")
+
+		// скрытый от пользователя пост для него не существует
+		post, err := postGetter.GetPost(ctx, id, viewer)
+		if errors.Is(err, storage.ErrPostNotFound) {
+			log.Error("invalid request", sl.Err(fmt.Errorf("post doesn't exist: %d", id)))
+
+			resp.Fail(w, r, storage.ErrNotFound, resp.CodePostNotFound, "post doesn't exist")
+
+			return
+		} else if err != nil {
+			log.Error("failed to get post", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to get post")
+
+			return
+		}
+
+		if err := postRenderer.RenderPost(post); err != nil {
+			log.Error("failed to render post", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to render post")
+
+			return
+		}
+
+		log.Info("post got", slog.Int("id", id))
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Post:     post,
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package unbookmark
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/storage"
+)
+
+type Response struct {
+	resp.Response
+}
+
+type PostUnbookmarker interface {
+	IsPostBookmarkedByUser(ctx context.Context, id int, login string) (bool, error)
+	UnbookmarkPost(ctx context.Context, id int, login string) error
+}
+
+// @Summary     Unbookmark
+// @Security    ApiKeyAuth
+// @Tags        post
+// @Description remove a post from bookmarks
+// @ID          unbookmark
+// @Accept      json
+// @Produde     json
+// @Param       id      path     int true "id of post to be removed from bookmarks"
+// @Success     200     {object} models.UnbookmarkSuccess
+// @Failure     400,409,500 {object} models.Problem
+// @Router      /v1/posts/{id}/bookmark [delete]
+func New(log *slog.Logger, postUnbookmarker PostUnbookmarker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.post.unbookmark.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidID, "invalid post id")
+
+			return
+		}
+
+		// закладку можно снять и с поста, который стал скрыт от пользователя
+		login := r.Header.Get("login")
+		bookmarked, err := postUnbookmarker.IsPostBookmarkedByUser(ctx, id, login)
+		if err != nil {
+			log.Error("failed to check if post bookmarked by user", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to check if post bookmarked by user")
+
+			return
+		}
+
+		if !bookmarked {
+			log.Error("invalid request", sl.Err(fmt.Errorf("you haven't bookmarked post %d", id)))
+
+			resp.Fail(w, r, storage.ErrConflict, resp.CodeNotBookmarked, fmt.Sprintf("you haven't bookmarked post %d", id))
+
+			return
+		}
+
+		if err := postUnbookmarker.UnbookmarkPost(ctx, id, login); err != nil {
+			log.Error("failed to unbookmark post", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to unbookmark post")
+
+			return
+		}
+
+		log.Info("post unbookmarked", slog.Int("id", id))
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+		})
+	}
+}
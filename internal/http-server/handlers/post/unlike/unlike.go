@@ -2,15 +2,18 @@ package unlike
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/like"
 	_ "github.com/solumD/go-blog-api/internal/http-server/models"
 	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/broker"
 	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
 )
 
@@ -23,11 +26,15 @@ type Response struct {
 }
 
 type PostUnLiker interface {
-	IsPostExist(ctx context.Context, id int) (bool, error)
+	CanViewPost(ctx context.Context, id int, viewer string) (bool, error)
 	IsPostLikedByUser(ctx context.Context, id int, liked_by string) (bool, error)
 	UnlikePost(ctx context.Context, id int, liked_by string) error
 }
 
+type Publisher interface {
+	Publish(topic string, eventType string, data any)
+}
+
 // @Summary     Unlike
 // @Security    ApiKeyAuth
 // @Tags        post
@@ -35,84 +42,58 @@ type PostUnLiker interface {
 // @ID          unlike
 // @Accept      json
 // @Produde     json
-// @Param       input   body     Request true "id of post to be unliked"
+// @Param       id      path     int true "id of post to be unliked"
 // @Success     200     {object} models.UnlikeSuccess
-// @Failure     400,500 {object} models.UnlikeError
-// @Router      /post/unlike [put]
-func New(ctx context.Context, log *slog.Logger, postUnliker PostUnLiker) http.HandlerFunc {
+// @Failure     400,404,409,500 {object} models.Problem
+// @Router      /v1/posts/{id}/like [delete]
+func New(log *slog.Logger, postUnliker PostUnLiker, eventPublisher Publisher) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const fn = "handlers.post.unlike.New"
 
-		log = log.With(
+		log := log.With(
 			slog.String("fn", fn),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
 		)
 
-		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 		defer cancel()
 
 		var req Request
+		var err error
 
-		err := render.DecodeJSON(r.Body, &req)
-		if err != nil {
-			log.Error("failed to decode request body", sl.Err(err))
-
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to decode request"))
+		// в /v1 ID поста в пути, в прежних маршрутах - в теле запроса
+		if id := chi.URLParam(r, "id"); id != "" {
+			req.ID, err = strconv.Atoi(id)
+			if err != nil {
+				log.Error("invalid request", sl.Err(err))
 
-			return
-		}
+				resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidID, "invalid post id")
 
-		log.Info("request body decoded", slog.Any("request", req))
-
-		exist, err := postUnliker.IsPostExist(ctx, req.ID)
-		if err != nil {
-			log.Error("failed to check if post exists", sl.Err(err))
+				return
+			}
+		} else if err = render.DecodeJSON(r.Body, &req); err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
 
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to check if post exists"))
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidJSON, "failed to decode request")
 
 			return
 		}
 
-		if !exist {
-			log.Error("invalid request", sl.Err(fmt.Errorf("post doesn't exist")))
-
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("post doesn't exist"))
-
-			return
-		}
+		log.Info("request decoded", slog.Any("request", req))
 
 		login := r.Header.Get("login")
-		liked, err := postUnliker.IsPostLikedByUser(ctx, req.ID, login)
-		if err != nil {
-			log.Error("failed to check if post liked by user", sl.Err(err))
-
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to check if post liked by user"))
-
-			return
-		}
-
-		if !liked {
-			log.Error("invalid request", sl.Err(fmt.Errorf("you haven't liked post %d", req.ID)))
 
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(fmt.Sprintf("you haven't liked post %d", req.ID)))
+		if failure := like.Unlike(ctx, log, postUnliker, int64(req.ID), login); failure != nil {
+			resp.Fail(w, r, failure.Kind, failure.Code, failure.Msg)
 
 			return
 		}
 
-		err = postUnliker.UnlikePost(ctx, req.ID, login)
-		if err != nil {
-			log.Error("failed to unlike post", sl.Err(err))
-
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to unlike post"))
-
-			return
-		}
+		eventPublisher.Publish(broker.PostTopic(int64(req.ID)), broker.EventUnlike, broker.ReactionData{
+			PostID: int64(req.ID),
+			Login:  login,
+		})
 
 		render.JSON(w, r, Response{
 			Response: resp.OK(),
@@ -0,0 +1,113 @@
+package unreact
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/broker"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+type Response struct {
+	resp.Response
+}
+
+type PostUnreactor interface {
+	IsPostReactedByUser(ctx context.Context, id int, login string, kind string) (bool, error)
+	RemoveReaction(ctx context.Context, id int, login string, kind string) error
+}
+
+type Publisher interface {
+	Publish(topic string, eventType string, data any)
+}
+
+// @Summary     Unreact
+// @Security    ApiKeyAuth
+// @Tags        post
+// @Description remove a reaction from a post
+// @ID          unreact
+// @Accept      json
+// @Produde     json
+// @Param       id      path     int    true "id of a post"
+// @Param       kind    path     string true "kind of a reaction"
+// @Success     200     {object} models.UnreactSuccess
+// @Failure     400,409,500 {object} models.Problem
+// @Router      /v1/posts/{id}/reactions/{kind} [delete]
+func New(log *slog.Logger, postUnreactor PostUnreactor, eventPublisher Publisher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.post.unreact.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidID, "invalid post id")
+
+			return
+		}
+
+		// вид реакции не сверяем с конфигом, чтобы можно было снять реакцию,
+		// которую убрали из списка разрешенных
+		kind := chi.URLParam(r, "kind")
+		login := r.Header.Get("login")
+
+		reacted, err := postUnreactor.IsPostReactedByUser(ctx, id, login, kind)
+		if err != nil {
+			log.Error("failed to check if post has user's reaction", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to check if post has your reaction")
+
+			return
+		}
+
+		if !reacted {
+			log.Error("invalid request", sl.Err(fmt.Errorf("you haven't reacted with %s to post %d", kind, id)))
+
+			resp.Fail(w, r, storage.ErrConflict, resp.CodeNotReacted, fmt.Sprintf("you haven't reacted with %s to post %d", kind, id))
+
+			return
+		}
+
+		if err := postUnreactor.RemoveReaction(ctx, id, login, kind); err != nil {
+			log.Error("failed to remove reaction", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to remove reaction")
+
+			return
+		}
+
+		log.Info("reaction removed", slog.Int("id", id), slog.String("kind", kind))
+
+		// снятый лайк выглядит для подписчиков так же, как снятый через /post/unlike
+		eventType, data := broker.EventUnlike, broker.ReactionData{PostID: int64(id), Login: login}
+		if kind != types.ReactionLike {
+			eventType, data.Kind = broker.EventUnreaction, kind
+		}
+
+		eventPublisher.Publish(broker.PostTopic(int64(id)), eventType, data)
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package unrepost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/broker"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/storage"
+)
+
+type Response struct {
+	resp.Response
+}
+
+type PostUnreposter interface {
+	GetRepostID(ctx context.Context, id int, created_by string) (int, error)
+	RemovePost(ctx context.Context, id int) error
+}
+
+type Publisher interface {
+	Publish(topic string, eventType string, data any)
+}
+
+// @Summary     Unrepost
+// @Security    ApiKeyAuth
+// @Tags        post
+// @Description undo a plain repost; quotes are deleted as usual posts
+// @ID          unrepost
+// @Accept      json
+// @Produde     json
+// @Param       id      path     int true "id of reposted post"
+// @Success     200     {object} models.UnrepostSuccess
+// @Failure     400,409,500 {object} models.Problem
+// @Router      /v1/posts/{id}/repost [delete]
+func New(log *slog.Logger, postUnreposter PostUnreposter, eventPublisher Publisher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.post.unrepost.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidID, "invalid post id")
+
+			return
+		}
+
+		login := r.Header.Get("login")
+
+		repostID, err := postUnreposter.GetRepostID(ctx, id, login)
+		if errors.Is(err, storage.ErrRepostNotFound) {
+			log.Error("invalid request", sl.Err(fmt.Errorf("you haven't reposted post %d", id)))
+
+			resp.Fail(w, r, storage.ErrConflict, resp.CodeNotReposted, fmt.Sprintf("you haven't reposted post %d", id))
+
+			return
+		} else if err != nil {
+			log.Error("failed to get repost", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to get repost")
+
+			return
+		}
+
+		if err := postUnreposter.RemovePost(ctx, repostID); err != nil {
+			log.Error("failed to remove repost", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to remove repost")
+
+			return
+		}
+
+		log.Info("repost removed", slog.Int("original", id), slog.Int("id", repostID))
+
+		eventPublisher.Publish(broker.PostTopic(int64(repostID)), broker.EventPostDeleted, broker.PostData{PostID: int64(repostID)})
+		eventPublisher.Publish(broker.UserTopic(login), broker.EventPostDeleted, broker.PostData{PostID: int64(repostID)})
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+		})
+	}
+}
@@ -0,0 +1,42 @@
+// Code generated by mockery v2.30.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PostFlagger is an autogenerated mock type for the PostFlagger type
+type PostFlagger struct {
+	mock.Mock
+}
+
+// ReportPost provides a mock function with given fields: ctx, id, reporter, reason, note, date_created
+func (_m *PostFlagger) ReportPost(ctx context.Context, id int, reporter string, reason string, note string, date_created string) error {
+	ret := _m.Called(ctx, id, reporter, reason, note, date_created)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string, string, string) error); ok {
+		r0 = rf(ctx, id, reporter, reason, note, date_created)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPostFlagger creates a new instance of PostFlagger. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPostFlagger(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PostFlagger {
+	mock := &PostFlagger{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,66 @@
+// Code generated by mockery v2.30.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PostUpdater is an autogenerated mock type for the PostUpdater type
+type PostUpdater struct {
+	mock.Mock
+}
+
+// GetPostCreator provides a mock function with given fields: ctx, id
+func (_m *PostUpdater) GetPostCreator(ctx context.Context, id int) (string, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (string, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) string); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePost provides a mock function with given fields: ctx, id, title, text, format, visibility, date_updated
+func (_m *PostUpdater) UpdatePost(ctx context.Context, id int, title string, text string, format string, visibility string, date_updated string) error {
+	ret := _m.Called(ctx, id, title, text, format, visibility, date_updated)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string, string, string, string) error); ok {
+		r0 = rf(ctx, id, title, text, format, visibility, date_updated)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPostUpdater creates a new instance of PostUpdater. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPostUpdater(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PostUpdater {
+	mock := &PostUpdater{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
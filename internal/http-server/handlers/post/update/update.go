@@ -2,34 +2,75 @@ package update
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/save"
 	_ "github.com/solumD/go-blog-api/internal/http-server/models"
 	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/broker"
 	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/lib/notifier"
+	"github.com/solumD/go-blog-api/internal/lib/policy"
+	"github.com/solumD/go-blog-api/internal/lib/validator"
+	"github.com/solumD/go-blog-api/internal/storage"
 )
 
 type Request struct {
-	ID    int    `json:"id"`
-	Title string `json:"title,omitempty"`
-	Text  string `json:"text,omitempty"`
+	ID         int    `json:"id"`
+	Title      string `json:"title,omitempty"`
+	Text       string `json:"text,omitempty"`
+	Format     string `json:"format,omitempty"`
+	Visibility string `json:"visibility,omitempty"`
 }
 
 type Response struct {
 	resp.Response
 }
 
-type PostUpdater interface {
+// PostCreatorGetter получает автора поста.
+type PostCreatorGetter interface {
 	GetPostCreator(ctx context.Context, id int) (string, error)
-	UpdatePostTitle(ctx context.Context, id int, title string, date_updated string) error
-	UpdatePostText(ctx context.Context, id int, text string, date_updated string) error
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=PostUpdater
+type PostUpdater interface {
+	PostCreatorGetter
+	UpdatePost(ctx context.Context, id int, title string, text string, format string, visibility string, date_updated string) error
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=MentionSaver
+type MentionSaver interface {
+	IsUserExist(ctx context.Context, login string) (bool, error)
+	SetPostMentions(ctx context.Context, id int64, logins []string, date_created string) ([]string, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=PolicyChecker
+type PolicyChecker interface {
+	Check(ctx context.Context, c policy.Content) (policy.Decision, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=PostFlagger
+type PostFlagger interface {
+	ReportPost(ctx context.Context, id int, reporter string, reason string, note string, date_created string) error
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=Notifier
+type Notifier interface {
+	Notify(e notifier.Event)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=Publisher
+type Publisher interface {
+	Publish(topic string, eventType string, data any)
 }
 
 // @Summary     Update
@@ -39,20 +80,22 @@ type PostUpdater interface {
 // @ID          update
 // @Accept      json
 // @Produde     json
-// @Param       input   body     Request true "id of a post and info to be updated"
+// @Param       id      path     int     true "id of a post to be updated"
+// @Param       input   body     Request true "info to be updated, id in the body is used only by /post/update"
 // @Success     200     {object} models.UpdateSuccess
-// @Failure     400,500 {object} models.UpdateError
-// @Router      /post/update [patch]
-func New(ctx context.Context, log *slog.Logger, PostUpdater PostUpdater) http.HandlerFunc {
+// @Failure     400,403,404,500 {object} models.Problem
+// @Router      /v1/posts/{id} [patch]
+func New(log *slog.Logger, PostUpdater PostUpdater, mentionSaver MentionSaver, policyChecker PolicyChecker, postFlagger PostFlagger, eventNotifier Notifier, eventPublisher Publisher) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const fn = "handlers.post.update.New"
 
-		log = log.With(
+		log := log.With(
 			slog.String("fn", fn),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
 		)
 
-		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 		defer cancel()
 
 		var req Request
@@ -61,82 +104,149 @@ func New(ctx context.Context, log *slog.Logger, PostUpdater PostUpdater) http.Ha
 		if err != nil {
 			log.Error("failed to decode request body", sl.Err(err))
 
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to decode request"))
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidJSON, "failed to decode request")
 
 			return
 		}
 
-		req.Title = strings.TrimSpace(req.Title)
-		req.Text = strings.TrimSpace(req.Text)
-		log.Info("request body decoded", slog.Any("request", req))
+		// в /v1 ID поста в пути, в прежних маршрутах - в теле запроса
+		if id := chi.URLParam(r, "id"); id != "" {
+			req.ID, err = strconv.Atoi(id)
+			if err != nil {
+				log.Error("invalid request", sl.Err(err))
+
+				resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidID, "invalid post id")
+
+				return
+			}
+		}
 
-		if len(req.Text) == 0 && len(req.Title) == 0 {
-			log.Error("invalid request", sl.Err(fmt.Errorf("title or text must be filled in")))
+		if failure := Validate(&req); failure != nil {
+			log.Error("invalid request", sl.Err(failure))
 
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("title or text must be filled in"))
+			resp.Fail(w, r, failure.Kind, failure.Code, failure.Msg)
 
 			return
 		}
 
-		created_by, err := PostUpdater.GetPostCreator(ctx, req.ID)
-		if err == sql.ErrNoRows {
-			log.Error("invalid request", sl.Err(fmt.Errorf("post doesn't exist: %d", req.ID)))
+		log.Info("request body decoded", slog.Any("request", req))
 
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("post doesn't exist"))
+		login := r.Header.Get("login")
 
-			return
-		} else if err != nil {
-			log.Error("failed to check if post exists", sl.Err(err))
+		date_updated := time.Now().Format("2006-01-02 15:04:05")
 
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to check if post exists"))
+		decision, failure := Apply(ctx, log, PostUpdater, policyChecker, login, req, date_updated)
+		if failure != nil {
+			resp.Fail(w, r, failure.Kind, failure.Code, failure.Msg)
 
 			return
 		}
 
-		login := r.Header.Get("login")
-		if created_by != login {
-			log.Error("invalid request", sl.Err(fmt.Errorf("invalid user: %s", login)))
+		log.Info("post updated", slog.Int("id", req.ID))
 
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid user"))
+		Updated(ctx, log, save.Effects{
+			MentionSaver: mentionSaver,
+			PostFlagger:  postFlagger,
+			Notifier:     eventNotifier,
+			Publisher:    eventPublisher,
+		}, login, int64(req.ID), req, decision, date_updated)
 
-			return
-		}
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+		})
+	}
+}
 
-		date_updated := time.Now().Format("2006-01-02 15:04:05")
+// Validate проверяет изменение поста: убирает пробелы по краям названия и текста
+// и проверяет, что что-то меняется.
+func Validate(req *Request) *resp.Failure {
+	req.Title = strings.TrimSpace(req.Title)
+	req.Text = strings.TrimSpace(req.Text)
 
-		if len(req.Title) > 0 {
-			err := PostUpdater.UpdatePostTitle(ctx, req.ID, req.Title, date_updated)
-			if err != nil {
-				log.Error("failed to update post title", sl.Err(err))
+	if len(req.Text) == 0 && len(req.Title) == 0 && len(req.Format) == 0 && len(req.Visibility) == 0 {
+		return invalid("title, text, format or visibility must be filled in")
+	}
 
-				render.Status(r, http.StatusInternalServerError)
-				render.JSON(w, r, resp.Error("failed to update post title"))
+	if len(req.Format) > 0 {
+		if err := validator.ValidatePostFormat(req.Format); err != nil {
+			return invalid(err.Error())
+		}
+	}
 
-				return
-			}
+	if len(req.Visibility) > 0 {
+		if err := validator.ValidateVisibility(req.Visibility); err != nil {
+			return invalid(err.Error())
 		}
+	}
 
-		if len(req.Text) > 0 {
-			err := PostUpdater.UpdatePostText(ctx, req.ID, req.Text, date_updated)
-			if err != nil {
-				log.Error("failed to update post text", sl.Err(err))
+	return nil
+}
+
+func invalid(msg string) *resp.Failure {
+	return &resp.Failure{Kind: resp.ErrInvalid, Code: resp.CodeValidationFailed, Msg: msg}
+}
 
-				render.Status(r, http.StatusInternalServerError)
-				render.JSON(w, r, resp.Error("failed to update post text"))
+// CheckAuthor проверяет, что пост id есть и его автор - login.
+func CheckAuthor(ctx context.Context, log *slog.Logger, getter PostCreatorGetter, id int64, login string) *resp.Failure {
+	created_by, err := getter.GetPostCreator(ctx, int(id))
+	if errors.Is(err, storage.ErrPostNotFound) {
+		log.Error("invalid request", sl.Err(fmt.Errorf("post doesn't exist: %d", id)))
 
-				return
-			}
+		return &resp.Failure{Kind: storage.ErrNotFound, Code: resp.CodePostNotFound, Msg: "post doesn't exist"}
+	} else if err != nil {
+		log.Error("failed to check if post exists", sl.Err(err))
+
+		return &resp.Failure{Kind: resp.ErrInternal, Code: resp.CodeInternal, Msg: "failed to check if post exists"}
+	}
+
+	if created_by != login {
+		log.Error("invalid request", sl.Err(fmt.Errorf("invalid user: %s", login)))
+
+		return &resp.Failure{Kind: storage.ErrForbidden, Code: resp.CodeNotPostAuthor, Msg: "invalid user"}
+	}
+
+	return nil
+}
+
+// Apply проверяет, что login - автор поста, а новые название и текст проходят
+// политику содержимого, и сохраняет изменение поста. Решение политики нужно Updated.
+func Apply(ctx context.Context, log *slog.Logger, postUpdater PostUpdater, policyChecker PolicyChecker, login string, req Request, date_updated string) (policy.Decision, *resp.Failure) {
+	decision := policy.Decision{Verdict: policy.Allow}
+
+	if failure := CheckAuthor(ctx, log, postUpdater, int64(req.ID), login); failure != nil {
+		return decision, failure
+	}
+
+	// формат и видимость не проверяются, поэтому проверка нужна, только если меняется название или текст
+	if len(req.Title) > 0 || len(req.Text) > 0 {
+		var failure *resp.Failure
+
+		decision, failure = save.CheckContent(ctx, log, policyChecker, policy.Content{PostID: int64(req.ID), Author: login, Title: req.Title, Text: req.Text})
+		if failure != nil {
+			return decision, failure
 		}
+	}
 
-		log.Info("post updated", slog.Int("id", req.ID))
+	err := postUpdater.UpdatePost(ctx, req.ID, req.Title, req.Text, req.Format, req.Visibility, date_updated)
+	if err != nil {
+		log.Error("failed to update post", sl.Err(err))
 
-		render.JSON(w, r, Response{
-			Response: resp.OK(),
-		})
+		return decision, &resp.Failure{Kind: resp.ErrInternal, Code: resp.CodeInternal, Msg: "failed to update post"}
+	}
+
+	return decision, nil
+}
+
+// Updated делает после изменения поста то же, что и после создания: помечает пост
+// для модерации и сохраняет упоминания, а еще сообщает об изменении тем, кто смотрит пост.
+func Updated(ctx context.Context, log *slog.Logger, e save.Effects, login string, id int64, req Request, decision policy.Decision, date_updated string) {
+	// упоминания сравниваются с прошлой версией текста,
+	// поэтому уже упомянутые пользователи не упоминаются повторно
+	if len(req.Text) > 0 {
+		e.Mention(ctx, log, id, req.Text, login, date_updated, true)
 	}
+
+	e.Flag(ctx, log, id, decision, date_updated)
+
+	e.Publisher.Publish(broker.PostTopic(id), broker.EventPostUpdated, broker.PostData{PostID: id})
 }
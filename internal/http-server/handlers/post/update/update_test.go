@@ -0,0 +1,240 @@
+package update_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/update"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/update/mocks"
+	"github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/broker"
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/solumD/go-blog-api/internal/lib/notifier"
+	"github.com/solumD/go-blog-api/internal/lib/policy"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdatePostHandler(t *testing.T) {
+	testCases := []struct {
+		name string
+		path string
+		body string
+		// что меняется в посте 1
+		title string
+		text  string
+		// автор поста или ошибка при его получении; пустой автор - до проверки автора не дошло
+		creator    string
+		creatorErr error
+		decision   policy.Decision
+		mentions   []string
+		updateErr  error
+		respError  string
+		code       string
+		statusCode int
+	}{
+		{
+			name:       "Success",
+			path:       "/v1/posts/1",
+			body:       `{"title": "New Title"}`,
+			title:      "New Title",
+			creator:    "test_user",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Success with mentions",
+			path:       "/v1/posts/1",
+			body:       `{"text": "Hi @cool_user"}`,
+			text:       "Hi @cool_user",
+			creator:    "test_user",
+			mentions:   []string{"cool_user"},
+			statusCode: http.StatusOK,
+		},
+		{
+			// в прежнем маршруте ID поста в теле запроса
+			name:       "Success with id in body",
+			body:       `{"id": 1, "title": "New Title"}`,
+			title:      "New Title",
+			creator:    "test_user",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid json",
+			path:       "/v1/posts/1",
+			body:       `{"title": 1}`,
+			respError:  "failed to decode request",
+			code:       response.CodeInvalidJSON,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Invalid id",
+			path:       "/v1/posts/abc",
+			body:       `{"title": "New Title"}`,
+			respError:  "invalid post id",
+			code:       response.CodeInvalidID,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Nothing to update",
+			path:       "/v1/posts/1",
+			body:       `{"title": "   "}`,
+			respError:  "title, text, format or visibility must be filled in",
+			code:       response.CodeValidationFailed,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Post not found",
+			path:       "/v1/posts/1",
+			body:       `{"title": "New Title"}`,
+			creatorErr: storage.ErrPostNotFound,
+			respError:  "post doesn't exist",
+			code:       response.CodePostNotFound,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Not post author",
+			path:       "/v1/posts/1",
+			body:       `{"title": "New Title"}`,
+			creator:    "other_user",
+			respError:  "invalid user",
+			code:       response.CodeNotPostAuthor,
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "GetPostCreator Error",
+			path:       "/v1/posts/1",
+			body:       `{"title": "New Title"}`,
+			creatorErr: errors.New("unexpected error"),
+			respError:  "failed to check if post exists",
+			code:       response.CodeInternal,
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "Rejected by policy",
+			path:       "/v1/posts/1",
+			body:       `{"title": "New Title"}`,
+			title:      "New Title",
+			creator:    "test_user",
+			decision:   policy.Decision{Verdict: policy.Reject, Rule: "blocklist", Reason: `post contains blocked word "new"`},
+			respError:  `post contains blocked word "new"`,
+			code:       response.CodeContentRejected,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "UpdatePost Error",
+			path:       "/v1/posts/1",
+			body:       `{"title": "New Title"}`,
+			title:      "New Title",
+			creator:    "test_user",
+			updateErr:  errors.New("unexpected error"),
+			respError:  "failed to update post",
+			code:       response.CodeInternal,
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			postUpdaterMock := mocks.NewPostUpdater(t)
+
+			if tc.creator != "" || tc.creatorErr != nil {
+				postUpdaterMock.On("GetPostCreator", mock.Anything, 1).
+					Return(tc.creator, tc.creatorErr).
+					Once()
+			}
+
+			author := tc.creator == "test_user"
+
+			policyCheckerMock := mocks.NewPolicyChecker(t)
+
+			if author {
+				policyCheckerMock.On("Check", mock.Anything, policy.Content{PostID: 1, Author: "test_user", Title: tc.title, Text: tc.text}).
+					Return(tc.decision, nil).
+					Once()
+			}
+
+			if author && tc.decision.Verdict != policy.Reject {
+				postUpdaterMock.On("UpdatePost", mock.Anything, 1, tc.title, tc.text, "", "", mock.AnythingOfType("string")).
+					Return(tc.updateErr).
+					Once()
+			}
+
+			mentionSaverMock := mocks.NewMentionSaver(t)
+			notifierMock := mocks.NewNotifier(t)
+
+			if tc.mentions != nil {
+				mentionSaverMock.On("IsUserExist", mock.Anything, "cool_user").Return(true, nil).Once()
+				mentionSaverMock.On("SetPostMentions", mock.Anything, int64(1), tc.mentions, mock.AnythingOfType("string")).
+					Return(tc.mentions, nil).
+					Once()
+			}
+
+			for _, recipient := range tc.mentions {
+				notifierMock.On("Notify", notifier.Event{
+					Type:      types.NotificationMention,
+					Recipient: recipient,
+					Actor:     "test_user",
+					PostID:    1,
+				}).Once()
+			}
+
+			publisherMock := mocks.NewPublisher(t)
+
+			if tc.statusCode == http.StatusOK {
+				publisherMock.On("Publish", "post:1", broker.EventPostUpdated, broker.PostData{PostID: 1}).Once()
+			}
+
+			handler := update.New(loggerdiscard.NewDiscardLogger(), postUpdaterMock, mentionSaverMock, policyCheckerMock, mocks.NewPostFlagger(t), notifierMock, publisherMock)
+
+			router := chi.NewRouter()
+			router.Patch("/post/update", handler)
+			router.Patch("/v1/posts/{id}", handler)
+
+			path := tc.path
+			if path == "" {
+				path = "/post/update"
+			}
+
+			req, err := http.NewRequest(http.MethodPatch, path, strings.NewReader(tc.body))
+			require.NoError(t, err)
+
+			req.Header.Add("login", "test_user")
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			require.Equal(t, tc.statusCode, recorder.Code)
+
+			if tc.respError != "" {
+				require.Equal(t, response.ContentTypeProblem, recorder.Header().Get("Content-Type"))
+
+				var problem response.Problem
+
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+
+				require.Equal(t, tc.statusCode, problem.Status)
+				require.Equal(t, tc.code, problem.Code)
+				require.Equal(t, tc.respError, problem.Detail)
+
+				return
+			}
+
+			var resp update.Response
+
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+
+			require.Equal(t, response.StatusOK, resp.Status)
+		})
+	}
+}
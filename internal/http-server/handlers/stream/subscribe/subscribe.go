@@ -0,0 +1,160 @@
+package subscribe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/lib/stream"
+)
+
+// retry - через сколько миллисекунд браузер переподключится после обрыва соединения
+const retry = 3000
+
+type Subscriber interface {
+	Subscribe(login string, lastID uint64) (*stream.Subscription, []stream.Event, bool)
+	Unsubscribe(sub *stream.Subscription)
+}
+
+// @Summary     Stream
+// @Security    ApiKeyAuth
+// @Tags        stream
+// @Description get real-time events over Server-Sent Events: new posts of followed users (post),
+// @Description likes on user's posts (like) and new notifications (notification).
+// @Description pass the id of the last received event in the Last-Event-ID header to get missed events;
+// @Description if some of them are lost, the reset event is sent first
+// @ID          stream
+// @Produce     text/event-stream
+// @Param       Last-Event-ID header   int false "id of the last received event"
+// @Success     200           {string} string "stream of events"
+// @Failure     400,500 {object} models.Problem
+// @Router      /v1/stream [get]
+func New(ctx context.Context, heartbeat time.Duration, writeTimeout time.Duration, log *slog.Logger, subscriber Subscriber) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.stream.subscribe.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		var lastID uint64
+		if header := r.Header.Get("Last-Event-ID"); header != "" {
+			id, err := strconv.ParseUint(header, 10, 64)
+			if err != nil {
+				log.Error("invalid request", sl.Err(err))
+
+				resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidID, "invalid Last-Event-ID")
+
+				return
+			}
+			lastID = id
+		}
+
+		rc := http.NewResponseController(w)
+
+		// WriteTimeout сервера оборвал бы поток, поэтому дедлайн ставится на каждую запись отдельно
+		if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			log.Error("failed to reset write deadline", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to open stream")
+
+			return
+		}
+
+		login := r.Header.Get("login")
+
+		sub, missed, complete := subscriber.Subscribe(login, lastID)
+		defer subscriber.Unsubscribe(sub)
+
+		log.Info("stream opened", slog.String("login", login), slog.Uint64("last_event_id", lastID))
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		// nginx не должен буферизовать поток
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		write := func(msg string) error {
+			if err := rc.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				return err
+			}
+
+			if _, err := fmt.Fprint(w, msg); err != nil {
+				return err
+			}
+
+			return rc.Flush()
+		}
+
+		if err := write(fmt.Sprintf("retry: %d\n\n", retry)); err != nil {
+			log.Error("failed to write to stream", sl.Err(err))
+			return
+		}
+
+		if !complete {
+			if err := write(fmt.Sprintf("event: %s\ndata: {}\n\n", stream.EventReset)); err != nil {
+				log.Error("failed to write to stream", sl.Err(err))
+				return
+			}
+		}
+
+		for _, e := range missed {
+			if err := writeEvent(write, e); err != nil {
+				log.Error("failed to write to stream", sl.Err(err))
+				return
+			}
+		}
+
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				log.Info("stream closed by client")
+				return
+			case <-ctx.Done():
+				log.Info("stream closed by server")
+				return
+			case <-ticker.C:
+				// комментарий не создает событие у клиента, но держит соединение открытым
+				if err := write(": heartbeat\n\n"); err != nil {
+					log.Error("failed to write to stream", sl.Err(err))
+					return
+				}
+			case e, ok := <-sub.Events():
+				if !ok {
+					log.Warn("stream closed: client is too slow")
+					return
+				}
+
+				if err := writeEvent(write, e); err != nil {
+					log.Error("failed to write to stream", sl.Err(err))
+					return
+				}
+			}
+		}
+	}
+}
+
+// writeEvent записывает событие в формате Server-Sent Events.
+func writeEvent(write func(msg string) error, e stream.Event) error {
+	data, err := json.Marshal(e.Data)
+	if err != nil {
+		return err
+	}
+
+	return write(fmt.Sprintf("id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data))
+}
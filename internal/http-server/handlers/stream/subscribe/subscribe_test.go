@@ -0,0 +1,89 @@
+package subscribe_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/stream/subscribe"
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/solumD/go-blog-api/internal/lib/stream"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribeHandler(t *testing.T) {
+	testCases := []struct {
+		name        string
+		lastEventID string
+		replaySize  int
+		statusCode  int
+		body        string
+	}{
+		{
+			name:       "New stream",
+			replaySize: 10,
+			statusCode: http.StatusOK,
+			body:       "retry: 3000\n\n",
+		},
+		{
+			name:        "Resume",
+			lastEventID: "1",
+			replaySize:  10,
+			statusCode:  http.StatusOK,
+			body: "retry: 3000\n\n" +
+				"id: 3\nevent: notification\ndata: {\"post_id\":1,\"actor\":\"other_user\"}\n\n",
+		},
+		{
+			name:        "Resume after lost events",
+			lastEventID: "1",
+			replaySize:  1,
+			statusCode:  http.StatusOK,
+			body: "retry: 3000\n\n" +
+				"event: reset\ndata: {}\n\n" +
+				"id: 3\nevent: notification\ndata: {\"post_id\":1,\"actor\":\"other_user\"}\n\n",
+		},
+		{
+			name:        "Invalid Last-Event-ID",
+			lastEventID: "abc",
+			replaySize:  10,
+			statusCode:  http.StatusBadRequest,
+			body: "{\"type\":\"about:blank\",\"title\":\"Bad Request\",\"status\":400," +
+				"\"detail\":\"invalid Last-Event-ID\",\"instance\":\"/stream\",\"code\":\"invalid_id\"}\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			hub := stream.NewHub(loggerdiscard.NewDiscardLogger(), tc.replaySize, 10)
+			hub.Publish(stream.EventLike, []string{"test_user"}, stream.LikeData{PostID: 1, Actor: "other_user"})
+			hub.Publish(stream.EventPost, []string{"other_user"}, stream.PostData{PostID: 2, Author: "third_user"})
+			hub.Publish(stream.EventNotification, []string{"test_user"}, stream.LikeData{PostID: 1, Actor: "other_user"})
+
+			handler := subscribe.New(context.Background(), time.Hour, time.Second, loggerdiscard.NewDiscardLogger(), hub)
+
+			// клиент уже отключился, поэтому хэндлер завершится после пропущенных событий
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/stream", nil)
+			require.NoError(t, err)
+
+			req.Header.Add("login", "test_user")
+			if tc.lastEventID != "" {
+				req.Header.Add("Last-Event-ID", tc.lastEventID)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			require.Equal(t, tc.statusCode, recorder.Code)
+			require.Equal(t, tc.body, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,324 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/gorilla/websocket"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	"github.com/solumD/go-blog-api/internal/lib/broker"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+)
+
+// Действия клиента
+const (
+	ActionSubscribe   = "subscribe"
+	ActionUnsubscribe = "unsubscribe"
+)
+
+// Типы ответов на действия клиента
+const (
+	ReplySubscribed   = "subscribed"
+	ReplyUnsubscribed = "unsubscribed"
+	ReplyError        = "error"
+)
+
+// maxMessageSize - максимальный размер сообщения от клиента в байтах
+const maxMessageSize = 1024
+
+// Request - сообщение от клиента.
+type Request struct {
+	Action string `json:"action"`
+	Topic  string `json:"topic"`
+}
+
+// Reply - ответ на сообщение клиента.
+type Reply struct {
+	Type  string `json:"type"`
+	Topic string `json:"topic,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type Broker interface {
+	NewSubscriber() *broker.Subscriber
+	Subscribe(sub *broker.Subscriber, topic string) error
+	Unsubscribe(sub *broker.Subscriber, topic string)
+	Close(sub *broker.Subscriber)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=TopicChecker
+type TopicChecker interface {
+	CanViewPost(ctx context.Context, id int, viewer string) (bool, error)
+	IsUserExist(ctx context.Context, login string) (bool, error)
+	IsBlocked(ctx context.Context, first string, second string) (bool, error)
+}
+
+// @Summary     WebSocket
+// @Security    ApiKeyAuth
+// @Tags        stream
+// @Description open a WebSocket connection to get events of topics: user:<login> (public posts of the user)
+// @Description and post:<id> (likes, reactions, changes and removal of the post).
+// @Description send {"action": "subscribe" or "unsubscribe", "topic": "post:7"} to manage subscriptions,
+// @Description the server replies with {"type": "subscribed", "unsubscribed" or "error", "topic", "error"}
+// @Description and sends events as {"type", "topic", "data"}. if the post becomes hidden from the client
+// @Description or the user blocks the client, the server replies with {"type": "unsubscribed", "topic", "error"}
+// @Description instead of the event and stops sending events of the topic. the server pings the connection,
+// @Description a client that doesn't answer with pong is disconnected
+// @ID          ws
+// @Success     101 {string} string "switching protocols"
+// @Failure     400 {string} string "bad request"
+// @Router      /v1/ws [get]
+func New(ctx context.Context, pongWait time.Duration, writeTimeout time.Duration, log *slog.Logger, eventBroker Broker, topicChecker TopicChecker) http.HandlerFunc {
+	upgrader := websocket.Upgrader{}
+
+	// пинг отправляется чаще, чем истекает ожидание понга
+	pingPeriod := pongWait * 9 / 10
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.stream.ws.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		login := r.Header.Get("login")
+
+		// при ошибке upgrader сам отвечает клиенту
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.Error("failed to upgrade connection", sl.Err(err))
+			return
+		}
+		defer conn.Close()
+
+		log.Info("websocket opened", slog.String("login", login))
+
+		sub := eventBroker.NewSubscriber()
+		defer eventBroker.Close(sub)
+
+		// у соединения остались дедлайны http-сервера, поэтому они выставляются заново
+		conn.SetReadLimit(maxMessageSize)
+		conn.SetReadDeadline(time.Now().Add(pongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(pongWait))
+		})
+
+		// писать в соединение может только одна горутина, поэтому ответы передаются ей через канал
+		replies := make(chan Reply)
+		stop := make(chan struct{})
+		defer close(stop)
+
+		readDone := make(chan struct{})
+		go func() {
+			defer close(readDone)
+
+			for {
+				_, data, err := conn.ReadMessage()
+				if err != nil {
+					if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+						log.Error("failed to read message", sl.Err(err))
+					}
+					return
+				}
+
+				reply := handle(ctx, log, eventBroker, topicChecker, sub, login, data)
+
+				select {
+				case replies <- reply:
+				case <-stop:
+					return
+				}
+			}
+		}()
+
+		ticker := time.NewTicker(pingPeriod)
+		defer ticker.Stop()
+
+		for {
+			var msg any
+
+			select {
+			case <-readDone:
+				log.Info("websocket closed by client")
+				return
+			case <-ctx.Done():
+				closeConn(conn, writeTimeout, websocket.CloseGoingAway, "server is shutting down")
+				return
+			case <-ticker.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout)); err != nil {
+					log.Error("failed to ping", sl.Err(err))
+					return
+				}
+				continue
+			case reply := <-replies:
+				msg = reply
+			case event, ok := <-sub.Messages():
+				if !ok {
+					log.Warn("websocket closed: client is too slow")
+					closeConn(conn, writeTimeout, websocket.ClosePolicyViolation, "client is too slow")
+					return
+				}
+				msg = event
+
+				// пост могли скрыть, а автор - заблокировать клиента уже после подписки
+				if err := recheck(ctx, topicChecker, login, event); errors.Is(err, errInternal) {
+					log.Error("failed to check topic", slog.String("topic", event.Topic))
+					continue
+				} else if err != nil {
+					eventBroker.Unsubscribe(sub, event.Topic)
+					log.Info("unsubscribed from hidden topic", slog.String("topic", event.Topic))
+					msg = Reply{Type: ReplyUnsubscribed, Topic: event.Topic, Error: err.Error()}
+				}
+			}
+
+			conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := conn.WriteJSON(msg); err != nil {
+				log.Error("failed to write message", sl.Err(err))
+				return
+			}
+		}
+	}
+}
+
+// handle выполняет действие клиента и возвращает ответ на него.
+func handle(ctx context.Context, log *slog.Logger, eventBroker Broker, topicChecker TopicChecker, sub *broker.Subscriber, login string, data []byte) Reply {
+	var req Request
+	if err := json.Unmarshal(data, &req); err != nil {
+		log.Error("invalid message", sl.Err(err))
+		return Reply{Type: ReplyError, Error: "invalid message"}
+	}
+
+	switch req.Action {
+	case ActionSubscribe:
+		if err := subscribe(ctx, eventBroker, topicChecker, sub, login, req.Topic); err != nil {
+			log.Error("failed to subscribe", slog.String("topic", req.Topic), sl.Err(err))
+			return Reply{Type: ReplyError, Topic: req.Topic, Error: err.Error()}
+		}
+
+		return Reply{Type: ReplySubscribed, Topic: req.Topic}
+	case ActionUnsubscribe:
+		eventBroker.Unsubscribe(sub, req.Topic)
+
+		return Reply{Type: ReplyUnsubscribed, Topic: req.Topic}
+	}
+
+	log.Error("invalid message", slog.String("action", req.Action))
+
+	return Reply{Type: ReplyError, Topic: req.Topic, Error: "action must be subscribe or unsubscribe"}
+}
+
+var (
+	errPostNotFound = errors.New("post doesn't exist")
+	errUserNotFound = errors.New("user doesn't exist")
+	errInternal     = errors.New("failed to subscribe")
+)
+
+// subscribe подписывает клиента на топик, если клиенту доступен пост или пользователь.
+// Возвращает ошибку, текст которой можно показать клиенту.
+func subscribe(ctx context.Context, eventBroker Broker, topicChecker TopicChecker, sub *broker.Subscriber, login string, topic string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	kind, value, err := broker.ParseTopic(topic)
+	if err != nil {
+		return err
+	}
+
+	switch kind {
+	case broker.TopicPost:
+		id, _ := strconv.Atoi(value)
+
+		// скрытый от пользователя пост для него не существует
+		visible, err := topicChecker.CanViewPost(ctx, id, login)
+		if err != nil {
+			return errInternal
+		}
+
+		if !visible {
+			return errPostNotFound
+		}
+	case broker.TopicUser:
+		exist, err := topicChecker.IsUserExist(ctx, value)
+		if err != nil {
+			return errInternal
+		}
+
+		if !exist {
+			return errUserNotFound
+		}
+
+		// при блокировке посты скрыты в обе стороны
+		blocked, err := topicChecker.IsBlocked(ctx, value, login)
+		if err != nil {
+			return errInternal
+		}
+
+		if blocked {
+			return errUserNotFound
+		}
+	}
+
+	err = eventBroker.Subscribe(sub, topic)
+	if errors.Is(err, broker.ErrTooManySubscriptions) {
+		return broker.ErrTooManySubscriptions
+	} else if err != nil {
+		return errInternal
+	}
+
+	return nil
+}
+
+// recheck проверяет, доступен ли клиенту топик события, так же, как при подписке.
+// Удаление поста доходит до подписчиков, даже если пост уже нельзя увидеть.
+func recheck(ctx context.Context, topicChecker TopicChecker, login string, event broker.Message) error {
+	if event.Type == broker.EventPostDeleted {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	kind, value, err := broker.ParseTopic(event.Topic)
+	if err != nil {
+		return errInternal
+	}
+
+	switch kind {
+	case broker.TopicPost:
+		id, _ := strconv.Atoi(value)
+
+		visible, err := topicChecker.CanViewPost(ctx, id, login)
+		if err != nil {
+			return errInternal
+		}
+
+		if !visible {
+			return errPostNotFound
+		}
+	case broker.TopicUser:
+		blocked, err := topicChecker.IsBlocked(ctx, value, login)
+		if err != nil {
+			return errInternal
+		}
+
+		if blocked {
+			return errUserNotFound
+		}
+	}
+
+	return nil
+}
+
+// closeConn отправляет клиенту сообщение о закрытии соединения.
+func closeConn(conn *websocket.Conn, writeTimeout time.Duration, code int, text string) {
+	msg := websocket.FormatCloseMessage(code, text)
+	conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(writeTimeout))
+}
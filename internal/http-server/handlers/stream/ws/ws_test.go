@@ -0,0 +1,197 @@
+package ws_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/stream/ws"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/stream/ws/mocks"
+	"github.com/solumD/go-blog-api/internal/lib/broker"
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebSocketHandler(t *testing.T) {
+	topicCheckerMock := mocks.NewTopicChecker(t)
+	topicCheckerMock.On("CanViewPost", mock.Anything, 1, "test_user").Return(true, nil)
+	topicCheckerMock.On("CanViewPost", mock.Anything, 2, "test_user").Return(false, nil)
+	topicCheckerMock.On("CanViewPost", mock.Anything, 3, "test_user").Return(true, nil)
+	topicCheckerMock.On("IsUserExist", mock.Anything, "other_user").Return(true, nil)
+	topicCheckerMock.On("IsUserExist", mock.Anything, "ghost_user").Return(false, nil)
+	topicCheckerMock.On("IsUserExist", mock.Anything, "blocker_user").Return(true, nil)
+	topicCheckerMock.On("IsBlocked", mock.Anything, "other_user", "test_user").Return(false, nil)
+	topicCheckerMock.On("IsBlocked", mock.Anything, "blocker_user", "test_user").Return(true, nil)
+
+	eventBroker := broker.New(loggerdiscard.NewDiscardLogger(), 10, 2)
+
+	handler := ws.New(context.Background(), time.Minute, time.Second, loggerdiscard.NewDiscardLogger(), eventBroker, topicCheckerMock)
+
+	// логин в хэдер кладет middleware авторизации
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Header.Set("login", "test_user")
+		handler(w, r)
+	}))
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	testCases := []struct {
+		name  string
+		send  string
+		reply ws.Reply
+	}{
+		{
+			name:  "Subscribe to post",
+			send:  `{"action": "subscribe", "topic": "post:1"}`,
+			reply: ws.Reply{Type: ws.ReplySubscribed, Topic: "post:1"},
+		},
+		{
+			name:  "Hidden post",
+			send:  `{"action": "subscribe", "topic": "post:2"}`,
+			reply: ws.Reply{Type: ws.ReplyError, Topic: "post:2", Error: "post doesn't exist"},
+		},
+		{
+			name:  "Missing user",
+			send:  `{"action": "subscribe", "topic": "user:ghost_user"}`,
+			reply: ws.Reply{Type: ws.ReplyError, Topic: "user:ghost_user", Error: "user doesn't exist"},
+		},
+		{
+			name:  "Blocked user",
+			send:  `{"action": "subscribe", "topic": "user:blocker_user"}`,
+			reply: ws.Reply{Type: ws.ReplyError, Topic: "user:blocker_user", Error: "user doesn't exist"},
+		},
+		{
+			name:  "Invalid topic",
+			send:  `{"action": "subscribe", "topic": "comment:1"}`,
+			reply: ws.Reply{Type: ws.ReplyError, Topic: "comment:1", Error: broker.ErrInvalidTopic.Error()},
+		},
+		{
+			name:  "Subscribe to user",
+			send:  `{"action": "subscribe", "topic": "user:other_user"}`,
+			reply: ws.Reply{Type: ws.ReplySubscribed, Topic: "user:other_user"},
+		},
+		{
+			name:  "Too many subscriptions",
+			send:  `{"action": "subscribe", "topic": "post:3"}`,
+			reply: ws.Reply{Type: ws.ReplyError, Topic: "post:3", Error: broker.ErrTooManySubscriptions.Error()},
+		},
+		{
+			name:  "Unknown action",
+			send:  `{"action": "publish", "topic": "post:1"}`,
+			reply: ws.Reply{Type: ws.ReplyError, Topic: "post:1", Error: "action must be subscribe or unsubscribe"},
+		},
+		{
+			name:  "Invalid message",
+			send:  `not json`,
+			reply: ws.Reply{Type: ws.ReplyError, Error: "invalid message"},
+		},
+	}
+
+	// сообщения обрабатываются по порядку в одном соединении, поэтому случаи не параллельны
+	for _, tc := range testCases {
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(tc.send)), tc.name)
+
+		var reply ws.Reply
+		require.NoError(t, conn.ReadJSON(&reply), tc.name)
+		require.Equal(t, tc.reply, reply, tc.name)
+	}
+
+	eventBroker.Publish("post:1", broker.EventLike, broker.ReactionData{PostID: 1, Login: "other_user"})
+
+	var event struct {
+		Topic string              `json:"topic"`
+		Type  string              `json:"type"`
+		Data  broker.ReactionData `json:"data"`
+	}
+	require.NoError(t, conn.ReadJSON(&event))
+	require.Equal(t, "post:1", event.Topic)
+	require.Equal(t, broker.EventLike, event.Type)
+	require.Equal(t, broker.ReactionData{PostID: 1, Login: "other_user"}, event.Data)
+}
+
+func TestWebSocketRecheck(t *testing.T) {
+	topicCheckerMock := mocks.NewTopicChecker(t)
+	// при подписке пост виден, а к первому событию его уже скрыли
+	topicCheckerMock.On("CanViewPost", mock.Anything, 1, "test_user").Return(true, nil).Once()
+	topicCheckerMock.On("CanViewPost", mock.Anything, 1, "test_user").Return(false, nil)
+	topicCheckerMock.On("IsUserExist", mock.Anything, "other_user").Return(true, nil)
+	// к первому событию пользователь уже заблокировал клиента
+	topicCheckerMock.On("IsBlocked", mock.Anything, "other_user", "test_user").Return(false, nil).Once()
+	topicCheckerMock.On("IsBlocked", mock.Anything, "other_user", "test_user").Return(true, nil)
+
+	eventBroker := broker.New(loggerdiscard.NewDiscardLogger(), 10, 10)
+
+	handler := ws.New(context.Background(), time.Minute, time.Second, loggerdiscard.NewDiscardLogger(), eventBroker, topicCheckerMock)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Header.Set("login", "test_user")
+		handler(w, r)
+	}))
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	testCases := []struct {
+		name  string
+		topic string
+		event func()
+		reply ws.Reply
+	}{
+		{
+			name:  "Hidden post",
+			topic: "post:1",
+			event: func() {
+				eventBroker.Publish("post:1", broker.EventLike, broker.ReactionData{PostID: 1, Login: "other_user"})
+			},
+			reply: ws.Reply{Type: ws.ReplyUnsubscribed, Topic: "post:1", Error: "post doesn't exist"},
+		},
+		{
+			name:  "Blocked by user",
+			topic: "user:other_user",
+			event: func() {
+				eventBroker.Publish("user:other_user", broker.EventPostCreated, broker.PostData{PostID: 2, Author: "other_user"})
+			},
+			reply: ws.Reply{Type: ws.ReplyUnsubscribed, Topic: "user:other_user", Error: "user doesn't exist"},
+		},
+	}
+
+	for _, tc := range testCases {
+		require.NoError(t, conn.WriteJSON(ws.Request{Action: ws.ActionSubscribe, Topic: tc.topic}), tc.name)
+
+		var reply ws.Reply
+		require.NoError(t, conn.ReadJSON(&reply), tc.name)
+		require.Equal(t, ws.Reply{Type: ws.ReplySubscribed, Topic: tc.topic}, reply, tc.name)
+
+		// удаление поста приходит без проверки
+		eventBroker.Publish(tc.topic, broker.EventPostDeleted, broker.PostData{PostID: 1})
+
+		var event broker.Message
+		require.NoError(t, conn.ReadJSON(&event), tc.name)
+		require.Equal(t, broker.EventPostDeleted, event.Type, tc.name)
+
+		tc.event()
+
+		require.NoError(t, conn.ReadJSON(&reply), tc.name)
+		require.Equal(t, tc.reply, reply, tc.name)
+
+		// клиент отписан, поэтому следующее событие топика не приходит,
+		// а следующим сообщением будет ответ на отписку
+		tc.event()
+
+		require.NoError(t, conn.WriteJSON(ws.Request{Action: ws.ActionUnsubscribe, Topic: tc.topic}), tc.name)
+
+		var unsubscribed ws.Reply
+		require.NoError(t, conn.ReadJSON(&unsubscribed), tc.name)
+		require.Equal(t, ws.Reply{Type: ws.ReplyUnsubscribed, Topic: tc.topic}, unsubscribed, tc.name)
+	}
+}
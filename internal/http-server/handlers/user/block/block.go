@@ -0,0 +1,99 @@
+package block
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/storage"
+)
+
+type Response struct {
+	resp.Response
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=UserBlocker
+type UserBlocker interface {
+	IsUserExist(ctx context.Context, login string) (bool, error)
+	Block(ctx context.Context, blocker string, blocked string, date_created string) error
+}
+
+// @Summary     Block
+// @Security    ApiKeyAuth
+// @Tags        user
+// @Description block a user: the blocked user can't like, repost, follow, mention or message you,
+// @Description posts are hidden in both directions and follows between you are removed
+// @ID          block
+// @Accept      json
+// @Produde     json
+// @Param       login   path     string true "login of a user to block"
+// @Success     200     {object} models.BlockSuccess
+// @Failure     400,404,500 {object} models.Problem
+// @Router      /v1/user/{login}/block [put]
+func New(log *slog.Logger, userBlocker UserBlocker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.user.block.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		blocked := strings.TrimSpace(chi.URLParam(r, "login"))
+		blocker := r.Header.Get("login")
+
+		if blocked == blocker {
+			log.Error("invalid request", sl.Err(fmt.Errorf("user can't block themselves")))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeSelfAction, "you can't block yourself")
+
+			return
+		}
+
+		exist, err := userBlocker.IsUserExist(ctx, blocked)
+		if err != nil {
+			log.Error("failed to check if user exists", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to check if user exists")
+
+			return
+		}
+
+		if !exist {
+			log.Error("invalid request", sl.Err(fmt.Errorf("user doesn't exist: %s", blocked)))
+
+			resp.Fail(w, r, storage.ErrNotFound, resp.CodeUserNotFound, "user doesn't exist")
+
+			return
+		}
+
+		date_created := time.Now().Format("2006-01-02 15:04:05")
+
+		if err := userBlocker.Block(ctx, blocker, blocked, date_created); err != nil {
+			log.Error("failed to block user", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to block user")
+
+			return
+		}
+
+		log.Info("user blocked", slog.String("blocked", blocked))
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+		})
+	}
+}
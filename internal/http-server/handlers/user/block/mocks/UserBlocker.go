@@ -0,0 +1,66 @@
+// Code generated by mockery v2.30.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UserBlocker is an autogenerated mock type for the UserBlocker type
+type UserBlocker struct {
+	mock.Mock
+}
+
+// Block provides a mock function with given fields: ctx, blocker, blocked, date_created
+func (_m *UserBlocker) Block(ctx context.Context, blocker string, blocked string, date_created string) error {
+	ret := _m.Called(ctx, blocker, blocked, date_created)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, blocker, blocked, date_created)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// IsUserExist provides a mock function with given fields: ctx, login
+func (_m *UserBlocker) IsUserExist(ctx context.Context, login string) (bool, error) {
+	ret := _m.Called(ctx, login)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, login)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, login)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, login)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewUserBlocker creates a new instance of UserBlocker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserBlocker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserBlocker {
+	mock := &UserBlocker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,66 @@
+package collections
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+type Response struct {
+	resp.Response
+	Collections []types.BookmarkCollection `json:"collections"`
+}
+
+type CollectionsGetter interface {
+	GetBookmarkCollections(ctx context.Context, login string) ([]types.BookmarkCollection, error)
+}
+
+// @Summary     Bookmark collections
+// @Security    ApiKeyAuth
+// @Tags        user
+// @Description get names of bookmark collections and number of bookmarks in each;
+// @Description bookmarks without a collection are counted under an empty name
+// @ID          collections
+// @Accept      json
+// @Produde     json
+// @Success     200 {object} models.CollectionsSuccess
+// @Failure     500 {object} models.Problem
+// @Router      /v1/user/me/bookmarks/collections [get]
+func New(log *slog.Logger, collectionsGetter CollectionsGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.user.collections.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		collections, err := collectionsGetter.GetBookmarkCollections(ctx, r.Header.Get("login"))
+		if err != nil {
+			log.Error("failed to get bookmark collections", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to get bookmark collections")
+
+			return
+		}
+
+		log.Info("bookmark collections got", slog.Int("count", len(collections)))
+
+		render.JSON(w, r, Response{
+			Response:    resp.OK(),
+			Collections: collections,
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package editprofile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/lib/validator"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+// Request - изменяемые поля профиля. Поля, которых нет в запросе, остаются прежними.
+type Request struct {
+	DisplayName *string `json:"display_name,omitempty"`
+	Bio         *string `json:"bio,omitempty"`
+	Website     *string `json:"website,omitempty"`
+	AvatarID    *int64  `json:"avatar_id,omitempty"`
+}
+
+type Response struct {
+	resp.Response
+	Profile *types.Profile `json:"profile,omitempty"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=ProfileUpdater
+type ProfileUpdater interface {
+	GetProfile(ctx context.Context, login string, viewer string) (*types.Profile, error)
+	UpdateProfile(ctx context.Context, login string, displayName string, bio string, website string, avatarID int64) error
+}
+
+// @Summary     Edit profile
+// @Security    ApiKeyAuth
+// @Tags        user
+// @Description change display name (50 characters at most), bio (160 characters at most), website (http or https url)
+// @Description or avatar of the user; the avatar is an uploaded media not attached to a post, 0 removes it;
+// @Description omitted fields stay the same
+// @ID          edit-profile
+// @Accept      json
+// @Produde     json
+// @Param       input       body     Request true "fields of the profile to change"
+// @Success     200         {object} models.EditProfileSuccess
+// @Failure     400,404,409,500 {object} models.Problem
+// @Router      /v1/user/me/profile [patch]
+func New(log *slog.Logger, profileUpdater ProfileUpdater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.user.editprofile.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidJSON, "failed to decode request")
+
+			return
+		}
+
+		if req.AvatarID != nil && *req.AvatarID < 0 {
+			log.Error("invalid request", sl.Err(fmt.Errorf("invalid avatar id: %d", *req.AvatarID)))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidID, "invalid avatar id")
+
+			return
+		}
+
+		login := r.Header.Get("login")
+
+		profile, err := profileUpdater.GetProfile(ctx, login, login)
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Error("invalid request", sl.Err(fmt.Errorf("user doesn't exist: %s", login)))
+
+			resp.Fail(w, r, storage.ErrNotFound, resp.CodeUserNotFound, "user doesn't exist")
+
+			return
+		} else if err != nil {
+			log.Error("failed to get profile", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to update profile")
+
+			return
+		}
+
+		if req.DisplayName != nil {
+			profile.DisplayName = strings.TrimSpace(*req.DisplayName)
+		}
+
+		if req.Bio != nil {
+			profile.Bio = strings.TrimSpace(*req.Bio)
+		}
+
+		if req.Website != nil {
+			profile.Website = strings.TrimSpace(*req.Website)
+		}
+
+		if req.AvatarID != nil {
+			profile.AvatarID = *req.AvatarID
+		}
+
+		if err := validateProfile(profile); err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, err.Error())
+
+			return
+		}
+
+		err = profileUpdater.UpdateProfile(ctx, login, profile.DisplayName, profile.Bio, profile.Website, profile.AvatarID)
+		if errors.Is(err, storage.ErrMediaNotAvailable) {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, storage.ErrConflict, resp.CodeMediaNotAvailable, "avatar doesn't exist or is attached to a post")
+
+			return
+		} else if err != nil {
+			log.Error("failed to update profile", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to update profile")
+
+			return
+		}
+
+		log.Info("profile updated", slog.String("login", login))
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Profile:  profile,
+		})
+	}
+}
+
+// validateProfile проверяет изменяемые поля профиля.
+func validateProfile(profile *types.Profile) error {
+	if err := validator.ValidateDisplayName(profile.DisplayName); err != nil {
+		return err
+	}
+
+	if err := validator.ValidateBio(profile.Bio); err != nil {
+		return err
+	}
+
+	return validator.ValidateWebsite(profile.Website)
+}
@@ -0,0 +1,165 @@
+package editprofile_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/user/editprofile"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/user/editprofile/mocks"
+	"github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditProfileHandler(t *testing.T) {
+	testCases := []struct {
+		name        string
+		body        string
+		displayName string
+		bio         string
+		website     string
+		avatarID    int64
+		get         bool
+		update      bool
+		respError   string
+		mockError   error
+		statusCode  int
+	}{
+		{
+			name:        "Success",
+			body:        `{"display_name": " Test User ", "website": "https://example.com"}`,
+			displayName: "Test User",
+			bio:         "old bio",
+			website:     "https://example.com",
+			avatarID:    3,
+			update:      true,
+			get:         true,
+			statusCode:  http.StatusOK,
+		},
+		{
+			name:        "Remove avatar",
+			body:        `{"avatar_id": 0}`,
+			displayName: "old name",
+			bio:         "old bio",
+			update:      true,
+			get:         true,
+			statusCode:  http.StatusOK,
+		},
+		{
+			name:       "Invalid avatar id",
+			body:       `{"avatar_id": -1}`,
+			respError:  "invalid avatar id",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Too long bio",
+			body:       `{"bio": "` + strings.Repeat("a", 161) + `"}`,
+			respError:  "bio cannot be longer than 160 characters",
+			get:        true,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Display name with line break",
+			body:       `{"display_name": "Test\nUser"}`,
+			respError:  "display name cannot contain line breaks",
+			get:        true,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Invalid website",
+			body:       `{"website": "ftp://example.com"}`,
+			respError:  "website must be an http or https url",
+			get:        true,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "Avatar not available",
+			body:        `{"avatar_id": 7}`,
+			displayName: "old name",
+			bio:         "old bio",
+			avatarID:    7,
+			update:      true,
+			respError:   "avatar doesn't exist or is attached to a post",
+			mockError:   fmt.Errorf("%w: %d", storage.ErrMediaNotAvailable, 7),
+			get:         true,
+			statusCode:  http.StatusConflict,
+		},
+		{
+			name:        "UpdateProfile Error",
+			body:        `{"bio": "new bio"}`,
+			displayName: "old name",
+			bio:         "new bio",
+			avatarID:    3,
+			update:      true,
+			respError:   "failed to update profile",
+			mockError:   errors.New("unexpected error"),
+			get:         true,
+			statusCode:  http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			profileUpdaterMock := mocks.NewProfileUpdater(t)
+
+			if tc.get {
+				profileUpdaterMock.On("GetProfile", mock.Anything, "test_user", "test_user").
+					Return(&types.Profile{Login: "test_user", DisplayName: "old name", Bio: "old bio", AvatarID: 3}, nil).
+					Once()
+			}
+
+			if tc.update {
+				profileUpdaterMock.On("UpdateProfile", mock.Anything, "test_user", tc.displayName, tc.bio, tc.website, tc.avatarID).
+					Return(tc.mockError).
+					Once()
+			}
+
+			router := chi.NewRouter()
+			router.Patch("/user/me/profile", editprofile.New(loggerdiscard.NewDiscardLogger(), profileUpdaterMock))
+
+			req, err := http.NewRequest(http.MethodPatch, "/user/me/profile", strings.NewReader(tc.body))
+			require.NoError(t, err)
+
+			req.Header.Add("login", "test_user")
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			require.Equal(t, tc.statusCode, recorder.Code)
+
+			if tc.respError != "" {
+				var problem response.Problem
+
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+
+				require.Equal(t, tc.respError, problem.Detail)
+
+				return
+			}
+
+			var resp editprofile.Response
+
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+
+			require.Equal(t, response.StatusOK, resp.Status)
+
+			if tc.statusCode == http.StatusOK {
+				require.Equal(t, tc.displayName, resp.Profile.DisplayName)
+				require.Equal(t, tc.avatarID, resp.Profile.AvatarID)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+// Code generated by mockery v2.30.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	types "github.com/solumD/go-blog-api/internal/types"
+)
+
+// ProfileUpdater is an autogenerated mock type for the ProfileUpdater type
+type ProfileUpdater struct {
+	mock.Mock
+}
+
+// GetProfile provides a mock function with given fields: ctx, login, viewer
+func (_m *ProfileUpdater) GetProfile(ctx context.Context, login string, viewer string) (*types.Profile, error) {
+	ret := _m.Called(ctx, login, viewer)
+
+	var r0 *types.Profile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*types.Profile, error)); ok {
+		return rf(ctx, login, viewer)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *types.Profile); ok {
+		r0 = rf(ctx, login, viewer)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Profile)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, login, viewer)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateProfile provides a mock function with given fields: ctx, login, displayName, bio, website, avatarID
+func (_m *ProfileUpdater) UpdateProfile(ctx context.Context, login string, displayName string, bio string, website string, avatarID int64) error {
+	ret := _m.Called(ctx, login, displayName, bio, website, avatarID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, int64) error); ok {
+		r0 = rf(ctx, login, displayName, bio, website, avatarID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewProfileUpdater creates a new instance of ProfileUpdater. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewProfileUpdater(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ProfileUpdater {
+	mock := &ProfileUpdater{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,147 @@
+package follow
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/lib/notifier"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+type Response struct {
+	resp.Response
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=UserFollower
+type UserFollower interface {
+	IsUserExist(ctx context.Context, login string) (bool, error)
+	IsBlocked(ctx context.Context, first string, second string) (bool, error)
+	IsFollowing(ctx context.Context, follower string, followee string) (bool, error)
+	Follow(ctx context.Context, follower string, followee string, date_created string) error
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=Notifier
+type Notifier interface {
+	Notify(e notifier.Event)
+}
+
+// @Summary     Follow
+// @Security    ApiKeyAuth
+// @Tags        user
+// @Description follow a user to see their followers-only posts
+// @ID          follow
+// @Accept      json
+// @Produde     json
+// @Param       login       path     string true "login of a user to follow"
+// @Success     200         {object} models.FollowSuccess
+// @Failure     400,403,404,409,500 {object} models.Problem
+// @Router      /v1/user/{login}/follow [put]
+func New(log *slog.Logger, userFollower UserFollower, eventNotifier Notifier) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.user.follow.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		followee := strings.TrimSpace(chi.URLParam(r, "login"))
+		follower := r.Header.Get("login")
+
+		if followee == follower {
+			log.Error("invalid request", sl.Err(fmt.Errorf("user can't follow themselves")))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeSelfAction, "you can't follow yourself")
+
+			return
+		}
+
+		exist, err := userFollower.IsUserExist(ctx, followee)
+		if err != nil {
+			log.Error("failed to check if user exists", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to check if user exists")
+
+			return
+		}
+
+		if !exist {
+			log.Error("invalid request", sl.Err(fmt.Errorf("user doesn't exist: %s", followee)))
+
+			resp.Fail(w, r, storage.ErrNotFound, resp.CodeUserNotFound, "user doesn't exist")
+
+			return
+		}
+
+		blocked, err := userFollower.IsBlocked(ctx, followee, follower)
+		if err != nil {
+			log.Error("failed to check if user is blocked", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to check if user is blocked")
+
+			return
+		}
+
+		if blocked {
+			log.Error("invalid request", sl.Err(fmt.Errorf("block between %s and %s", follower, followee)))
+
+			resp.Fail(w, r, storage.ErrForbidden, resp.CodeBlocked, "you can't follow this user")
+
+			return
+		}
+
+		following, err := userFollower.IsFollowing(ctx, follower, followee)
+		if err != nil {
+			log.Error("failed to check if user is followed", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to check if user is followed")
+
+			return
+		}
+
+		if following {
+			log.Error("invalid request", sl.Err(fmt.Errorf("you already follow %s", followee)))
+
+			resp.Fail(w, r, storage.ErrConflict, resp.CodeAlreadyFollowing, fmt.Sprintf("you already follow %s", followee))
+
+			return
+		}
+
+		date_created := time.Now().Format("2006-01-02 15:04:05")
+
+		if err := userFollower.Follow(ctx, follower, followee, date_created); err != nil {
+			log.Error("failed to follow user", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to follow user")
+
+			return
+		}
+
+		eventNotifier.Notify(notifier.Event{
+			Type:      types.NotificationFollow,
+			Recipient: followee,
+			Actor:     follower,
+		})
+
+		log.Info("user followed", slog.String("followee", followee))
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package follow_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/user/follow"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/user/follow/mocks"
+	"github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/solumD/go-blog-api/internal/lib/notifier"
+	"github.com/solumD/go-blog-api/internal/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollowHandler(t *testing.T) {
+	testCases := []struct {
+		name       string
+		login      string
+		exist      bool
+		blocked    bool
+		following  bool
+		respError  string
+		mockError  error
+		statusCode int
+	}{
+		{
+			name:       "Success",
+			login:      "other_user",
+			exist:      true,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Follow yourself",
+			login:      "test_user",
+			respError:  "you can't follow yourself",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Missing user",
+			login:      "other_user",
+			respError:  "user doesn't exist",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Blocked",
+			login:      "other_user",
+			exist:      true,
+			blocked:    true,
+			respError:  "you can't follow this user",
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "Already following",
+			login:      "other_user",
+			exist:      true,
+			following:  true,
+			respError:  "you already follow other_user",
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "Follow Error",
+			login:      "other_user",
+			exist:      true,
+			respError:  "failed to follow user",
+			mockError:  errors.New("unexpected error"),
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			userFollowerMock := mocks.NewUserFollower(t)
+
+			if tc.login != "test_user" {
+				userFollowerMock.On("IsUserExist", mock.Anything, tc.login).
+					Return(tc.exist, nil).
+					Once()
+			}
+
+			if tc.exist {
+				userFollowerMock.On("IsBlocked", mock.Anything, tc.login, "test_user").
+					Return(tc.blocked, nil).
+					Once()
+			}
+
+			if tc.exist && !tc.blocked {
+				userFollowerMock.On("IsFollowing", mock.Anything, "test_user", tc.login).
+					Return(tc.following, nil).
+					Once()
+			}
+
+			if tc.exist && !tc.blocked && !tc.following {
+				userFollowerMock.On("Follow", mock.Anything, "test_user", tc.login, mock.AnythingOfType("string")).
+					Return(tc.mockError).
+					Once()
+			}
+
+			notifierMock := mocks.NewNotifier(t)
+
+			if tc.statusCode == http.StatusOK {
+				notifierMock.On("Notify", notifier.Event{
+					Type:      types.NotificationFollow,
+					Recipient: tc.login,
+					Actor:     "test_user",
+				}).Once()
+			}
+
+			router := chi.NewRouter()
+			router.Put("/user/{login}/follow", follow.New(loggerdiscard.NewDiscardLogger(), userFollowerMock, notifierMock))
+
+			req, err := http.NewRequest(http.MethodPut, "/user/"+tc.login+"/follow", nil)
+			require.NoError(t, err)
+
+			req.Header.Add("login", "test_user")
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			require.Equal(t, tc.statusCode, recorder.Code)
+
+			if tc.respError != "" {
+				var problem response.Problem
+
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+
+				require.Equal(t, tc.respError, problem.Detail)
+
+				return
+			}
+
+			var resp follow.Response
+
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+
+			require.Equal(t, response.StatusOK, resp.Status)
+		})
+	}
+}
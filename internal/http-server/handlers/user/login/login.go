@@ -16,6 +16,7 @@ import (
 	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
 	"github.com/solumD/go-blog-api/internal/lib/password"
 	"github.com/solumD/go-blog-api/internal/lib/validator"
+	"github.com/solumD/go-blog-api/internal/storage"
 )
 
 type Request struct {
@@ -32,6 +33,12 @@ type Response struct {
 type UserAuthorizer interface {
 	IsUserExist(ctx context.Context, login string) (bool, error)
 	GetPassword(ctx context.Context, login string) (string, error)
+	IsSuspended(ctx context.Context, login string) (bool, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=LoginCounter
+type LoginCounter interface {
+	LoginAttempted(ok bool)
 }
 
 // @Summary     Login
@@ -40,20 +47,21 @@ type UserAuthorizer interface {
 // @ID          login
 // @Accept      json
 // @Produde     json
-// @Param       input   body     Request true "account info"
-// @Success     200     {object} models.LoginSuccess
-// @Failure     400,500 {object} models.LoginError
-// @Router      /auth/login [post]
-func New(ctx context.Context, secret string, log *slog.Logger, userAuthorizer UserAuthorizer) http.HandlerFunc {
+// @Param       input       body     Request true "account info"
+// @Success     200         {object} models.LoginSuccess
+// @Failure     400,401,403,404,500 {object} models.Problem
+// @Router      /v1/auth/login [post]
+func New(secret string, log *slog.Logger, userAuthorizer UserAuthorizer, loginCounter LoginCounter) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const fn = "handlers.login.New"
 
-		log = log.With(
+		log := log.With(
 			slog.String("fn", fn),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
 		)
 
-		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 		defer cancel()
 
 		var req Request
@@ -62,8 +70,7 @@ func New(ctx context.Context, secret string, log *slog.Logger, userAuthorizer Us
 		if err != nil {
 			log.Error("failed to decode request body", sl.Err(err))
 
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to decode request"))
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidJSON, "failed to decode request")
 
 			return
 		}
@@ -75,8 +82,7 @@ func New(ctx context.Context, secret string, log *slog.Logger, userAuthorizer Us
 		if err := validator.ValidateLogin(req.Login); err != nil {
 			log.Error("invalid request", sl.Err(err))
 
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(err.Error()))
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, err.Error())
 
 			return
 		}
@@ -84,8 +90,7 @@ func New(ctx context.Context, secret string, log *slog.Logger, userAuthorizer Us
 		if err := validator.ValidatePassword(req.Password); err != nil {
 			log.Error("invalid request", sl.Err(err))
 
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(err.Error()))
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, err.Error())
 
 			return
 		}
@@ -95,17 +100,16 @@ func New(ctx context.Context, secret string, log *slog.Logger, userAuthorizer Us
 		if err != nil {
 			log.Error("failed to check if user exists", sl.Err(err))
 
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to check if user exists"))
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to check if user exists")
 
 			return
 		}
 
 		if !exist {
 			log.Error("invalid request", sl.Err(fmt.Errorf("user does not exist")))
+			loginCounter.LoginAttempted(false)
 
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("user does not exist"))
+			resp.Fail(w, r, storage.ErrNotFound, resp.CodeUserNotFound, "user does not exist")
 
 			return
 		}
@@ -115,8 +119,7 @@ func New(ctx context.Context, secret string, log *slog.Logger, userAuthorizer Us
 		if err != nil {
 			log.Error("failed to get user's real password", sl.Err(err))
 
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to get user's real password"))
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to get user's real password")
 
 			return
 		}
@@ -124,9 +127,28 @@ func New(ctx context.Context, secret string, log *slog.Logger, userAuthorizer Us
 		// сравниваем реальный пароль с тем, который был передан в теле запроса
 		if err := password.CompareHashAndPass(req.Password, realPassword); err != nil {
 			log.Error("invalid request", sl.Err(fmt.Errorf("invalid password")))
+			loginCounter.LoginAttempted(false)
+
+			resp.Fail(w, r, resp.ErrUnauthorized, resp.CodeInvalidPassword, "invalid password")
+
+			return
+		}
+
+		// заблокированный модератором пользователь не может войти
+		suspended, err := userAuthorizer.IsSuspended(ctx, req.Login)
+		if err != nil {
+			log.Error("failed to check if user is suspended", sl.Err(err))
 
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid password"))
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to check if user is suspended")
+
+			return
+		}
+
+		if suspended {
+			log.Error("invalid request", sl.Err(fmt.Errorf("user is suspended")))
+			loginCounter.LoginAttempted(false)
+
+			resp.Fail(w, r, storage.ErrForbidden, resp.CodeAccountSuspended, "account is suspended")
 
 			return
 		}
@@ -135,12 +157,13 @@ func New(ctx context.Context, secret string, log *slog.Logger, userAuthorizer Us
 		if err != nil {
 			log.Error("failed to generate jwt-token", sl.Err(err))
 
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to generate jwt-token"))
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to generate jwt-token")
 
 			return
 		}
 
+		loginCounter.LoginAttempted(true)
+
 		render.JSON(w, r, Response{
 			Response: resp.OK(),
 			Token:    token,
@@ -2,7 +2,6 @@ package login_test
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,6 +11,7 @@ import (
 
 	"github.com/solumD/go-blog-api/internal/http-server/handlers/user/login"
 	"github.com/solumD/go-blog-api/internal/http-server/handlers/user/login/mocks"
+	"github.com/solumD/go-blog-api/internal/lib/api/response"
 	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
 	"github.com/solumD/go-blog-api/internal/lib/password"
 	"github.com/stretchr/testify/mock"
@@ -27,6 +27,7 @@ func TestLoginHandler(t *testing.T) {
 		respError            string
 		mockGetPasswordError error
 		mockIsUserExistError error
+		suspended            bool
 		statusCode           int
 	}{
 		{
@@ -85,7 +86,7 @@ func TestLoginHandler(t *testing.T) {
 			login:      "VeryCoolLogin",
 			password:   "VeryCoolPassword",
 			respError:  "user does not exist",
-			statusCode: http.StatusBadRequest,
+			statusCode: http.StatusNotFound,
 		},
 		{
 			name:         "Passwords doesn't match",
@@ -93,7 +94,16 @@ func TestLoginHandler(t *testing.T) {
 			password:     "VeryCoolPassword",
 			realPassword: "AnotherPassword",
 			respError:    "invalid password",
-			statusCode:   http.StatusBadRequest,
+			statusCode:   http.StatusUnauthorized,
+		},
+		{
+			name:         "Suspended",
+			login:        "VeryCoolLogin",
+			password:     "VeryCoolPassword",
+			realPassword: "VeryCoolPassword",
+			suspended:    true,
+			respError:    "account is suspended",
+			statusCode:   http.StatusForbidden,
 		},
 	}
 
@@ -108,7 +118,13 @@ func TestLoginHandler(t *testing.T) {
 			hashedrealPassword, err := password.EncryptPassword(tc.realPassword)
 			require.NoError(t, err)
 
-			if tc.respError == "" || tc.respError == "invalid password" || tc.mockGetPasswordError != nil {
+			if tc.respError == "" || tc.suspended {
+				userAuthorizerMock.On("IsSuspended", mock.Anything, tc.login).
+					Return(tc.suspended, nil).
+					Once()
+			}
+
+			if tc.respError == "" || tc.suspended || tc.respError == "invalid password" || tc.mockGetPasswordError != nil {
 				userAuthorizerMock.On("GetPassword", mock.Anything, mock.AnythingOfType("string")).
 					Return(hashedrealPassword, tc.mockGetPasswordError).
 					Once()
@@ -123,7 +139,16 @@ func TestLoginHandler(t *testing.T) {
 					Once()
 			}
 
-			handler := login.New(context.Background(), "secret", loggerdiscard.NewDiscardLogger(), userAuthorizerMock)
+			loginCounterMock := mocks.NewLoginCounter(t)
+
+			switch tc.respError {
+			case "":
+				loginCounterMock.On("LoginAttempted", true).Once()
+			case "user does not exist", "invalid password", "account is suspended":
+				loginCounterMock.On("LoginAttempted", false).Once()
+			}
+
+			handler := login.New("secret", loggerdiscard.NewDiscardLogger(), userAuthorizerMock, loginCounterMock)
 
 			input := fmt.Sprintf(`{"login": "%s", "password": "%s"}`, tc.login, tc.password)
 
@@ -137,11 +162,21 @@ func TestLoginHandler(t *testing.T) {
 
 			body := recorder.Body.String()
 
+			if tc.respError != "" {
+				var problem response.Problem
+
+				require.NoError(t, json.Unmarshal([]byte(body), &problem))
+
+				require.Equal(t, tc.respError, problem.Detail)
+
+				return
+			}
+
 			var resp login.Response
 
 			require.NoError(t, json.Unmarshal([]byte(body), &resp))
 
-			require.Equal(t, tc.respError, resp.Error)
+			require.Equal(t, response.StatusOK, resp.Status)
 		})
 	}
 }
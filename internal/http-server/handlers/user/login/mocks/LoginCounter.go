@@ -0,0 +1,29 @@
+// Code generated by mockery v2.30.0. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// LoginCounter is an autogenerated mock type for the LoginCounter type
+type LoginCounter struct {
+	mock.Mock
+}
+
+// LoginAttempted provides a mock function with given fields: ok
+func (_m *LoginCounter) LoginAttempted(ok bool) {
+	_m.Called(ok)
+}
+
+// NewLoginCounter creates a new instance of LoginCounter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLoginCounter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LoginCounter {
+	mock := &LoginCounter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
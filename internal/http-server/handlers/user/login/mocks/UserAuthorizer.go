@@ -37,6 +37,30 @@ func (_m *UserAuthorizer) GetPassword(ctx context.Context, _a1 string) (string,
 	return r0, r1
 }
 
+// IsSuspended provides a mock function with given fields: ctx, _a1
+func (_m *UserAuthorizer) IsSuspended(ctx context.Context, _a1 string) (bool, error) {
+	ret := _m.Called(ctx, _a1)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // IsUserExist provides a mock function with given fields: ctx, _a1
 func (_m *UserAuthorizer) IsUserExist(ctx context.Context, _a1 string) (bool, error) {
 	ret := _m.Called(ctx, _a1)
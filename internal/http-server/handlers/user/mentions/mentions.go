@@ -0,0 +1,102 @@
+package mentions
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	"github.com/solumD/go-blog-api/internal/lib/api/pagination"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+type Response struct {
+	resp.Response
+	Posts      []types.Post `json:"posts"`
+	NextOffset *int         `json:"next_offset,omitempty"`
+}
+
+type MentionsGetter interface {
+	GetMentions(ctx context.Context, login string, limit int, offset int) ([]types.Post, error)
+}
+
+type PostRenderer interface {
+	RenderPost(post *types.Post) error
+}
+
+// @Summary     Mentions
+// @Security    ApiKeyAuth
+// @Tags        user
+// @Description get posts where the user is mentioned, newest mentions first;
+// @Description posts hidden from the user are skipped
+// @ID          mentions
+// @Accept      json
+// @Produde     json
+// @Param       limit   query    int false "page size, 20 by default, 100 at most"
+// @Param       offset  query    int false "offset of the page"
+// @Success     200     {object} models.MentionsSuccess
+// @Failure     400,500 {object} models.Problem
+// @Router      /v1/user/me/mentions [get]
+func New(log *slog.Logger, mentionsGetter MentionsGetter, postRenderer PostRenderer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.user.mentions.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		page, err := pagination.Parse(r)
+		if err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, err.Error())
+
+			return
+		}
+
+		login := r.Header.Get("login")
+
+		// запрашиваем на один пост больше, чтобы понять, есть ли следующая страница
+		posts, err := mentionsGetter.GetMentions(ctx, login, page.Limit+1, page.Offset)
+		if err != nil {
+			log.Error("failed to get mentions", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to get mentions")
+
+			return
+		}
+
+		next := page.Next(len(posts))
+		if next != nil {
+			posts = posts[:page.Limit]
+		}
+
+		for i := range posts {
+			if err := postRenderer.RenderPost(&posts[i]); err != nil {
+				log.Error("failed to render post", slog.Int64("id", posts[i].ID), sl.Err(err))
+
+				resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to render post")
+
+				return
+			}
+		}
+
+		log.Info("mentions got", slog.Int("count", len(posts)))
+
+		render.JSON(w, r, Response{
+			Response:   resp.OK(),
+			Posts:      posts,
+			NextOffset: next,
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package mute
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/storage"
+)
+
+type Response struct {
+	resp.Response
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=UserMuter
+type UserMuter interface {
+	IsUserExist(ctx context.Context, login string) (bool, error)
+	Mute(ctx context.Context, muter string, muted string, date_created string) error
+}
+
+// @Summary     Mute
+// @Security    ApiKeyAuth
+// @Tags        user
+// @Description mute a user: their posts and reposts of their posts are hidden from your mentions,
+// @Description lists of posts and live events, and you don't get notifications about their actions
+// @ID          mute
+// @Accept      json
+// @Produde     json
+// @Param       login   path     string true "login of a user to mute"
+// @Success     200     {object} models.MuteSuccess
+// @Failure     400,404,500 {object} models.Problem
+// @Router      /v1/user/{login}/mute [put]
+func New(log *slog.Logger, userMuter UserMuter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.user.mute.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		muted := strings.TrimSpace(chi.URLParam(r, "login"))
+		muter := r.Header.Get("login")
+
+		if muted == muter {
+			log.Error("invalid request", sl.Err(fmt.Errorf("user can't mute themselves")))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeSelfAction, "you can't mute yourself")
+
+			return
+		}
+
+		exist, err := userMuter.IsUserExist(ctx, muted)
+		if err != nil {
+			log.Error("failed to check if user exists", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to check if user exists")
+
+			return
+		}
+
+		if !exist {
+			log.Error("invalid request", sl.Err(fmt.Errorf("user doesn't exist: %s", muted)))
+
+			resp.Fail(w, r, storage.ErrNotFound, resp.CodeUserNotFound, "user doesn't exist")
+
+			return
+		}
+
+		date_created := time.Now().Format("2006-01-02 15:04:05")
+
+		if err := userMuter.Mute(ctx, muter, muted, date_created); err != nil {
+			log.Error("failed to mute user", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to mute user")
+
+			return
+		}
+
+		log.Info("user muted", slog.String("muted", muted))
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+		})
+	}
+}
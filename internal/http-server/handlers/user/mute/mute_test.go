@@ -0,0 +1,106 @@
+package mute_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/user/mute"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/user/mute/mocks"
+	"github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMuteHandler(t *testing.T) {
+	testCases := []struct {
+		name       string
+		login      string
+		exist      bool
+		respError  string
+		mockError  error
+		statusCode int
+	}{
+		{
+			name:       "Success",
+			login:      "other_user",
+			exist:      true,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Mute yourself",
+			login:      "test_user",
+			respError:  "you can't mute yourself",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Missing user",
+			login:      "other_user",
+			respError:  "user doesn't exist",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Mute Error",
+			login:      "other_user",
+			exist:      true,
+			respError:  "failed to mute user",
+			mockError:  errors.New("unexpected error"),
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			userMuterMock := mocks.NewUserMuter(t)
+
+			if tc.login != "test_user" {
+				userMuterMock.On("IsUserExist", mock.Anything, tc.login).
+					Return(tc.exist, nil).
+					Once()
+			}
+
+			if tc.exist {
+				userMuterMock.On("Mute", mock.Anything, "test_user", tc.login, mock.AnythingOfType("string")).
+					Return(tc.mockError).
+					Once()
+			}
+
+			router := chi.NewRouter()
+			router.Put("/user/{login}/mute", mute.New(loggerdiscard.NewDiscardLogger(), userMuterMock))
+
+			req, err := http.NewRequest(http.MethodPut, "/user/"+tc.login+"/mute", nil)
+			require.NoError(t, err)
+
+			req.Header.Add("login", "test_user")
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			require.Equal(t, tc.statusCode, recorder.Code)
+
+			if tc.respError != "" {
+				var problem response.Problem
+
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+
+				require.Equal(t, tc.respError, problem.Detail)
+
+				return
+			}
+
+			var resp mute.Response
+
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+
+			require.Equal(t, response.StatusOK, resp.Status)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+type Response struct {
+	resp.Response
+	Profile *types.Profile `json:"profile,omitempty"`
+}
+
+type ProfileGetter interface {
+	GetProfile(ctx context.Context, login string, viewer string) (*types.Profile, error)
+}
+
+// @Summary     Profile
+// @Tags        user
+// @Description get user's profile with the number of posts, received likes, followers and followings;
+// @Description posts and likes are counted only over the posts visible to the viewer;
+// @Description GET /user/me/profile returns the profile of the authorized user
+// @ID          profile
+// @Accept      json
+// @Produde     json
+// @Param       login       path     string true "login of a user"
+// @Success     200         {object} models.ProfileSuccess
+// @Failure     400,404,500 {object} models.Problem
+// @Router      /v1/user/{login}/profile [get]
+func New(log *slog.Logger, profileGetter ProfileGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.user.profile.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		viewer := r.Header.Get("login")
+
+		// на /user/me/profile логина в пути нет - это профиль самого пользователя
+		login := strings.TrimSpace(chi.URLParam(r, "login"))
+		if login == "" {
+			login = viewer
+		}
+
+		if login == "" {
+			log.Error("invalid request", sl.Err(fmt.Errorf("login is empty")))
+
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, "invalid login")
+
+			return
+		}
+
+		profile, err := profileGetter.GetProfile(ctx, login, viewer)
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Error("invalid request", sl.Err(fmt.Errorf("user doesn't exist: %s", login)))
+
+			resp.Fail(w, r, storage.ErrNotFound, resp.CodeUserNotFound, "user doesn't exist")
+
+			return
+		} else if err != nil {
+			log.Error("failed to get profile", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to get profile")
+
+			return
+		}
+
+		log.Info("profile got", slog.String("login", login))
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+			Profile:  profile,
+		})
+	}
+}
@@ -0,0 +1,29 @@
+// Code generated by mockery v2.30.0. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// RegistrationCounter is an autogenerated mock type for the RegistrationCounter type
+type RegistrationCounter struct {
+	mock.Mock
+}
+
+// UserRegistered provides a mock function with given fields:
+func (_m *RegistrationCounter) UserRegistered() {
+	_m.Called()
+}
+
+// NewRegistrationCounter creates a new instance of RegistrationCounter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRegistrationCounter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RegistrationCounter {
+	mock := &RegistrationCounter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
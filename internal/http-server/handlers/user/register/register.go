@@ -15,6 +15,7 @@ import (
 	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
 	"github.com/solumD/go-blog-api/internal/lib/password"
 	"github.com/solumD/go-blog-api/internal/lib/validator"
+	"github.com/solumD/go-blog-api/internal/storage"
 )
 
 type Request struct {
@@ -33,6 +34,11 @@ type UserRegistrar interface {
 	SaveUser(ctx context.Context, login string, password string, date_created string) (int64, error)
 }
 
+//go:generate go run github.com/vektra/mockery/v2@v2.30.0 --name=RegistrationCounter
+type RegistrationCounter interface {
+	UserRegistered()
+}
+
 // @Summary     Register
 // @Tags        auth
 // @Description register
@@ -41,18 +47,19 @@ type UserRegistrar interface {
 // @Produde     json
 // @Param       input   body     Request true "account info"
 // @Success     200     {object} models.RegisterSuccess
-// @Failure     400,500 {object} models.RegisterError
-// @Router      /auth/register [post]
-func New(ctx context.Context, log *slog.Logger, userRegistrar UserRegistrar) http.HandlerFunc {
+// @Failure     400,409,500 {object} models.Problem
+// @Router      /v1/auth/register [post]
+func New(log *slog.Logger, userRegistrar UserRegistrar, registrationCounter RegistrationCounter) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const fn = "handlers.register.New"
 
-		log = log.With(
+		log := log.With(
 			slog.String("fn", fn),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
 		)
 
-		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 		defer cancel()
 
 		var req Request
@@ -61,8 +68,7 @@ func New(ctx context.Context, log *slog.Logger, userRegistrar UserRegistrar) htt
 		if err != nil {
 			log.Error("failed to decode request", sl.Err(err))
 
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("failed to decode request"))
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidJSON, "failed to decode request")
 
 			return
 		}
@@ -74,8 +80,7 @@ func New(ctx context.Context, log *slog.Logger, userRegistrar UserRegistrar) htt
 		if err := validator.ValidateLogin(req.Login); err != nil {
 			log.Error("invalid request", sl.Err(err))
 
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(err.Error()))
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, err.Error())
 
 			return
 		}
@@ -83,8 +88,7 @@ func New(ctx context.Context, log *slog.Logger, userRegistrar UserRegistrar) htt
 		if err := validator.ValidatePassword(req.Password); err != nil {
 			log.Error("invalid request", sl.Err(err))
 
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(err.Error()))
+			resp.Fail(w, r, resp.ErrInvalid, resp.CodeValidationFailed, err.Error())
 
 			return
 		}
@@ -94,8 +98,7 @@ func New(ctx context.Context, log *slog.Logger, userRegistrar UserRegistrar) htt
 		if err != nil {
 			log.Error("failed to check if user exists", sl.Err(err))
 
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to check if user exists"))
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to check if user exists")
 
 			return
 		}
@@ -103,8 +106,7 @@ func New(ctx context.Context, log *slog.Logger, userRegistrar UserRegistrar) htt
 		if exist {
 			log.Error("invalid request", sl.Err(fmt.Errorf("user already exists")))
 
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("user already exists"))
+			resp.Fail(w, r, storage.ErrConflict, resp.CodeUserAlreadyExists, "user already exists")
 
 			return
 		}
@@ -113,8 +115,7 @@ func New(ctx context.Context, log *slog.Logger, userRegistrar UserRegistrar) htt
 		if err != nil {
 			log.Error("failed to encrypt password", sl.Err(err))
 
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to encrypt password"))
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to encrypt password")
 
 			return
 		}
@@ -125,12 +126,13 @@ func New(ctx context.Context, log *slog.Logger, userRegistrar UserRegistrar) htt
 		if err != nil {
 			log.Error("failed to save user", sl.Err(err))
 
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("failed to save user"))
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to save user")
 
 			return
 		}
 
+		registrationCounter.UserRegistered()
+
 		render.JSON(w, r, Response{
 			Response: resp.OK(),
 			ID:       id,
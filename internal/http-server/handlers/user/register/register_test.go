@@ -2,7 +2,6 @@ package register_test
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,6 +11,7 @@ import (
 
 	"github.com/solumD/go-blog-api/internal/http-server/handlers/user/register"
 	"github.com/solumD/go-blog-api/internal/http-server/handlers/user/register/mocks"
+	"github.com/solumD/go-blog-api/internal/lib/api/response"
 	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -82,7 +82,7 @@ func TestRegisterHandler(t *testing.T) {
 			login:      "VeryCoolLogin",
 			password:   "VeryCoolPassword",
 			respError:  "user already exists",
-			statusCode: http.StatusBadRequest,
+			statusCode: http.StatusConflict,
 		},
 	}
 
@@ -109,7 +109,13 @@ func TestRegisterHandler(t *testing.T) {
 					Once()
 			}
 
-			handler := register.New(context.Background(), loggerdiscard.NewDiscardLogger(), userRegistrarMock)
+			registrationCounterMock := mocks.NewRegistrationCounter(t)
+
+			if tc.respError == "" {
+				registrationCounterMock.On("UserRegistered").Once()
+			}
+
+			handler := register.New(loggerdiscard.NewDiscardLogger(), userRegistrarMock, registrationCounterMock)
 
 			input := fmt.Sprintf(`{"login": "%s", "password": "%s"}`, tc.login, tc.password)
 
@@ -123,11 +129,21 @@ func TestRegisterHandler(t *testing.T) {
 
 			body := recorder.Body.String()
 
+			if tc.respError != "" {
+				var problem response.Problem
+
+				require.NoError(t, json.Unmarshal([]byte(body), &problem))
+
+				require.Equal(t, tc.respError, problem.Detail)
+
+				return
+			}
+
 			var resp register.Response
 
 			require.NoError(t, json.Unmarshal([]byte(body), &resp))
 
-			require.Equal(t, tc.respError, resp.Error)
+			require.Equal(t, response.StatusOK, resp.Status)
 		})
 	}
 }
@@ -0,0 +1,67 @@
+package unblock
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+)
+
+type Response struct {
+	resp.Response
+}
+
+type UserUnblocker interface {
+	Unblock(ctx context.Context, blocker string, blocked string) error
+}
+
+// @Summary     Unblock
+// @Security    ApiKeyAuth
+// @Tags        user
+// @Description unblock a user, follows removed by the block are not restored
+// @ID          unblock
+// @Accept      json
+// @Produde     json
+// @Param       login   path     string true "login of a user to unblock"
+// @Success     200     {object} models.UnblockSuccess
+// @Failure     500 {object} models.Problem
+// @Router      /v1/user/{login}/block [delete]
+func New(log *slog.Logger, userUnblocker UserUnblocker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.user.unblock.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		blocked := strings.TrimSpace(chi.URLParam(r, "login"))
+		blocker := r.Header.Get("login")
+
+		if err := userUnblocker.Unblock(ctx, blocker, blocked); err != nil {
+			log.Error("failed to unblock user", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to unblock user")
+
+			return
+		}
+
+		log.Info("user unblocked", slog.String("blocked", blocked))
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+		})
+	}
+}
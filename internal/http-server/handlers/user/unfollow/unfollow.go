@@ -0,0 +1,87 @@
+package unfollow
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/storage"
+)
+
+type Response struct {
+	resp.Response
+}
+
+type UserUnfollower interface {
+	IsFollowing(ctx context.Context, follower string, followee string) (bool, error)
+	Unfollow(ctx context.Context, follower string, followee string) error
+}
+
+// @Summary     Unfollow
+// @Security    ApiKeyAuth
+// @Tags        user
+// @Description unfollow a user
+// @ID          unfollow
+// @Accept      json
+// @Produde     json
+// @Param       login   path     string true "login of a user to unfollow"
+// @Success     200     {object} models.UnfollowSuccess
+// @Failure     409,500 {object} models.Problem
+// @Router      /v1/user/{login}/follow [delete]
+func New(log *slog.Logger, userUnfollower UserUnfollower) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.user.unfollow.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		followee := strings.TrimSpace(chi.URLParam(r, "login"))
+		follower := r.Header.Get("login")
+
+		following, err := userUnfollower.IsFollowing(ctx, follower, followee)
+		if err != nil {
+			log.Error("failed to check if user is followed", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to check if user is followed")
+
+			return
+		}
+
+		if !following {
+			log.Error("invalid request", sl.Err(fmt.Errorf("you don't follow %s", followee)))
+
+			resp.Fail(w, r, storage.ErrConflict, resp.CodeNotFollowing, fmt.Sprintf("you don't follow %s", followee))
+
+			return
+		}
+
+		if err := userUnfollower.Unfollow(ctx, follower, followee); err != nil {
+			log.Error("failed to unfollow user", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to unfollow user")
+
+			return
+		}
+
+		log.Info("user unfollowed", slog.String("followee", followee))
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package unmute
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	_ "github.com/solumD/go-blog-api/internal/http-server/models"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+)
+
+type Response struct {
+	resp.Response
+}
+
+type UserUnmuter interface {
+	Unmute(ctx context.Context, muter string, muted string) error
+}
+
+// @Summary     Unmute
+// @Security    ApiKeyAuth
+// @Tags        user
+// @Description unmute a user
+// @ID          unmute
+// @Accept      json
+// @Produde     json
+// @Param       login   path     string true "login of a user to unmute"
+// @Success     200     {object} models.UnmuteSuccess
+// @Failure     500 {object} models.Problem
+// @Router      /v1/user/{login}/mute [delete]
+func New(log *slog.Logger, userUnmuter UserUnmuter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const fn = "handlers.user.unmute.New"
+
+		log := log.With(
+			slog.String("fn", fn),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.Trace(r.Context()),
+		)
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		muted := strings.TrimSpace(chi.URLParam(r, "login"))
+		muter := r.Header.Get("login")
+
+		if err := userUnmuter.Unmute(ctx, muter, muted); err != nil {
+			log.Error("failed to unmute user", sl.Err(err))
+
+			resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to unmute user")
+
+			return
+		}
+
+		log.Info("user unmuted", slog.String("muted", muted))
+
+		render.JSON(w, r, Response{
+			Response: resp.OK(),
+		})
+	}
+}
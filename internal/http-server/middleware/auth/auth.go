@@ -6,7 +6,6 @@ import (
 	"net/http"
 	"strings"
 
-	"github.com/go-chi/render"
 	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
 	"github.com/solumD/go-blog-api/internal/lib/jwt"
 	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
@@ -21,6 +20,17 @@ type Response struct {
 // для будущих операций и направляет запрос на следующий хэндлер,
 // иначе - отвечает на запрос ошибкой.
 func New(secret string, log *slog.Logger) func(next http.Handler) http.Handler {
+	return newAuth(secret, log, true)
+}
+
+// NewOptional работает как New, но пропускает запросы без хэдера Authorization.
+// У таких запросов хэдер Login пустой, и хэндлер обрабатывает их как запросы анонима.
+// Невалидный токен по-прежнему приводит к ошибке.
+func NewOptional(secret string, log *slog.Logger) func(next http.Handler) http.Handler {
+	return newAuth(secret, log, false)
+}
+
+func newAuth(secret string, log *slog.Logger, required bool) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		log := log.With(
 			slog.String("component", "middleware/auth"),
@@ -29,21 +39,43 @@ func New(secret string, log *slog.Logger) func(next http.Handler) http.Handler {
 		fn := func(w http.ResponseWriter, r *http.Request) {
 			log.Info("middleware auth used")
 
+			// логин пользователя может установить только этот middleware
+			r.Header.Del("Login")
+
 			auth := r.Header.Get("Authorization")
-			token := strings.Split(auth, " ")[1]
+			if auth == "" && !required {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			scheme, token, ok := strings.Cut(auth, " ")
+			if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+				log.Error("invalid authorization header")
+
+				resp.Fail(w, r, resp.ErrUnauthorized, resp.CodeUnauthorized, "authorization header must be in format: Bearer <token>")
+
+				return
+			}
 
 			claims, err := jwt.GetTokenClaims(secret, token)
 			if err != nil {
 				log.Error("invalid jwt token", sl.Err(err))
 
-				render.Status(r, http.StatusUnauthorized)
-				render.JSON(w, r, resp.Error(fmt.Sprintf("%v", err)))
+				resp.Fail(w, r, resp.ErrUnauthorized, resp.CodeUnauthorized, fmt.Sprintf("%v", err))
+
+				return
+			}
+
+			login, ok := claims["sub"].(string)
+			if !ok || login == "" {
+				log.Error("invalid jwt token", sl.Err(fmt.Errorf("token has no subject")))
+
+				resp.Fail(w, r, resp.ErrUnauthorized, resp.CodeUnauthorized, "token has no subject")
 
 				return
 			}
 
-			login := claims["sub"].(string)
-			r.Header.Add("Login", login)
+			r.Header.Set("Login", login)
 
 			next.ServeHTTP(w, r)
 		}
@@ -0,0 +1,117 @@
+package mwAuth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mwAuth "github.com/solumD/go-blog-api/internal/http-server/middleware/auth"
+	"github.com/solumD/go-blog-api/internal/lib/jwt"
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/stretchr/testify/require"
+)
+
+const secret = "test secret"
+
+func TestAuth(t *testing.T) {
+	token, err := jwt.GenerateToken("test_user", secret)
+	require.NoError(t, err)
+
+	wrongToken, err := jwt.GenerateToken("test_user", "another secret")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		optional      bool
+		authorization string
+		spoofedLogin  string
+		statusCode    int
+		login         string
+	}{
+		{
+			name:          "Valid token",
+			authorization: "Bearer " + token,
+			statusCode:    http.StatusOK,
+			login:         "test_user",
+		},
+		{
+			name:       "Missing header",
+			statusCode: http.StatusUnauthorized,
+		},
+		{
+			name:          "Malformed header",
+			authorization: token,
+			statusCode:    http.StatusUnauthorized,
+		},
+		{
+			name:          "Wrong secret",
+			authorization: "Bearer " + wrongToken,
+			statusCode:    http.StatusUnauthorized,
+		},
+		{
+			name:          "Spoofed login is replaced",
+			authorization: "Bearer " + token,
+			spoofedLogin:  "admin_user",
+			statusCode:    http.StatusOK,
+			login:         "test_user",
+		},
+		{
+			name:       "Optional without header",
+			optional:   true,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:         "Optional spoofed login is dropped",
+			optional:     true,
+			spoofedLogin: "admin_user",
+			statusCode:   http.StatusOK,
+		},
+		{
+			name:          "Optional valid token",
+			optional:      true,
+			authorization: "Bearer " + token,
+			statusCode:    http.StatusOK,
+			login:         "test_user",
+		},
+		{
+			name:          "Optional wrong secret",
+			optional:      true,
+			authorization: "Bearer " + wrongToken,
+			statusCode:    http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mw := mwAuth.New(secret, loggerdiscard.NewDiscardLogger())
+			if tc.optional {
+				mw = mwAuth.NewOptional(secret, loggerdiscard.NewDiscardLogger())
+			}
+
+			var login string
+			handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				login = r.Header.Get("login")
+			}))
+
+			req, err := http.NewRequest(http.MethodGet, "/", nil)
+			require.NoError(t, err)
+
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			if tc.spoofedLogin != "" {
+				req.Header.Set("Login", tc.spoofedLogin)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			require.Equal(t, tc.statusCode, recorder.Code)
+			require.Equal(t, tc.login, login)
+		})
+	}
+}
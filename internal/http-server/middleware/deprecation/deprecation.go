@@ -0,0 +1,26 @@
+package mwDeprecation
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// New отмечает ответы устаревших маршрутов хэдерами Deprecation (RFC 9745) - с какого
+// момента маршрут устарел, и Sunset (RFC 8594) - когда он перестанет работать.
+func New(deprecatedAt time.Time, sunset time.Time) func(next http.Handler) http.Handler {
+	deprecation := "@" + strconv.FormatInt(deprecatedAt.Unix(), 10)
+	sunsetAt := sunset.UTC().Format(http.TimeFormat)
+
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			// хэдеры задаются до хэндлера, иначе они не попадут в уже отправленный ответ
+			w.Header().Set("Deprecation", deprecation)
+			w.Header().Set("Sunset", sunsetAt)
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
@@ -0,0 +1,38 @@
+package mwDeprecation_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	mwDeprecation "github.com/solumD/go-blog-api/internal/http-server/middleware/deprecation"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	deprecatedAt := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, time.April, 19, 0, 0, 0, 0, time.UTC)
+
+	router := chi.NewRouter()
+	router.With(mwDeprecation.New(deprecatedAt, sunset)).
+		Post("/post/create", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		})
+	router.Post("/v1/posts", func(w http.ResponseWriter, r *http.Request) {})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/post/create", nil))
+
+	// хэдеры есть и в ответах с ошибкой
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Equal(t, "@1792368000", recorder.Header().Get("Deprecation"))
+	require.Equal(t, "Mon, 19 Apr 2027 00:00:00 GMT", recorder.Header().Get("Sunset"))
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/v1/posts", nil))
+
+	require.Empty(t, recorder.Header().Get("Deprecation"))
+	require.Empty(t, recorder.Header().Get("Sunset"))
+}
@@ -0,0 +1,195 @@
+package mwIdempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+const (
+	// HeaderKey - хэдер, в котором клиент передает ключ идемпотентности.
+	HeaderKey = "Idempotency-Key"
+	// HeaderReplayed есть в ответах, повторенных из хранилища.
+	HeaderReplayed = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+)
+
+// Store хранит ключи идемпотентности пользователей и ответы на первые запросы с ними.
+type Store interface {
+	ReserveIdempotencyKey(ctx context.Context, login string, key string, fingerprint string, expires_at string, now string) (types.IdempotencyRecord, bool, error)
+	SaveIdempotentResponse(ctx context.Context, login string, key string, status int, content_type string, body []byte, expires_at string) error
+	RemoveIdempotencyKey(ctx context.Context, login string, key string) error
+}
+
+// New выполняет POST, PATCH и DELETE запросы с хэдером Idempotency-Key только один раз:
+// ответ на первый запрос хранится ttl и повторяется на запросы с тем же ключом.
+// Пока первый запрос выполняется, ключ занят не дольше lease. Тело запроса с ключом
+// читается целиком, поэтому тело больше maxBodySize байт - ошибка 413.
+// Должен стоять после middleware авторизации, потому что ключи у каждого пользователя свои.
+func New(log *slog.Logger, store Store, ttl time.Duration, lease time.Duration, maxBodySize int64) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		log := log.With(
+			slog.String("component", "middleware/idempotency"),
+		)
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(HeaderKey)
+			login := r.Header.Get("login")
+
+			if key == "" || login == "" || !mutating(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(key) > maxKeyLength {
+				log.Error("invalid idempotency key", slog.Int("length", len(key)))
+
+				resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidIdempotency, "idempotency key must be at most 255 characters")
+
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					log.Error("request body is too large", sl.Err(err))
+
+					resp.Fail(w, r, resp.ErrTooLarge, resp.CodeBodyTooLarge, fmt.Sprintf("request body with idempotency key can't be larger than %d bytes", maxBodySize))
+
+					return
+				}
+
+				log.Error("failed to read request body", sl.Err(err))
+
+				resp.Fail(w, r, resp.ErrInvalid, resp.CodeInvalidBody, "failed to read request body")
+
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			fingerprint := fingerprint(r, body)
+			now := time.Now()
+
+			// до сохранения ответа ключ занят только на lease: если процесс упадет,
+			// ключ не останется занятым на весь ttl
+			record, reserved, err := store.ReserveIdempotencyKey(r.Context(), login, key, fingerprint,
+				now.Add(lease).Format("2006-01-02 15:04:05"), now.Format("2006-01-02 15:04:05"))
+			if err != nil {
+				log.Error("failed to reserve idempotency key", sl.Err(err))
+
+				resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to check idempotency key")
+
+				return
+			}
+
+			if !reserved {
+				replay(log, w, r, record, fingerprint)
+				return
+			}
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			var buf bytes.Buffer
+			ww.Tee(&buf)
+
+			// ответ сохраняется, даже если клиент уже отключился: он повторит запрос
+			ctx := context.WithoutCancel(r.Context())
+
+			saved := false
+			defer func() {
+				// ключ освобождается, если хэндлер запаниковал или ответ не сохранен,
+				// чтобы запрос можно было повторить
+				if saved {
+					return
+				}
+
+				if err := store.RemoveIdempotencyKey(ctx, login, key); err != nil {
+					log.Error("failed to remove idempotency key", sl.Err(err))
+				}
+			}()
+
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			// на временные ошибки повтор запроса должен выполнить его заново
+			if status >= http.StatusInternalServerError || status == http.StatusTooManyRequests {
+				return
+			}
+
+			expires_at := time.Now().Add(ttl).Format("2006-01-02 15:04:05")
+
+			if err := store.SaveIdempotentResponse(ctx, login, key, status, ww.Header().Get("Content-Type"), buf.Bytes(), expires_at); err != nil {
+				log.Error("failed to save idempotent response", sl.Err(err))
+				return
+			}
+
+			saved = true
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// replay отвечает на повтор запроса сохраненным ответом.
+func replay(log *slog.Logger, w http.ResponseWriter, r *http.Request, record types.IdempotencyRecord, fingerprint string) {
+	if record.Fingerprint != fingerprint {
+		log.Error("idempotency key reused with a different request")
+
+		resp.Fail(w, r, storage.ErrConflict, resp.CodeIdempotencyReused, "idempotency key was already used for a different request")
+
+		return
+	}
+
+	// одновременный повтор: первый запрос еще не выполнен
+	if record.Status == 0 {
+		log.Error("request with idempotency key is in progress")
+
+		w.Header().Set("Retry-After", "1")
+
+		resp.Fail(w, r, storage.ErrConflict, resp.CodeIdempotencyPending, "request with this idempotency key is in progress")
+
+		return
+	}
+
+	log.Info("idempotent response replayed", slog.Int("status", record.Status))
+
+	if record.ContentType != "" {
+		w.Header().Set("Content-Type", record.ContentType)
+	}
+	w.Header().Set(HeaderReplayed, "true")
+	w.WriteHeader(record.Status)
+	w.Write(record.Body)
+}
+
+// fingerprint - хэш метода, пути и тела запроса: с одним ключом можно повторять
+// только тот же самый запрос.
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func mutating(method string) bool {
+	return method == http.MethodPost || method == http.MethodPatch || method == http.MethodDelete
+}
@@ -0,0 +1,244 @@
+package mwIdempotency_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	mwIdempotency "github.com/solumD/go-blog-api/internal/http-server/middleware/idempotency"
+	"github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/idempotency"
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/stretchr/testify/require"
+)
+
+func request(t *testing.T, handler http.Handler, method string, login string, key string, body string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(method, "/v1/posts", strings.NewReader(body))
+	require.NoError(t, err)
+
+	if login != "" {
+		req.Header.Set("login", login)
+	}
+	if key != "" {
+		req.Header.Set(mwIdempotency.HeaderKey, key)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	return recorder
+}
+
+func problemCode(t *testing.T, recorder *httptest.ResponseRecorder) string {
+	var problem response.Problem
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+
+	return problem.Code
+}
+
+func TestNew(t *testing.T) {
+	var calls atomic.Int64
+
+	handler := mwIdempotency.New(loggerdiscard.NewDiscardLogger(), idempotency.NewMemory(), time.Hour, time.Minute, 1<<20)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := calls.Add(1)
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"id":%d}`, n)
+		}),
+	)
+
+	rec := request(t, handler, http.MethodPost, "test_user", "key-1", `{"title":"title"}`)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	require.Equal(t, `{"id":1}`, rec.Body.String())
+	require.Empty(t, rec.Header().Get(mwIdempotency.HeaderReplayed))
+
+	// повтор получает тот же ответ, а хэндлер не вызывается
+	rec = request(t, handler, http.MethodPost, "test_user", "key-1", `{"title":"title"}`)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	require.Equal(t, `{"id":1}`, rec.Body.String())
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	require.Equal(t, "true", rec.Header().Get(mwIdempotency.HeaderReplayed))
+	require.Equal(t, int64(1), calls.Load())
+
+	// тот же ключ с другим запросом
+	rec = request(t, handler, http.MethodPost, "test_user", "key-1", `{"title":"other"}`)
+	require.Equal(t, http.StatusConflict, rec.Code)
+	require.Equal(t, response.CodeIdempotencyReused, problemCode(t, rec))
+
+	// ключи у каждого пользователя свои
+	rec = request(t, handler, http.MethodPost, "other_user", "key-1", `{"title":"title"}`)
+	require.Equal(t, `{"id":2}`, rec.Body.String())
+
+	// без ключа, без авторизации и для GET запросы выполняются каждый раз
+	request(t, handler, http.MethodPost, "test_user", "", `{"title":"title"}`)
+	request(t, handler, http.MethodPost, "", "key-1", `{"title":"title"}`)
+	request(t, handler, http.MethodGet, "test_user", "key-1", "")
+	require.Equal(t, int64(5), calls.Load())
+
+	rec = request(t, handler, http.MethodPost, "test_user", strings.Repeat("k", 256), `{"title":"title"}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Equal(t, response.CodeInvalidIdempotency, problemCode(t, rec))
+}
+
+func TestNewServerError(t *testing.T) {
+	var calls atomic.Int64
+
+	handler := mwIdempotency.New(loggerdiscard.NewDiscardLogger(), idempotency.NewMemory(), time.Hour, time.Minute, 1<<20)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+
+	// после ошибки сервера повтор выполняет запрос заново
+	rec := request(t, handler, http.MethodDelete, "test_user", "key-1", "")
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	rec = request(t, handler, http.MethodDelete, "test_user", "key-1", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Empty(t, rec.Header().Get(mwIdempotency.HeaderReplayed))
+
+	rec = request(t, handler, http.MethodDelete, "test_user", "key-1", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "true", rec.Header().Get(mwIdempotency.HeaderReplayed))
+	require.Equal(t, int64(2), calls.Load())
+}
+
+func TestNewConcurrent(t *testing.T) {
+	var calls atomic.Int64
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	handler := mwIdempotency.New(loggerdiscard.NewDiscardLogger(), idempotency.NewMemory(), time.Hour, time.Minute, 1<<20)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			close(started)
+			<-release
+
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		done <- request(t, handler, http.MethodPost, "test_user", "key-1", `{}`)
+	}()
+
+	<-started
+
+	// пока первый запрос выполняется, повтор не выполняет его второй раз
+	rec := request(t, handler, http.MethodPost, "test_user", "key-1", `{}`)
+	require.Equal(t, http.StatusConflict, rec.Code)
+	require.Equal(t, "1", rec.Header().Get("Retry-After"))
+	require.Equal(t, response.CodeIdempotencyPending, problemCode(t, rec))
+
+	close(release)
+	require.Equal(t, http.StatusOK, (<-done).Code)
+
+	rec = request(t, handler, http.MethodPost, "test_user", "key-1", `{}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "true", rec.Header().Get(mwIdempotency.HeaderReplayed))
+	require.Equal(t, int64(1), calls.Load())
+}
+
+func TestNewBodyTooLarge(t *testing.T) {
+	var calls atomic.Int64
+
+	handler := mwIdempotency.New(loggerdiscard.NewDiscardLogger(), idempotency.NewMemory(), time.Hour, time.Minute, 16)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+
+	// тело не читается в память целиком, если оно больше лимита
+	rec := request(t, handler, http.MethodPost, "test_user", "key-1", strings.Repeat("a", 17))
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	require.Equal(t, response.CodeBodyTooLarge, problemCode(t, rec))
+	require.Zero(t, calls.Load())
+
+	rec = request(t, handler, http.MethodPost, "test_user", "key-1", strings.Repeat("a", 16))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// без ключа тело не читается, и лимит не применяется
+	rec = request(t, handler, http.MethodPost, "test_user", "", strings.Repeat("a", 17))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, int64(2), calls.Load())
+}
+
+func TestNewPanic(t *testing.T) {
+	var calls atomic.Int64
+
+	handler := mwIdempotency.New(loggerdiscard.NewDiscardLogger(), idempotency.NewMemory(), time.Hour, time.Minute, 1<<20)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				panic("unexpected error")
+			}
+
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+
+	// панику перехватывает middleware выше, ключ к этому времени уже освобожден
+	require.Panics(t, func() {
+		request(t, handler, http.MethodPost, "test_user", "key-1", `{}`)
+	})
+
+	rec := request(t, handler, http.MethodPost, "test_user", "key-1", `{}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Empty(t, rec.Header().Get(mwIdempotency.HeaderReplayed))
+	require.Equal(t, int64(2), calls.Load())
+}
+
+func TestNewLease(t *testing.T) {
+	var calls atomic.Int64
+
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+
+	// ключ занят выполняющимся запросом не дольше lease, поэтому при нулевом lease
+	// ключ запроса, который так и не завершился, например, из-за остановки процесса, снова свободен
+	handler := mwIdempotency.New(loggerdiscard.NewDiscardLogger(), idempotency.NewMemory(), time.Hour, 0, 1<<20)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				started <- struct{}{}
+				<-release
+			}
+
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		done <- request(t, handler, http.MethodPost, "test_user", "key-1", `{}`)
+	}()
+
+	<-started
+
+	rec := request(t, handler, http.MethodPost, "test_user", "key-1", `{}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Empty(t, rec.Header().Get(mwIdempotency.HeaderReplayed))
+
+	close(release)
+	require.Equal(t, http.StatusOK, (<-done).Code)
+
+	// сохраненный ответ хранится ttl, а не lease
+	rec = request(t, handler, http.MethodPost, "test_user", "key-1", `{}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "true", rec.Header().Get(mwIdempotency.HeaderReplayed))
+	require.Equal(t, int64(2), calls.Load())
+}
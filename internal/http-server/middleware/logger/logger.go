@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
 )
 
 // Middleware оборачивает запрос в логгер с детальной информацией
@@ -24,6 +25,7 @@ func New(log *slog.Logger) func(next http.Handler) http.Handler {
 				slog.String("remote_addr", r.RemoteAddr),
 				slog.String("user_agent", r.UserAgent()),
 				slog.String("request_id", middleware.GetReqID(r.Context())),
+				sl.Trace(r.Context()),
 			)
 
 			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
@@ -0,0 +1,47 @@
+package mwMetrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// unmatched - маршрут запросов, для которых не нашлось обработчика.
+const unmatched = "unmatched"
+
+type RequestObserver interface {
+	ObserveRequest(method string, route string, status int, d time.Duration)
+}
+
+// New учитывает каждый запрос по шаблону маршрута chi, а не по пути,
+// чтобы у метрик было ограниченное число значений меток.
+func New(requestObserver RequestObserver) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			start := time.Now()
+			defer func() {
+				// шаблон маршрута известен только после того, как роутер нашел обработчик
+				route := unmatched
+				if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+					route = rctx.RoutePattern()
+				}
+
+				// хэндлер, который ничего не записал, отвечает 200
+				status := ww.Status()
+				if status == 0 {
+					status = http.StatusOK
+				}
+
+				requestObserver.ObserveRequest(r.Method, route, status, time.Since(start))
+			}()
+
+			next.ServeHTTP(ww, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
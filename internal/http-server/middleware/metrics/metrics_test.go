@@ -0,0 +1,60 @@
+package mwMetrics_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	mwMetrics "github.com/solumD/go-blog-api/internal/http-server/middleware/metrics"
+	"github.com/solumD/go-blog-api/internal/lib/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+
+	return string(body)
+}
+
+func TestNew(t *testing.T) {
+	m := metrics.New()
+
+	router := chi.NewRouter()
+	router.Use(mwMetrics.New(m))
+	router.Get("/post/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	router.Delete("/post/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/post/1", nil),
+		httptest.NewRequest(http.MethodGet, "/post/2", nil),
+		httptest.NewRequest(http.MethodDelete, "/post/3", nil),
+		httptest.NewRequest(http.MethodGet, "/unknown/path", nil),
+	} {
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	m.LoginAttempted(true)
+	m.LoginAttempted(false)
+	m.LoginAttempted(false)
+
+	body := scrape(t, m)
+
+	// запросы считаются по шаблону маршрута, а не по пути
+	require.Contains(t, body, `blog_http_requests_total{method="GET",route="/post/{id}",status="200"} 2`)
+	require.Contains(t, body, `blog_http_requests_total{method="DELETE",route="/post/{id}",status="403"} 1`)
+	require.Contains(t, body, `blog_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	require.Contains(t, body, `blog_http_request_duration_seconds_count{method="GET",route="/post/{id}",status="200"} 2`)
+	require.NotContains(t, body, "/post/1")
+
+	require.Contains(t, body, `blog_logins_total{result="ok"} 1`)
+	require.Contains(t, body, `blog_logins_total{result="failed"} 2`)
+}
@@ -0,0 +1,55 @@
+package mwModerator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/storage"
+)
+
+type ModeratorChecker interface {
+	IsModerator(ctx context.Context, login string) (bool, error)
+}
+
+// New пропускает к следующему хэндлеру только запросы модераторов.
+// Должен стоять после middleware авторизации, который кладет логин в хэдер Login.
+func New(log *slog.Logger, moderatorChecker ModeratorChecker) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		log := log.With(
+			slog.String("component", "middleware/moderator"),
+		)
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+			defer cancel()
+
+			login := r.Header.Get("login")
+
+			moderator, err := moderatorChecker.IsModerator(ctx, login)
+			if err != nil {
+				log.Error("failed to check if user is a moderator", sl.Err(err))
+
+				resp.Fail(w, r, resp.ErrInternal, resp.CodeInternal, "failed to check if user is a moderator")
+
+				return
+			}
+
+			if !moderator {
+				log.Error("access denied", sl.Err(fmt.Errorf("%s is not a moderator", login)))
+
+				resp.Fail(w, r, storage.ErrForbidden, resp.CodeModeratorRequired, "only moderators can do this")
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
@@ -0,0 +1,71 @@
+package mwModerator_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mwModerator "github.com/solumD/go-blog-api/internal/http-server/middleware/moderator"
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/stretchr/testify/require"
+)
+
+type checker map[string]bool
+
+func (c checker) IsModerator(ctx context.Context, login string) (bool, error) {
+	if login == "broken_user" {
+		return false, errors.New("unexpected error")
+	}
+
+	return c[login], nil
+}
+
+func TestModerator(t *testing.T) {
+	testCases := []struct {
+		name       string
+		login      string
+		statusCode int
+	}{
+		{
+			name:       "Moderator",
+			login:      "mod_user",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Not a moderator",
+			login:      "test_user",
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "IsModerator Error",
+			login:      "broken_user",
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := mwModerator.New(loggerdiscard.NewDiscardLogger(), checker{"mod_user": true})(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+				}),
+			)
+
+			req, err := http.NewRequest(http.MethodGet, "/mod/reports", nil)
+			require.NoError(t, err)
+
+			req.Header.Set("login", tc.login)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			require.Equal(t, tc.statusCode, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package mwRatelimit
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/lib/ratelimit"
+)
+
+// Store хранит ведра токенов. Ключ включает маршрут, поэтому
+// у одного ключа всегда одно и то же ограничение.
+type Store interface {
+	Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error)
+}
+
+// Limiter ограничивает частоту запросов к маршрутам по ограничениям из конфига.
+type Limiter struct {
+	log    *slog.Logger
+	store  Store
+	limits map[string]ratelimit.Limit
+}
+
+// New создает ограничитель с ограничениями limits по названиям маршрутов.
+func New(log *slog.Logger, store Store, limits map[string]ratelimit.Limit) *Limiter {
+	return &Limiter{
+		log:    log.With(slog.String("component", "middleware/ratelimit")),
+		store:  store,
+		limits: limits,
+	}
+}
+
+// ByUser возвращает middleware, который ограничивает запросы к маршруту route:
+// запросы авторизованного пользователя считаются по логину, анонимные - по IP.
+// Должен стоять после middleware авторизации, иначе хэдер Login может подделать клиент.
+func (l *Limiter) ByUser(route string) func(next http.Handler) http.Handler {
+	return l.limit(route, func(r *http.Request) string {
+		if login := r.Header.Get("login"); login != "" {
+			return "user:" + login
+		}
+
+		return clientIP(r)
+	})
+}
+
+// ByIP возвращает middleware, который ограничивает запросы к маршруту route по IP.
+func (l *Limiter) ByIP(route string) func(next http.Handler) http.Handler {
+	return l.limit(route, clientIP)
+}
+
+// limit ограничивает запросы к маршруту route с одинаковым ключом clientKey.
+// Если для маршрута нет ограничения в конфиге, запросы не ограничиваются.
+func (l *Limiter) limit(route string, clientKey func(r *http.Request) string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		limit, ok := l.limits[route]
+		if !ok || limit.Requests <= 0 || limit.Per <= 0 {
+			return next
+		}
+
+		log := l.log.With(slog.String("route", route))
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			key := route + ":" + clientKey(r)
+
+			res, err := l.store.Take(r.Context(), key, limit)
+			if err != nil {
+				// недоступное хранилище не должно останавливать сервис
+				log.Error("failed to check rate limit", sl.Err(err))
+
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(limit.Capacity()))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			w.Header().Set("RateLimit-Reset", ceilSeconds(res.Reset))
+
+			if !res.Allowed {
+				log.Error("rate limit exceeded", slog.String("key", key))
+
+				w.Header().Set("Retry-After", ceilSeconds(res.RetryAfter))
+
+				resp.Fail(w, r, resp.ErrTooManyRequests, resp.CodeTooManyRequests, "too many requests")
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// clientIP возвращает ключ по IP клиента.
+func clientIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	return "ip:" + ip
+}
+
+// ceilSeconds округляет длительность вверх до целых секунд.
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
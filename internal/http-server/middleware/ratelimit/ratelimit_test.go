@@ -0,0 +1,107 @@
+package mwRatelimit_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mwRatelimit "github.com/solumD/go-blog-api/internal/http-server/middleware/ratelimit"
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/solumD/go-blog-api/internal/lib/ratelimit"
+	"github.com/stretchr/testify/require"
+)
+
+type brokenStore struct{}
+
+func (brokenStore) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("unexpected error")
+}
+
+var ok = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+func request(t *testing.T, handler http.Handler, login string, ip string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(http.MethodPost, "/post/create", nil)
+	require.NoError(t, err)
+
+	req.RemoteAddr = ip + ":12345"
+	if login != "" {
+		req.Header.Set("login", login)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	return recorder
+}
+
+func TestByUser(t *testing.T) {
+	limiter := mwRatelimit.New(loggerdiscard.NewDiscardLogger(), ratelimit.NewMemory(), map[string]ratelimit.Limit{
+		"post.create": {Requests: 2, Per: time.Minute},
+	})
+	handler := limiter.ByUser("post.create")(ok)
+
+	rec := request(t, handler, "test_user", "10.0.0.1")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "2", rec.Header().Get("RateLimit-Limit"))
+	require.Equal(t, "1", rec.Header().Get("RateLimit-Remaining"))
+	require.Equal(t, "30", rec.Header().Get("RateLimit-Reset"))
+
+	// с другого IP тот же пользователь
+	rec = request(t, handler, "test_user", "10.0.0.2")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = request(t, handler, "test_user", "10.0.0.1")
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.Equal(t, "30", rec.Header().Get("Retry-After"))
+	require.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+	require.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+	require.JSONEq(t, `{"type":"about:blank","title":"Too Many Requests","status":429,"detail":"too many requests","instance":"/post/create","code":"too_many_requests"}`, rec.Body.String())
+
+	// у другого пользователя свое ведро
+	rec = request(t, handler, "another_user", "10.0.0.1")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// анонимные запросы считаются по IP
+	require.Equal(t, http.StatusOK, request(t, handler, "", "10.0.0.1").Code)
+	require.Equal(t, http.StatusOK, request(t, handler, "", "10.0.0.1").Code)
+	require.Equal(t, http.StatusTooManyRequests, request(t, handler, "", "10.0.0.1").Code)
+}
+
+func TestByIP(t *testing.T) {
+	limiter := mwRatelimit.New(loggerdiscard.NewDiscardLogger(), ratelimit.NewMemory(), map[string]ratelimit.Limit{
+		"auth.login": {Requests: 1, Per: time.Minute},
+	})
+	handler := limiter.ByIP("auth.login")(ok)
+
+	require.Equal(t, http.StatusOK, request(t, handler, "", "10.0.0.1").Code)
+
+	// подделанный хэдер Login не дает нового ведра
+	require.Equal(t, http.StatusTooManyRequests, request(t, handler, "fake_user", "10.0.0.1").Code)
+
+	require.Equal(t, http.StatusOK, request(t, handler, "", "10.0.0.2").Code)
+}
+
+func TestRouteWithoutLimit(t *testing.T) {
+	limiter := mwRatelimit.New(loggerdiscard.NewDiscardLogger(), ratelimit.NewMemory(), nil)
+	handler := limiter.ByUser("post.create")(ok)
+
+	for i := 0; i < 10; i++ {
+		rec := request(t, handler, "test_user", "10.0.0.1")
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Empty(t, rec.Header().Get("RateLimit-Limit"))
+	}
+}
+
+func TestStoreError(t *testing.T) {
+	limiter := mwRatelimit.New(loggerdiscard.NewDiscardLogger(), brokenStore{}, map[string]ratelimit.Limit{
+		"post.create": {Requests: 1, Per: time.Minute},
+	})
+	handler := limiter.ByUser("post.create")(ok)
+
+	require.Equal(t, http.StatusOK, request(t, handler, "test_user", "10.0.0.1").Code)
+}
@@ -0,0 +1,122 @@
+package mwTracing_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	mwTracing "github.com/solumD/go-blog-api/internal/http-server/middleware/tracing"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func setup(t *testing.T, logs *bytes.Buffer) (*chi.Mux, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	log := slog.New(slog.NewJSONHandler(logs, nil))
+
+	router := chi.NewRouter()
+	router.Use(mwTracing.New(tp))
+	router.Get("/post/{id}", func(w http.ResponseWriter, r *http.Request) {
+		// так же, как хранилище открывает спаны вызовов методов
+		_, span := tp.Tracer("test").Start(r.Context(), "storage.sqlite.GetPost")
+		span.End()
+
+		log.With(sl.Trace(r.Context())).Info("post found")
+	})
+	router.Get("/fail", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	return router, exporter
+}
+
+func attributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+
+	return attrs
+}
+
+func TestNew(t *testing.T) {
+	var logs bytes.Buffer
+	router, exporter := setup(t, &logs)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/post/1", nil))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	child, server := spans[0], spans[1]
+
+	require.Equal(t, "GET /post/{id}", server.Name)
+	require.Equal(t, trace.SpanKindServer, server.SpanKind)
+	require.False(t, server.Parent.IsValid())
+
+	attrs := attributes(server)
+	require.Equal(t, "/post/{id}", attrs["http.route"].AsString())
+	require.Equal(t, "/post/1", attrs["url.path"].AsString())
+	require.Equal(t, int64(http.StatusOK), attrs["http.response.status_code"].AsInt64())
+
+	// вызовы хранилища - дочерние спаны запроса
+	require.Equal(t, "storage.sqlite.GetPost", child.Name)
+	require.Equal(t, server.SpanContext.TraceID(), child.SpanContext.TraceID())
+	require.Equal(t, server.SpanContext.SpanID(), child.Parent.SpanID())
+
+	// в логах обработчика ID трейса и спана запроса
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &record))
+	require.Equal(t, server.SpanContext.TraceID().String(), record["trace_id"])
+	require.Equal(t, server.SpanContext.SpanID().String(), record["span_id"])
+}
+
+func TestNewTraceparent(t *testing.T) {
+	var logs bytes.Buffer
+	router, exporter := setup(t, &logs)
+
+	req := httptest.NewRequest(http.MethodGet, "/post/1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	server := spans[1]
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext.TraceID().String())
+	require.Equal(t, "00f067aa0ba902b7", server.Parent.SpanID().String())
+	require.True(t, server.Parent.IsRemote())
+}
+
+func TestNewServerError(t *testing.T) {
+	var logs bytes.Buffer
+	router, exporter := setup(t, &logs)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	require.Equal(t, "GET /fail", spans[0].Name)
+	require.Equal(t, codes.Error, spans[0].Status.Code)
+
+	// маршрут не найден
+	require.Equal(t, "GET", spans[1].Name)
+	require.Equal(t, int64(http.StatusNotFound), attributes(spans[1])["http.response.status_code"].AsInt64())
+	require.Equal(t, codes.Unset, spans[1].Status.Code)
+}
@@ -4,6 +4,31 @@ import "github.com/solumD/go-blog-api/internal/types"
 
 // Это пакет сделан для генерации swagger-документации
 
+// errors
+
+// Problem - ошибка в формате application/problem+json. С хэдером Accept: application/json
+// ошибки отдаются в прежнем формате {"status": "Error", "error": "..."}.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail"`
+	Instance string `json:"instance"`
+	Code     string `json:"code"`
+}
+
+// BatchAborted - ошибка атомарного пакета с результатами его операций.
+type BatchAborted struct {
+	Problem
+	Results []BatchResult `json:"results"`
+}
+
+type BatchResult struct {
+	Op    string   `json:"op"`
+	ID    int64    `json:"id"`
+	Error *Problem `json:"error"`
+}
+
 // auth
 
 type RegisterSuccess struct {
@@ -11,76 +36,243 @@ type RegisterSuccess struct {
 	ID     int64  `json:"id"`
 }
 
-type RegisterError struct {
+type LoginSuccess struct {
 	Status string `json:"status"`
-	Error  string `json:"error"`
+	Token  string `json:"token"`
 }
 
-type LoginSuccess struct {
+// post
+
+type SaveSuccess struct {
 	Status string `json:"status"`
-	Token  string `json:"token"`
+	ID     int64  `json:"id"`
 }
 
-type LoginError struct {
+type DeleteSuccess struct {
 	Status string `json:"status"`
-	Error  string `json:"error"`
 }
 
-// post
+type UpdateSuccess struct {
+	Status string `json:"status"`
+}
 
-type SaveSuccess struct {
+type LikeSuccess struct {
+	Status string `json:"status"`
+}
+
+type UnlikeSuccess struct {
+	Status string `json:"status"`
+}
+
+type PostsSuccess struct {
+	Status string `json:"status"`
+	types.UsersPosts
+	Message string `json:"message,omitempty"`
+}
+
+type PostSuccess struct {
+	Status string     `json:"status"`
+	Post   types.Post `json:"post"`
+}
+
+type FollowSuccess struct {
+	Status string `json:"status"`
+}
+
+type UnfollowSuccess struct {
+	Status string `json:"status"`
+}
+
+// mentions
+
+type MentionsSuccess struct {
+	Status     string       `json:"status"`
+	Posts      []types.Post `json:"posts"`
+	NextOffset int          `json:"next_offset,omitempty"`
+}
+
+// reposts
+
+type RepostSuccess struct {
 	Status string `json:"status"`
 	ID     int64  `json:"id"`
 }
 
-type SaveError struct {
+type UnrepostSuccess struct {
 	Status string `json:"status"`
-	Error  string `json:"error"`
 }
 
-type DeleteSuccess struct {
+// reactions
+
+type ReactSuccess struct {
 	Status string `json:"status"`
 }
 
-type DeleteError struct {
+type UnreactSuccess struct {
 	Status string `json:"status"`
-	Error  string `json:"error"`
 }
 
-type UpdateSuccess struct {
+// bookmarks
+
+type BookmarkSuccess struct {
 	Status string `json:"status"`
 }
 
-type UpdateError struct {
+type UnbookmarkSuccess struct {
 	Status string `json:"status"`
-	Error  string `json:"error"`
 }
 
-type LikeSuccess struct {
+type BookmarksSuccess struct {
+	Status     string       `json:"status"`
+	Posts      []types.Post `json:"posts"`
+	NextOffset int          `json:"next_offset,omitempty"`
+}
+
+type CollectionsSuccess struct {
+	Status      string                     `json:"status"`
+	Collections []types.BookmarkCollection `json:"collections"`
+}
+
+// notifications
+
+type NotificationsSuccess struct {
+	Status        string               `json:"status"`
+	Notifications []types.Notification `json:"notifications"`
+	Unread        int                  `json:"unread"`
+	NextOffset    int                  `json:"next_offset,omitempty"`
+}
+
+type ReadNotificationsSuccess struct {
 	Status string `json:"status"`
 }
 
-type LikeError struct {
+type NotificationPrefsSuccess struct {
+	Status      string          `json:"status"`
+	Preferences map[string]bool `json:"preferences"`
+}
+
+type SetNotificationPrefsSuccess struct {
 	Status string `json:"status"`
-	Error  string `json:"error"`
 }
 
-type UnlikeSuccess struct {
+// media
+
+type UploadSuccess struct {
 	Status string `json:"status"`
+	types.Media
 }
 
-type UnlikeError struct {
+type CreateConversationSuccess struct {
 	Status string `json:"status"`
-	Error  string `json:"error"`
+	ID     int64  `json:"id"`
 }
 
-type PostsSuccess struct {
+type ConversationsSuccess struct {
+	Status        string               `json:"status"`
+	Conversations []types.Conversation `json:"conversations"`
+	NextOffset    int                  `json:"next_offset,omitempty"`
+}
+
+type ConversationSuccess struct {
+	Status       string             `json:"status"`
+	Conversation types.Conversation `json:"conversation"`
+}
+
+type SendMessageSuccess struct {
 	Status string `json:"status"`
-	types.UsersPosts
-	Message string `json:"message,omitempty"`
+	ID     int64  `json:"id"`
+}
+
+type MessagesSuccess struct {
+	Status     string          `json:"status"`
+	Messages   []types.Message `json:"messages"`
+	NextOffset int             `json:"next_offset,omitempty"`
+}
+
+type ReadConversationSuccess struct {
+	Status     string `json:"status"`
+	LastReadID int64  `json:"last_read_id"`
+}
+
+type ProfileSuccess struct {
+	Status  string        `json:"status"`
+	Profile types.Profile `json:"profile"`
+}
+
+type EditProfileSuccess struct {
+	Status  string        `json:"status"`
+	Profile types.Profile `json:"profile"`
+}
+
+type BlockSuccess struct {
+	Status string `json:"status"`
+}
+
+type UnblockSuccess struct {
+	Status string `json:"status"`
+}
+
+type MuteSuccess struct {
+	Status string `json:"status"`
+}
+
+type UnmuteSuccess struct {
+	Status string `json:"status"`
+}
+
+type ReportSuccess struct {
+	Status string `json:"status"`
+}
+
+type ReportsSuccess struct {
+	Status     string              `json:"status"`
+	Reports    []types.ReportGroup `json:"reports"`
+	NextOffset int                 `json:"next_offset,omitempty"`
+}
+
+type ResolveReportsSuccess struct {
+	Status string `json:"status"`
+}
+
+type ModerationActionsSuccess struct {
+	Status     string                   `json:"status"`
+	Actions    []types.ModerationAction `json:"actions"`
+	NextOffset int                      `json:"next_offset,omitempty"`
 }
 
-type PostsError struct {
+type HealthSuccess struct {
 	Status string `json:"status"`
-	Error  string `json:"error"`
+}
+
+type ReadySuccess struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+type ReadyError struct {
+	Status string            `json:"status"`
+	Error  string            `json:"error"`
+	Checks map[string]string `json:"checks"`
+}
+
+type VersionSuccess struct {
+	Status    string `json:"status"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// graphql
+
+// GraphQLError - ошибка поля или всего запроса. В extensions - код ошибки и HTTP-статус,
+// с которым такая же ошибка вернулась бы из REST обработчика.
+type GraphQLError struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+type GraphQLResponse struct {
+	Data   map[string]interface{} `json:"data,omitempty"`
+	Errors []GraphQLError         `json:"errors,omitempty"`
 }
@@ -0,0 +1,280 @@
+package router
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/solumD/go-blog-api/internal/config"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/conversations/create"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/conversations/details"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/conversations/history"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/conversations/inbox"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/conversations/markread"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/conversations/send"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/graphql"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/health/live"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/health/ready"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/health/version"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/media/download"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/media/upload"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/mod/actions"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/mod/reports"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/mod/resolve"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/notifications/list"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/notifications/prefs"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/notifications/read"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/notifications/setprefs"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/batch"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/bookmark"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/like"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/posts"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/react"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/remove"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/report"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/repost"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/save"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/single"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/unbookmark"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/unlike"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/unreact"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/unrepost"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/update"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/stream/subscribe"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/stream/ws"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/user/block"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/user/bookmarks"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/user/collections"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/user/editprofile"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/user/follow"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/user/login"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/user/mentions"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/user/mute"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/user/profile"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/user/register"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/user/unblock"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/user/unfollow"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/user/unmute"
+	mwAuth "github.com/solumD/go-blog-api/internal/http-server/middleware/auth"
+	mwDeprecation "github.com/solumD/go-blog-api/internal/http-server/middleware/deprecation"
+	mwIdempotency "github.com/solumD/go-blog-api/internal/http-server/middleware/idempotency"
+	mwLogger "github.com/solumD/go-blog-api/internal/http-server/middleware/logger"
+	mwMetrics "github.com/solumD/go-blog-api/internal/http-server/middleware/metrics"
+	mwModerator "github.com/solumD/go-blog-api/internal/http-server/middleware/moderator"
+	mwRatelimit "github.com/solumD/go-blog-api/internal/http-server/middleware/ratelimit"
+	mwTracing "github.com/solumD/go-blog-api/internal/http-server/middleware/tracing"
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/broker"
+	"github.com/solumD/go-blog-api/internal/lib/health"
+	"github.com/solumD/go-blog-api/internal/lib/markdown"
+	"github.com/solumD/go-blog-api/internal/lib/metrics"
+	"github.com/solumD/go-blog-api/internal/lib/notifier"
+	"github.com/solumD/go-blog-api/internal/lib/policy"
+	"github.com/solumD/go-blog-api/internal/lib/stream"
+	"github.com/solumD/go-blog-api/internal/storage/blob"
+	sqlite "github.com/solumD/go-blog-api/internal/storage/sqlite"
+	httpSwagger "github.com/swaggo/http-swagger"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Deps - то, что нужно обработчикам. Создается и останавливается в main.
+type Deps struct {
+	Storage        *sqlite.Storage
+	Blobs          blob.BlobStore
+	Renderer       *markdown.Renderer
+	Policy         *policy.Pipeline
+	Metrics        *metrics.Metrics
+	TracerProvider trace.TracerProvider
+	Limiter        *mwRatelimit.Limiter
+	Notifier       *notifier.Notifier
+	Hub            *stream.Hub
+	Broker         *broker.Broker
+	Checks         *health.Health
+	Idempotency    mwIdempotency.Store
+	// закрывается при остановке сервера, вместе с ним закрываются потоки событий
+	Streams context.Context
+}
+
+type routes struct {
+	cfg *config.Config
+	log *slog.Logger
+	// авторизация, после которой POST, PATCH и DELETE запросы учитывают Idempotency-Key
+	auth func(next http.Handler) http.Handler
+	Deps
+}
+
+// New создает роутер API: маршруты /v1, прежние маршруты без версии
+// с хэдерами Deprecation и Sunset, а также служебные маршруты.
+func New(cfg *config.Config, log *slog.Logger, deps Deps) *chi.Mux {
+	authorize := mwAuth.New(cfg.TokenSecret, log)
+	idempotent := mwIdempotency.New(log, deps.Idempotency, cfg.Idempotency.TTL, cfg.Idempotency.Lease, cfg.Idempotency.MaxBodySize)
+
+	rt := routes{cfg: cfg, log: log, Deps: deps}
+	rt.auth = func(next http.Handler) http.Handler {
+		return authorize(idempotent(next))
+	}
+
+	router := chi.NewRouter()
+
+	// метрики учитывают все запросы, в том числе отклоненные следующими middleware
+	router.Use(mwMetrics.New(deps.Metrics))
+
+	// спан запроса открывается до логгера, чтобы в логах были ID трейса и спана
+	router.Use(mwTracing.New(deps.TracerProvider))
+
+	// инициализируем middleware логгера
+	router.Use(mwLogger.New(log))
+
+	// иниализируем вспомогательные middleware
+	router.Use(middleware.RequestID)
+	router.Use(middleware.Recoverer)
+	router.Use(middleware.URLFormat)
+
+	// ошибки роутера отдаются в том же формате, что и ошибки обработчиков
+	router.NotFound(resp.NotFound)
+	router.MethodNotAllowed(resp.MethodNotAllowed)
+
+	router.Route("/v1", func(r chi.Router) {
+		r.Route("/posts", rt.posts)
+		r.With(rt.auth, rt.Limiter.ByUser("post.batch")).
+			Post("/posts:batch", batch.New(cfg.PostBatch.MaxOperations, log, deps.Storage, deps.Storage, deps.Policy, deps.Storage, deps.Metrics, deps.Notifier, deps.Broker))
+		rt.common(r)
+	})
+
+	// прежние маршруты работают так же, как раньше, пока не наступит sunset
+	router.Group(func(r chi.Router) {
+		r.Use(mwDeprecation.New(cfg.LegacyRoutes.DeprecatedAt, cfg.LegacyRoutes.Sunset))
+		r.Route("/post", rt.legacyPosts)
+		rt.common(r)
+	})
+
+	// запросы GraphQL тоже отправляются POST, поэтому Idempotency-Key здесь не учитывается
+	router.With(mwAuth.NewOptional(cfg.TokenSecret, log), rt.Limiter.ByUser("graphql")).
+		Post("/graphql", graphql.New(cfg.GraphQL.MaxDepth, cfg.GraphQL.MaxComplexity, log, deps.Storage, deps.Policy, deps.Metrics, deps.Notifier, deps.Broker))
+
+	router.Get("/healthz", live.New())
+	router.Get("/readyz", ready.New(log, deps.Checks))
+	router.Get("/version", version.New())
+
+	router.Get("/swagger/*", httpSwagger.Handler(
+		httpSwagger.URL("http://localhost:8081/swagger/doc.json"),
+	))
+
+	return router
+}
+
+// posts - обработчики, связанные с постами, в /v1: ID поста всегда в пути.
+func (rt routes) posts(r chi.Router) {
+	r.With(mwAuth.NewOptional(rt.cfg.TokenSecret, rt.log)).
+		Get("/{id}", single.New(rt.log, rt.Storage, rt.Renderer))
+
+	r.Group(func(r chi.Router) {
+		r.Use(rt.auth)
+		r.With(rt.Limiter.ByUser("post.create")).Post("/", save.New(rt.log, rt.Storage, rt.Storage, rt.Policy, rt.Storage, rt.Metrics, rt.Notifier, rt.Broker))
+		r.With(rt.Limiter.ByUser("post.update")).Patch("/{id}", update.New(rt.log, rt.Storage, rt.Storage, rt.Policy, rt.Storage, rt.Notifier, rt.Broker))
+		r.With(rt.Limiter.ByUser("post.delete")).Delete("/{id}", remove.New(rt.log, rt.Storage, rt.Broker))
+		r.With(rt.Limiter.ByUser("post.like")).Put("/{id}/like", like.New(rt.log, rt.Storage, rt.Metrics, rt.Notifier, rt.Broker))
+		r.With(rt.Limiter.ByUser("post.like")).Delete("/{id}/like", unlike.New(rt.log, rt.Storage, rt.Broker))
+		rt.postActions(r)
+	})
+}
+
+// legacyPosts - прежние обработчики постов: ID поста для удаления, изменения
+// и лайка передается в теле запроса.
+func (rt routes) legacyPosts(r chi.Router) {
+	r.With(mwAuth.NewOptional(rt.cfg.TokenSecret, rt.log)).
+		Get("/{id}", single.New(rt.log, rt.Storage, rt.Renderer))
+
+	r.Group(func(r chi.Router) {
+		r.Use(rt.auth)
+		r.With(rt.Limiter.ByUser("post.create")).Post("/create", save.New(rt.log, rt.Storage, rt.Storage, rt.Policy, rt.Storage, rt.Metrics, rt.Notifier, rt.Broker))
+		r.With(rt.Limiter.ByUser("post.delete")).Delete("/delete", remove.New(rt.log, rt.Storage, rt.Broker))
+		r.With(rt.Limiter.ByUser("post.update")).Patch("/update", update.New(rt.log, rt.Storage, rt.Storage, rt.Policy, rt.Storage, rt.Notifier, rt.Broker))
+		r.With(rt.Limiter.ByUser("post.like")).Put("/like", like.New(rt.log, rt.Storage, rt.Metrics, rt.Notifier, rt.Broker))
+		r.With(rt.Limiter.ByUser("post.like")).Put("/unlike", unlike.New(rt.log, rt.Storage, rt.Broker))
+		rt.postActions(r)
+	})
+}
+
+// postActions - действия с постом, которые и раньше были в виде /post/{id}/...
+// Вызывается в группе с авторизацией.
+func (rt routes) postActions(r chi.Router) {
+	r.With(rt.Limiter.ByUser("post.report")).Post("/{id}/report", report.New(rt.log, rt.Storage))
+	r.With(rt.Limiter.ByUser("post.repost")).Post("/{id}/repost", repost.New(rt.log, rt.Storage, rt.Broker))
+	r.Delete("/{id}/repost", unrepost.New(rt.log, rt.Storage, rt.Broker))
+	r.With(rt.Limiter.ByUser("post.react")).Put("/{id}/reactions/{kind}", react.New(rt.cfg.Reactions, rt.log, rt.Storage, rt.Metrics, rt.Notifier, rt.Broker))
+	r.With(rt.Limiter.ByUser("post.react")).Delete("/{id}/reactions/{kind}", unreact.New(rt.log, rt.Storage, rt.Broker))
+	r.Put("/{id}/bookmark", bookmark.New(rt.log, rt.Storage))
+	r.Delete("/{id}/bookmark", unbookmark.New(rt.log, rt.Storage))
+}
+
+// common - маршруты, которые одинаковы в /v1 и без версии.
+func (rt routes) common(router chi.Router) {
+	cfg, log, storage := rt.cfg, rt.log, rt.Storage
+
+	// обработчики, связанные с загрузками
+	router.With(rt.auth, rt.Limiter.ByUser("media.upload")).
+		Post("/media", upload.New(cfg.Media.MaxSize, cfg.Media.ThumbnailSize, log, storage, rt.Blobs))
+	router.With(mwAuth.NewOptional(cfg.TokenSecret, log)).
+		Get("/media/{id}", download.New(log, storage, rt.Blobs))
+
+	// обработчики, связанные с пользователями
+	router.With(mwAuth.NewOptional(cfg.TokenSecret, log)).
+		Get("/user/{login}", posts.New(log, storage, rt.Renderer))
+	router.With(mwAuth.NewOptional(cfg.TokenSecret, log)).
+		Get("/user/{login}/profile", profile.New(log, storage))
+	router.Route("/user/me", func(r chi.Router) {
+		r.Use(rt.auth)
+		r.Get("/profile", profile.New(log, storage))
+		r.Patch("/profile", editprofile.New(log, storage))
+		r.Get("/bookmarks", bookmarks.New(log, storage, rt.Renderer))
+		r.Get("/bookmarks/collections", collections.New(log, storage))
+		r.Get("/mentions", mentions.New(log, storage, rt.Renderer))
+		r.Get("/notifications", list.New(log, storage))
+		r.Post("/notifications/read", read.New(log, storage))
+		r.Get("/notifications/preferences", prefs.New(log, storage))
+		r.Put("/notifications/preferences", setprefs.New(log, storage))
+	})
+	// обработчики, связанные с личными сообщениями
+	router.Route("/conversations", func(r chi.Router) {
+		r.Use(rt.auth)
+		r.With(rt.Limiter.ByUser("conversations.send")).Post("/", create.New(log, storage))
+		r.Get("/", inbox.New(log, storage))
+		r.Get("/{id}", details.New(log, storage))
+		r.With(rt.Limiter.ByUser("conversations.send")).Post("/{id}/messages", send.New(log, storage))
+		r.Get("/{id}/messages", history.New(log, storage))
+		r.Post("/{id}/read", markread.New(log, storage))
+	})
+	router.Route("/user/{login}/follow", func(r chi.Router) {
+		r.Use(rt.auth)
+		r.With(rt.Limiter.ByUser("user.follow")).Put("/", follow.New(log, storage, rt.Notifier))
+		r.Delete("/", unfollow.New(log, storage))
+	})
+	router.Route("/user/{login}/block", func(r chi.Router) {
+		r.Use(rt.auth)
+		r.Put("/", block.New(log, storage))
+		r.Delete("/", unblock.New(log, storage))
+	})
+	router.Route("/user/{login}/mute", func(r chi.Router) {
+		r.Use(rt.auth)
+		r.Put("/", mute.New(log, storage))
+		r.Delete("/", unmute.New(log, storage))
+	})
+	// обработчики модерации доступны только модераторам из конфига
+	router.Route("/mod", func(r chi.Router) {
+		r.Use(rt.auth)
+		r.Use(mwModerator.New(log, storage))
+		r.Get("/reports", reports.New(log, storage))
+		r.Post("/reports/{id}/resolve", resolve.New(log, storage, rt.Broker))
+		r.Get("/actions", actions.New(log, storage))
+	})
+	// у потока событий свой дедлайн на каждую запись вместо WriteTimeout сервера
+	router.With(rt.auth).
+		Get("/stream", subscribe.New(rt.Streams, cfg.Stream.Heartbeat, cfg.HTTPServer.Timeout, log, rt.Hub))
+	router.With(rt.auth).
+		Get("/ws", ws.New(rt.Streams, cfg.WebSocket.PongWait, cfg.HTTPServer.Timeout, log, rt.Broker, storage))
+	router.With(rt.Limiter.ByIP("auth.register")).Post("/auth/register", register.New(log, storage, rt.Metrics))
+	router.With(rt.Limiter.ByIP("auth.login")).Post("/auth/login", login.New(cfg.TokenSecret, log, storage, rt.Metrics))
+}
@@ -0,0 +1,376 @@
+package router_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/solumD/go-blog-api/internal/config"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/batch"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/post/save"
+	"github.com/solumD/go-blog-api/internal/http-server/handlers/user/login"
+	mwRatelimit "github.com/solumD/go-blog-api/internal/http-server/middleware/ratelimit"
+	"github.com/solumD/go-blog-api/internal/http-server/router"
+	"github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/lib/broker"
+	"github.com/solumD/go-blog-api/internal/lib/health"
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/solumD/go-blog-api/internal/lib/markdown"
+	"github.com/solumD/go-blog-api/internal/lib/metrics"
+	"github.com/solumD/go-blog-api/internal/lib/notifier"
+	"github.com/solumD/go-blog-api/internal/lib/policy"
+	"github.com/solumD/go-blog-api/internal/lib/ratelimit"
+	"github.com/solumD/go-blog-api/internal/lib/stream"
+	"github.com/solumD/go-blog-api/internal/storage/blob"
+	sqlite "github.com/solumD/go-blog-api/internal/storage/sqlite"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func setup(t *testing.T) *chi.Mux {
+	log := loggerdiscard.NewDiscardLogger()
+
+	storage, err := sqlite.New(filepath.Join(t.TempDir(), "blog.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = storage.Close() })
+
+	require.NoError(t, storage.Init(context.Background()))
+
+	cfg := &config.Config{
+		TokenSecret: "test secret",
+		Reactions:   []string{"like"},
+		LegacyRoutes: config.LegacyRoutes{
+			DeprecatedAt: time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC),
+			Sunset:       time.Date(2027, time.April, 19, 0, 0, 0, 0, time.UTC),
+		},
+		Idempotency: config.Idempotency{TTL: time.Hour, Lease: time.Minute, MaxBodySize: 1 << 20},
+		PostBatch:   config.PostBatch{MaxOperations: 10},
+		GraphQL:     config.GraphQL{MaxDepth: 10, MaxComplexity: 1000},
+	}
+
+	hub := stream.NewHub(log, 10, 10)
+
+	return router.New(cfg, log, router.Deps{
+		Storage:        storage,
+		Blobs:          blob.NewMemory(),
+		Renderer:       markdown.New(10),
+		Policy:         policy.New(),
+		Metrics:        metrics.New(),
+		TracerProvider: noop.NewTracerProvider(),
+		Limiter:        mwRatelimit.New(log, ratelimit.NewMemory(), nil),
+		Notifier:       notifier.New(log, storage, hub, 10),
+		Hub:            hub,
+		Broker:         broker.New(log, 10, 10),
+		Checks:         health.New(),
+		Idempotency:    storage,
+		Streams:        context.Background(),
+	})
+}
+
+func do(t *testing.T, handler http.Handler, method string, path string, body string, token string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	return recorder
+}
+
+func authorize(t *testing.T, handler http.Handler) string {
+	body := `{"login": "test_user", "password": "Test_password1"}`
+
+	require.Equal(t, http.StatusOK, do(t, handler, http.MethodPost, "/v1/auth/register", body, "").Code)
+
+	recorder := do(t, handler, http.MethodPost, "/v1/auth/login", body, "")
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var resp login.Response
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+
+	return resp.Token
+}
+
+func TestNewV1Posts(t *testing.T) {
+	handler := setup(t)
+	token := authorize(t, handler)
+
+	recorder := do(t, handler, http.MethodPost, "/v1/posts", `{"title": "title", "text": "text"}`, token)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Empty(t, recorder.Header().Get("Deprecation"))
+
+	var created save.Response
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &created))
+
+	post := fmt.Sprintf("/v1/posts/%d", created.ID)
+
+	testCases := []struct {
+		method     string
+		path       string
+		body       string
+		statusCode int
+	}{
+		{method: http.MethodGet, path: post, statusCode: http.StatusOK},
+		{method: http.MethodPatch, path: post, body: `{"title": "new title"}`, statusCode: http.StatusOK},
+		{method: http.MethodPut, path: post + "/like", statusCode: http.StatusOK},
+		{method: http.MethodPut, path: post + "/like", statusCode: http.StatusConflict},
+		{method: http.MethodDelete, path: post + "/like", statusCode: http.StatusOK},
+		{method: http.MethodDelete, path: post, statusCode: http.StatusOK},
+		{method: http.MethodGet, path: post, statusCode: http.StatusNotFound},
+		{method: http.MethodDelete, path: "/v1/posts/abc", statusCode: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		recorder := do(t, handler, tc.method, tc.path, tc.body, token)
+
+		require.Equal(t, tc.statusCode, recorder.Code, "%s %s", tc.method, tc.path)
+		require.Empty(t, recorder.Header().Get("Deprecation"))
+	}
+}
+
+func TestNewLegacyRoutes(t *testing.T) {
+	handler := setup(t)
+	token := authorize(t, handler)
+
+	// прежние маршруты работают, но отмечены как устаревшие
+	recorder := do(t, handler, http.MethodPost, "/post/create", `{"title": "title", "text": "text"}`, token)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "@1792368000", recorder.Header().Get("Deprecation"))
+	require.Equal(t, "Mon, 19 Apr 2027 00:00:00 GMT", recorder.Header().Get("Sunset"))
+
+	var created save.Response
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &created))
+
+	id := fmt.Sprintf(`{"id": %d}`, created.ID)
+
+	for _, req := range []struct{ method, path, body string }{
+		{http.MethodPut, "/post/like", id},
+		{http.MethodPut, "/post/unlike", id},
+		{http.MethodDelete, "/post/delete", id},
+		{http.MethodGet, "/user/test_user/profile", ""},
+	} {
+		recorder := do(t, handler, req.method, req.path, req.body, token)
+
+		require.Equal(t, http.StatusOK, recorder.Code, "%s %s", req.method, req.path)
+		require.NotEmpty(t, recorder.Header().Get("Deprecation"), "%s %s", req.method, req.path)
+		require.NotEmpty(t, recorder.Header().Get("Sunset"), "%s %s", req.method, req.path)
+	}
+
+	// служебные маршруты без версии не устаревают
+	recorder = do(t, handler, http.MethodGet, "/healthz", "", "")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Empty(t, recorder.Header().Get("Deprecation"))
+}
+
+func TestNewIdempotency(t *testing.T) {
+	handler := setup(t)
+	token := authorize(t, handler)
+
+	body := `{"title": "title", "text": "text"}`
+
+	first := do(t, handler, http.MethodPost, "/v1/posts", body, token, "Idempotency-Key", "key-1")
+	require.Equal(t, http.StatusOK, first.Code)
+
+	// повтор не создает второй пост, хотя проверка на дубликаты в политике выключена
+	retry := do(t, handler, http.MethodPost, "/v1/posts", body, token, "Idempotency-Key", "key-1")
+	require.Equal(t, http.StatusOK, retry.Code)
+	require.Equal(t, first.Body.String(), retry.Body.String())
+	require.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+
+	recorder := do(t, handler, http.MethodPost, "/v1/posts", `{"title": "other", "text": "text"}`, token, "Idempotency-Key", "key-1")
+	require.Equal(t, http.StatusConflict, recorder.Code)
+
+	// без ключа каждый запрос создает пост
+	recorder = do(t, handler, http.MethodPost, "/v1/posts", body, token)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.NotEqual(t, first.Body.String(), recorder.Body.String())
+}
+
+func TestNewPostsBatch(t *testing.T) {
+	handler := setup(t)
+	token := authorize(t, handler)
+
+	batchResponse := func(body string) batch.Response {
+		recorder := do(t, handler, http.MethodPost, "/v1/posts:batch", body, token)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+		var resp batch.Response
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+
+		return resp
+	}
+
+	created := batchResponse(`{"operations": [
+		{"op": "create", "title": "first", "text": "text"},
+		{"op": "create", "title": "second", "text": "text"}
+	]}`)
+	require.Equal(t, response.StatusOK, created.Status)
+	require.Len(t, created.Results, 2)
+
+	first, second := created.Results[0].ID, created.Results[1].ID
+	require.NotZero(t, first)
+	require.NotZero(t, second)
+
+	// в атомарном режиме ошибка одной операции откатывает остальные
+	recorder := do(t, handler, http.MethodPost, "/v1/posts:batch", fmt.Sprintf(`{"operations": [
+		{"op": "delete", "id": %d},
+		{"op": "delete", "id": %d}
+	]}`, first, first), token)
+	require.Equal(t, http.StatusConflict, recorder.Code, recorder.Body.String())
+
+	var aborted struct {
+		response.Problem
+		Results []batch.Result `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &aborted))
+	require.Equal(t, response.CodeBatchAborted, aborted.Code)
+	require.Equal(t, response.CodeBatchAborted, aborted.Results[0].Error.Code)
+	require.Equal(t, response.CodePostNotFound, aborted.Results[1].Error.Code)
+	require.Equal(t, http.StatusNotFound, aborted.Results[1].Error.Status)
+
+	require.Equal(t, http.StatusOK, do(t, handler, http.MethodGet, fmt.Sprintf("/v1/posts/%d", first), "", token).Code)
+
+	// в режиме best_effort выполняются операции без ошибок
+	partial := batchResponse(fmt.Sprintf(`{"mode": "best_effort", "operations": [
+		{"op": "delete", "id": %d},
+		{"op": "update", "id": %d, "title": "new title"},
+		{"op": "update", "id": %d},
+		{"op": "create", "title": "third", "text": "text", "format": "html"}
+	]}`, first, second, second))
+	require.Equal(t, response.StatusError, partial.Status)
+	require.Nil(t, partial.Results[0].Error)
+	require.Nil(t, partial.Results[1].Error)
+	require.Equal(t, response.CodeValidationFailed, partial.Results[2].Error.Code)
+	require.Equal(t, response.CodeValidationFailed, partial.Results[3].Error.Code)
+
+	require.Equal(t, http.StatusNotFound, do(t, handler, http.MethodGet, fmt.Sprintf("/v1/posts/%d", first), "", token).Code)
+
+	recorder = do(t, handler, http.MethodGet, fmt.Sprintf("/v1/posts/%d", second), "", token)
+	require.Contains(t, recorder.Body.String(), `"title":"new title"`)
+
+	recorder = do(t, handler, http.MethodPost, "/v1/posts:batch", `{"operations": []}`, token)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestNewGraphQL(t *testing.T) {
+	handler := setup(t)
+	token := authorize(t, handler)
+
+	type graphQLResponse struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Extensions struct {
+				Code string `json:"code"`
+			} `json:"extensions"`
+		} `json:"errors"`
+	}
+
+	query := func(token string, query string, variables map[string]any) graphQLResponse {
+		body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+		require.NoError(t, err)
+
+		recorder := do(t, handler, http.MethodPost, "/graphql", string(body), token)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+		var resp graphQLResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+
+		return resp
+	}
+
+	create := `mutation Create($title: String!) { createPost(input: {title: $title, text: "text"}) { id title author { login } } }`
+
+	resp := query(token, create, map[string]any{"title": "first"})
+	require.Empty(t, resp.Errors)
+	require.JSONEq(t, `{"createPost": {"id": "1", "title": "first", "author": {"login": "test_user"}}}`, string(resp.Data))
+
+	resp = query(token, create, map[string]any{"title": "second"})
+	require.Empty(t, resp.Errors)
+
+	resp = query(token, `mutation { likePost(id: 1) { likes reactionCounts { kind count } } }`, nil)
+	require.Empty(t, resp.Errors)
+	require.JSONEq(t, `{"likePost": {"likes": 1, "reactionCounts": [{"kind": "like", "count": 1}]}}`, string(resp.Data))
+
+	// анониму доступны запросы, но не мутации
+	resp = query("", `mutation { likePost(id: 2) { likes } }`, nil)
+	require.Len(t, resp.Errors, 1)
+	require.Equal(t, response.CodeUnauthorized, resp.Errors[0].Extensions.Code)
+
+	posts := `query Posts($after: String) { user(login: "test_user") { posts(first: 1, after: $after) {
+		edges { node { title reactions { edges { node { user { login } } } } } }
+		pageInfo { hasNextPage endCursor }
+	} } }`
+
+	var page struct {
+		User struct {
+			Posts struct {
+				Edges []struct {
+					Node struct {
+						Title     string
+						Reactions struct {
+							Edges []struct {
+								Node struct {
+									User struct{ Login string }
+								}
+							}
+						}
+					}
+				}
+				PageInfo struct {
+					HasNextPage bool
+					EndCursor   string
+				}
+			}
+		}
+	}
+
+	resp = query("", posts, nil)
+	require.Empty(t, resp.Errors)
+	require.NoError(t, json.Unmarshal(resp.Data, &page))
+	require.Len(t, page.User.Posts.Edges, 1)
+	require.Equal(t, "second", page.User.Posts.Edges[0].Node.Title)
+	require.True(t, page.User.Posts.PageInfo.HasNextPage)
+
+	resp = query("", posts, map[string]any{"after": page.User.Posts.PageInfo.EndCursor})
+	require.Empty(t, resp.Errors)
+	require.NoError(t, json.Unmarshal(resp.Data, &page))
+	require.Len(t, page.User.Posts.Edges, 1)
+	require.Equal(t, "first", page.User.Posts.Edges[0].Node.Title)
+	require.Equal(t, "test_user", page.User.Posts.Edges[0].Node.Reactions.Edges[0].Node.User.Login)
+	require.False(t, page.User.Posts.PageInfo.HasNextPage)
+
+	resp = query(token, `mutation { deletePost(id: 1) }`, nil)
+	require.Empty(t, resp.Errors)
+
+	resp = query(token, `{ post(id: 1) { id } }`, nil)
+	require.JSONEq(t, `{"post": null}`, string(resp.Data))
+
+	// токен проверяется так же, как на других маршрутах
+	recorder := do(t, handler, http.MethodPost, "/graphql", `{"query": "{ me { login } }"}`, "invalid token")
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestNewNotFound(t *testing.T) {
+	handler := setup(t)
+
+	for _, path := range []string{"/v1/unknown", "/post/create/unknown", "/v2/posts"} {
+		recorder := do(t, handler, http.MethodGet, path, "", "")
+		require.Equal(t, http.StatusNotFound, recorder.Code, path)
+
+		var problem response.Problem
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+		require.Equal(t, response.CodeRouteNotFound, problem.Code)
+	}
+}
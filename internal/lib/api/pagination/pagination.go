@@ -0,0 +1,54 @@
+package pagination
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Page - параметры страницы списка из query-параметров limit и offset.
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// Parse читает limit и offset из запроса.
+// Если параметры не указаны, возвращает первую страницу размером DefaultLimit.
+func Parse(r *http.Request) (Page, error) {
+	page := Page{Limit: DefaultLimit}
+
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			return Page{}, fmt.Errorf("limit must be a number from 1 to %d", MaxLimit)
+		}
+		page.Limit = limit
+	}
+
+	if v := r.URL.Query().Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return Page{}, fmt.Errorf("offset must be a non-negative number")
+		}
+		page.Offset = offset
+	}
+
+	return page, nil
+}
+
+// Next возвращает offset следующей страницы, если она есть, иначе - nil.
+// Хранилище должно запросить на один элемент больше limit, чтобы понять, есть ли следующая страница.
+func (p Page) Next(got int) *int {
+	if got <= p.Limit {
+		return nil
+	}
+
+	next := p.Offset + p.Limit
+
+	return &next
+}
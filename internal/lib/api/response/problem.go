@@ -0,0 +1,259 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/render"
+	"github.com/solumD/go-blog-api/internal/storage"
+)
+
+// ContentTypeProblem - тип ответов с ошибкой в формате RFC 7807.
+const ContentTypeProblem = "application/problem+json"
+
+// Виды ошибок, которые возникают в обработчиках, а не в хранилище.
+// Виды ошибок хранилища: storage.ErrNotFound, storage.ErrConflict, storage.ErrForbidden.
+var (
+	ErrInvalid          = errors.New("invalid request")
+	ErrUnauthorized     = errors.New("unauthorized")
+	ErrMethodNotAllowed = errors.New("method not allowed")
+	ErrTooLarge         = errors.New("request entity too large")
+	ErrUnsupportedMedia = errors.New("unsupported media type")
+	ErrTooManyRequests  = errors.New("too many requests")
+	ErrUnavailable      = errors.New("service unavailable")
+	// операция пакета не выполнена из-за ошибки в другой операции
+	ErrFailedDependency = errors.New("failed dependency")
+	ErrInternal         = errors.New("internal error")
+)
+
+// Коды ошибок. Клиенты различают ошибки по коду, а не по тексту,
+// поэтому коды не меняются, даже если меняется текст ошибки.
+const (
+	CodeInternal             = "internal_error"
+	CodeInvalidJSON          = "invalid_json"
+	CodeValidationFailed     = "validation_failed"
+	CodeInvalidID            = "invalid_id"
+	CodeInvalidFile          = "invalid_file"
+	CodeFileTooLarge         = "file_too_large"
+	CodeImageTooLarge        = "image_too_large"
+	CodeUnsupportedImage     = "unsupported_image_type"
+	CodeContentRejected      = "content_rejected"
+	CodeSelfAction           = "self_action"
+	CodeUnauthorized         = "unauthorized"
+	CodeInvalidPassword      = "invalid_password"
+	CodeAccountSuspended     = "account_suspended"
+	CodeModeratorRequired    = "moderator_required"
+	CodeNotPostAuthor        = "not_post_author"
+	CodePostNotPublic        = "post_not_public"
+	CodeBlocked              = "blocked"
+	CodePostNotFound         = "post_not_found"
+	CodeUserNotFound         = "user_not_found"
+	CodeConversationNotFound = "conversation_not_found"
+	CodeMediaNotFound        = "media_not_found"
+	CodeReportsNotFound      = "reports_not_found"
+	CodeMediaNotAvailable    = "media_not_available"
+	CodeUserAlreadyExists    = "user_already_exists"
+	CodeAlreadyFollowing     = "already_following"
+	CodeNotFollowing         = "not_following"
+	CodeAlreadyLiked         = "already_liked"
+	CodeNotLiked             = "not_liked"
+	CodeAlreadyReacted       = "already_reacted"
+	CodeNotReacted           = "not_reacted"
+	CodeAlreadyReposted      = "already_reposted"
+	CodeNotReposted          = "not_reposted"
+	CodeAlreadyReported      = "already_reported"
+	CodeNotBookmarked        = "not_bookmarked"
+	CodeTooManyRequests      = "too_many_requests"
+	CodeRouteNotFound        = "route_not_found"
+	CodeMethodNotAllowed     = "method_not_allowed"
+	CodeInvalidBody          = "invalid_body"
+	CodeBodyTooLarge         = "body_too_large"
+	CodeInvalidIdempotency   = "invalid_idempotency_key"
+	CodeIdempotencyReused    = "idempotency_key_reused"
+	CodeIdempotencyPending   = "idempotency_key_in_progress"
+	CodeBatchAborted         = "batch_aborted"
+	CodeQueryTooComplex      = "query_too_complex"
+)
+
+// Problem - ответ с ошибкой в формате RFC 7807.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// стабильный код ошибки
+	Code string `json:"code"`
+}
+
+// Failure - ошибка проверки запроса с видом, кодом и текстом ответа. Ее возвращают
+// проверки, общие для REST обработчиков, пакетных запросов и GraphQL: каждый
+// из них отвечает на нее в своем формате.
+type Failure struct {
+	Kind error
+	Code string
+	Msg  string
+}
+
+func (f *Failure) Error() string {
+	return f.Msg
+}
+
+// StatusOf возвращает HTTP-статус для ошибки вида kind. Это единственное место,
+// где вид ошибки превращается в статус: ошибки неизвестного вида - это 500.
+func StatusOf(kind error) int {
+	switch {
+	case errors.Is(kind, ErrInvalid):
+		return http.StatusBadRequest
+	case errors.Is(kind, ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(kind, storage.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(kind, storage.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(kind, ErrMethodNotAllowed):
+		return http.StatusMethodNotAllowed
+	case errors.Is(kind, storage.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(kind, ErrTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(kind, ErrUnsupportedMedia):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(kind, ErrTooManyRequests):
+		return http.StatusTooManyRequests
+	case errors.Is(kind, ErrUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(kind, ErrFailedDependency):
+		return http.StatusFailedDependency
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// NewProblem возвращает описание ошибки вида kind с кодом code и текстом msg,
+// например для ошибки одной из операций пакетного запроса.
+func NewProblem(r *http.Request, kind error, code string, msg string) Problem {
+	status := StatusOf(kind)
+
+	return Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   msg,
+		Instance: r.URL.Path,
+		Code:     code,
+	}
+}
+
+// Fail отвечает на запрос ошибкой вида kind с кодом code и текстом msg.
+// Ответ - application/problem+json, если только клиент не просит прежний формат
+// {"status":"Error","error":"<msg>"}, указав в Accept application/json.
+func Fail(w http.ResponseWriter, r *http.Request, kind error, code string, msg string) {
+	FailWith(w, r, kind, code, msg, "", nil)
+}
+
+// FailWith отвечает, как Fail, но добавляет к ошибке поле name со значением value
+// (RFC 7807 разрешает свои поля), например результаты операций пакетного запроса.
+// Пустое name - ответ как у Fail.
+func FailWith(w http.ResponseWriter, r *http.Request, kind error, code string, msg string, name string, value any) {
+	status := StatusOf(kind)
+
+	if wantsLegacy(r) && name == "" {
+		render.Status(r, status)
+		render.JSON(w, r, Error(msg))
+
+		return
+	}
+
+	contentType := ContentTypeProblem
+
+	var body any = NewProblem(r, kind, code, msg)
+	if wantsLegacy(r) {
+		contentType = "application/json"
+		body = Error(msg)
+	}
+
+	buf, err := encode(body, name, value)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	w.Write(buf)
+}
+
+// encode кодирует объект v в JSON и, если name не пустое, дописывает в него поле name.
+func encode(v any, name string, value any) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(true)
+
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		return buf.Bytes(), nil
+	}
+
+	// убираем закрывающую скобку объекта и перевод строки после нее
+	obj := bytes.TrimSuffix(bytes.TrimSpace(buf.Bytes()), []byte("}"))
+
+	field := &bytes.Buffer{}
+	enc = json.NewEncoder(field)
+	enc.SetEscapeHTML(true)
+
+	if err := enc.Encode(map[string]any{name: value}); err != nil {
+		return nil, err
+	}
+
+	// поле без открывающей скобки своего объекта
+	out := append(obj, ',')
+	out = append(out, bytes.TrimPrefix(field.Bytes(), []byte("{"))...)
+
+	return out, nil
+}
+
+// NotFound отвечает на запрос к несуществующему маршруту.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	Fail(w, r, storage.ErrNotFound, CodeRouteNotFound, "route not found")
+}
+
+// MethodNotAllowed отвечает на запрос к маршруту с неподдерживаемым методом.
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	Fail(w, r, ErrMethodNotAllowed, CodeMethodNotAllowed, "method not allowed")
+}
+
+// wantsLegacy проверяет, предпочитает ли клиент application/json формату application/problem+json.
+// Без Accept или с */* ошибки отдаются в новом формате.
+func wantsLegacy(r *http.Request) bool {
+	legacy, problem := 0.0, 0.0
+
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+
+		switch strings.ToLower(strings.TrimSpace(mediaType)) {
+		case "application/json":
+			legacy = max(legacy, q)
+		case ContentTypeProblem:
+			problem = max(problem, q)
+		}
+	}
+
+	return legacy > problem
+}
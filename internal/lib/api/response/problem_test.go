@@ -0,0 +1,117 @@
+package response_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	resp "github.com/solumD/go-blog-api/internal/lib/api/response"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusOf(t *testing.T) {
+	testCases := []struct {
+		kind   error
+		status int
+	}{
+		{kind: resp.ErrInvalid, status: http.StatusBadRequest},
+		{kind: resp.ErrUnauthorized, status: http.StatusUnauthorized},
+		{kind: storage.ErrForbidden, status: http.StatusForbidden},
+		{kind: storage.ErrNotFound, status: http.StatusNotFound},
+		{kind: storage.ErrPostNotFound, status: http.StatusNotFound},
+		{kind: fmt.Errorf("storage.sqlite.ReportPost: %w", storage.ErrAlreadyReported), status: http.StatusConflict},
+		{kind: resp.ErrTooManyRequests, status: http.StatusTooManyRequests},
+		{kind: resp.ErrFailedDependency, status: http.StatusFailedDependency},
+		{kind: resp.ErrInternal, status: http.StatusInternalServerError},
+		{kind: fmt.Errorf("unexpected error"), status: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.status, resp.StatusOf(tc.kind), tc.kind.Error())
+	}
+}
+
+func TestFail(t *testing.T) {
+	problem := `{"type":"about:blank","title":"Not Found","status":404,"detail":"post doesn't exist","instance":"/post/1","code":"post_not_found"}`
+	legacy := `{"status":"Error","error":"post doesn't exist"}`
+
+	testCases := []struct {
+		name        string
+		accept      string
+		contentType string
+		body        string
+	}{
+		{name: "No Accept", contentType: "application/problem+json", body: problem},
+		{name: "Any", accept: "*/*", contentType: "application/problem+json", body: problem},
+		{name: "Problem", accept: "application/problem+json", contentType: "application/problem+json", body: problem},
+		{name: "Legacy", accept: "application/json", contentType: "application/json", body: legacy},
+		{name: "Legacy with wildcard", accept: "application/json, text/plain, */*", contentType: "application/json", body: legacy},
+		{name: "Both", accept: "application/json, application/problem+json", contentType: "application/problem+json", body: problem},
+		{name: "Legacy preferred", accept: "application/problem+json;q=0.5, application/json", contentType: "application/json", body: legacy},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/post/1", nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+
+			recorder := httptest.NewRecorder()
+			resp.Fail(recorder, req, storage.ErrPostNotFound, resp.CodePostNotFound, "post doesn't exist")
+
+			require.Equal(t, http.StatusNotFound, recorder.Code)
+			require.Equal(t, tc.contentType, recorder.Header().Get("Content-Type"))
+			require.JSONEq(t, tc.body, recorder.Body.String())
+		})
+	}
+}
+
+func TestFailWith(t *testing.T) {
+	results := []map[string]string{{"op": "delete"}}
+
+	testCases := []struct {
+		name        string
+		accept      string
+		contentType string
+		body        string
+	}{
+		{
+			name:        "Problem",
+			contentType: "application/problem+json",
+			body:        `{"type":"about:blank","title":"Conflict","status":409,"detail":"batch aborted","instance":"/v1/posts:batch","code":"batch_aborted","results":[{"op":"delete"}]}`,
+		},
+		{
+			name:        "Legacy",
+			accept:      "application/json",
+			contentType: "application/json",
+			body:        `{"status":"Error","error":"batch aborted","results":[{"op":"delete"}]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/posts:batch", nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+
+			recorder := httptest.NewRecorder()
+			resp.FailWith(recorder, req, storage.ErrConflict, resp.CodeBatchAborted, "batch aborted", "results", results)
+
+			require.Equal(t, http.StatusConflict, recorder.Code)
+			require.Equal(t, tc.contentType, recorder.Header().Get("Content-Type"))
+			require.JSONEq(t, tc.body, recorder.Body.String())
+		})
+	}
+}
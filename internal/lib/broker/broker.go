@@ -0,0 +1,222 @@
+package broker
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Типы событий
+const (
+	// пользователь опубликовал публичный пост
+	EventPostCreated = "post.created"
+	// пост изменен
+	EventPostUpdated = "post.updated"
+	// пост удален
+	EventPostDeleted = "post.deleted"
+	// пост лайкнули
+	EventLike = "like"
+	// с поста сняли лайк
+	EventUnlike = "unlike"
+	// на пост поставили реакцию, кроме лайка
+	EventReaction = "reaction"
+	// с поста сняли реакцию, кроме лайка
+	EventUnreaction = "unreaction"
+)
+
+// Виды топиков
+const (
+	// посты пользователя, например, user:alice123
+	TopicUser = "user"
+	// лайки и реакции на пост, его изменение и удаление, например, post:7
+	TopicPost = "post"
+)
+
+var (
+	ErrInvalidTopic           = errors.New("topic must be in format user:<login> or post:<id>")
+	ErrTooManySubscriptions   = errors.New("too many subscriptions")
+	ErrSubscriberDisconnected = errors.New("subscriber is disconnected")
+)
+
+// Message - событие в топике.
+type Message struct {
+	Topic string `json:"topic"`
+	Type  string `json:"type"`
+	Data  any    `json:"data"`
+}
+
+// PostData - данные событий о создании, изменении и удалении поста.
+type PostData struct {
+	PostID int64  `json:"post_id"`
+	Author string `json:"author,omitempty"`
+}
+
+// ReactionData - данные событий о лайках и реакциях.
+type ReactionData struct {
+	PostID int64  `json:"post_id"`
+	Login  string `json:"login"`
+	Kind   string `json:"kind,omitempty"`
+}
+
+// UserTopic возвращает топик постов пользователя login.
+func UserTopic(login string) string {
+	return TopicUser + ":" + login
+}
+
+// PostTopic возвращает топик поста id.
+func PostTopic(id int64) string {
+	return TopicPost + ":" + strconv.FormatInt(id, 10)
+}
+
+// ParseTopic возвращает вид топика и логин пользователя или id поста.
+func ParseTopic(topic string) (kind string, value string, err error) {
+	kind, value, ok := strings.Cut(topic, ":")
+	if !ok || value == "" {
+		return "", "", ErrInvalidTopic
+	}
+
+	switch kind {
+	case TopicUser:
+	case TopicPost:
+		if id, err := strconv.ParseInt(value, 10, 64); err != nil || id <= 0 {
+			return "", "", ErrInvalidTopic
+		}
+	default:
+		return "", "", ErrInvalidTopic
+	}
+
+	return kind, value, nil
+}
+
+// Subscriber - подписчик, обычно одно WebSocket-соединение.
+type Subscriber struct {
+	messages chan Message
+	topics   map[string]struct{}
+	closed   bool
+}
+
+// Messages возвращает канал событий подписчика.
+// Канал закрывается после Close или если подписчик не успевает читать события.
+func (s *Subscriber) Messages() <-chan Message {
+	return s.messages
+}
+
+// Broker рассылает события подписчикам топиков внутри процесса.
+type Broker struct {
+	log              *slog.Logger
+	mu               sync.Mutex
+	topics           map[string]map[*Subscriber]struct{}
+	bufferSize       int
+	maxSubscriptions int
+}
+
+// New создает Broker, который держит в очереди каждого подписчика не больше bufferSize событий
+// и разрешает подписчику не больше maxSubscriptions топиков.
+func New(log *slog.Logger, bufferSize int, maxSubscriptions int) *Broker {
+	return &Broker{
+		log:              log.With(slog.String("component", "broker")),
+		topics:           make(map[string]map[*Subscriber]struct{}),
+		bufferSize:       bufferSize,
+		maxSubscriptions: maxSubscriptions,
+	}
+}
+
+// NewSubscriber создает подписчика без топиков.
+func (b *Broker) NewSubscriber() *Subscriber {
+	return &Subscriber{
+		messages: make(chan Message, b.bufferSize),
+		topics:   make(map[string]struct{}),
+	}
+}
+
+// Subscribe подписывает sub на topic. Повторная подписка ничего не делает.
+func (b *Broker) Subscribe(sub *Subscriber, topic string) error {
+	const fn = "lib.broker.Subscribe"
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if sub.closed {
+		return fmt.Errorf("%s: %w", fn, ErrSubscriberDisconnected)
+	}
+
+	if _, ok := sub.topics[topic]; ok {
+		return nil
+	}
+
+	if len(sub.topics) >= b.maxSubscriptions {
+		return fmt.Errorf("%s: %w", fn, ErrTooManySubscriptions)
+	}
+
+	if b.topics[topic] == nil {
+		b.topics[topic] = make(map[*Subscriber]struct{})
+	}
+	b.topics[topic][sub] = struct{}{}
+	sub.topics[topic] = struct{}{}
+
+	return nil
+}
+
+// Unsubscribe отписывает sub от topic.
+func (b *Broker) Unsubscribe(sub *Subscriber, topic string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.unsubscribe(sub, topic)
+}
+
+// Close отписывает sub от всех топиков и закрывает его канал. Повторный вызов ничего не делает.
+func (b *Broker) Close(sub *Subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.close(sub)
+}
+
+// Publish отправляет событие подписчикам топика и не блокируется.
+// Подписчик, очередь которого заполнена, отключается.
+func (b *Broker) Publish(topic string, eventType string, data any) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	msg := Message{
+		Topic: topic,
+		Type:  eventType,
+		Data:  data,
+	}
+
+	for sub := range b.topics[topic] {
+		select {
+		case sub.messages <- msg:
+		default:
+			b.log.Warn("subscriber is too slow, disconnected", slog.String("topic", topic))
+			b.close(sub)
+		}
+	}
+}
+
+func (b *Broker) unsubscribe(sub *Subscriber, topic string) {
+	delete(sub.topics, topic)
+
+	subs := b.topics[topic]
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(b.topics, topic)
+	}
+}
+
+func (b *Broker) close(sub *Subscriber) {
+	if sub.closed {
+		return
+	}
+
+	for topic := range sub.topics {
+		b.unsubscribe(sub, topic)
+	}
+
+	sub.closed = true
+	close(sub.messages)
+}
@@ -0,0 +1,89 @@
+package broker_test
+
+import (
+	"testing"
+
+	"github.com/solumD/go-blog-api/internal/lib/broker"
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTopic(t *testing.T) {
+	testCases := []struct {
+		topic string
+		kind  string
+		value string
+		err   error
+	}{
+		{topic: "user:alice_user", kind: broker.TopicUser, value: "alice_user"},
+		{topic: "post:7", kind: broker.TopicPost, value: "7"},
+		{topic: "post:0", err: broker.ErrInvalidTopic},
+		{topic: "post:abc", err: broker.ErrInvalidTopic},
+		{topic: "user:", err: broker.ErrInvalidTopic},
+		{topic: "comment:1", err: broker.ErrInvalidTopic},
+		{topic: "alice_user", err: broker.ErrInvalidTopic},
+	}
+
+	for _, tc := range testCases {
+		kind, value, err := broker.ParseTopic(tc.topic)
+		require.ErrorIs(t, err, tc.err, tc.topic)
+		require.Equal(t, tc.kind, kind, tc.topic)
+		require.Equal(t, tc.value, value, tc.topic)
+	}
+}
+
+func TestBroker(t *testing.T) {
+	b := broker.New(loggerdiscard.NewDiscardLogger(), 10, 2)
+
+	alice := b.NewSubscriber()
+	bob := b.NewSubscriber()
+
+	require.NoError(t, b.Subscribe(alice, "post:1"))
+	require.NoError(t, b.Subscribe(alice, "post:1"))
+	require.NoError(t, b.Subscribe(alice, "user:bob_user"))
+	require.ErrorIs(t, b.Subscribe(alice, "post:2"), broker.ErrTooManySubscriptions)
+	require.NoError(t, b.Subscribe(bob, "post:1"))
+
+	b.Publish("post:1", broker.EventLike, broker.ReactionData{PostID: 1, Login: "carol_user"})
+	b.Publish("post:3", broker.EventLike, broker.ReactionData{PostID: 3, Login: "carol_user"})
+
+	want := broker.Message{
+		Topic: "post:1",
+		Type:  broker.EventLike,
+		Data:  broker.ReactionData{PostID: 1, Login: "carol_user"},
+	}
+	require.Equal(t, want, <-alice.Messages())
+	require.Equal(t, want, <-bob.Messages())
+	require.Len(t, alice.Messages(), 0)
+
+	// после отписки место под топик освобождается
+	b.Unsubscribe(alice, "post:1")
+	require.NoError(t, b.Subscribe(alice, "post:2"))
+
+	b.Publish("post:1", broker.EventUnlike, nil)
+	require.Len(t, alice.Messages(), 0)
+	require.Len(t, bob.Messages(), 1)
+
+	b.Close(alice)
+	b.Close(alice)
+
+	_, ok := <-alice.Messages()
+	require.False(t, ok)
+	require.ErrorIs(t, b.Subscribe(alice, "post:1"), broker.ErrSubscriberDisconnected)
+}
+
+func TestBrokerDropsSlowSubscriber(t *testing.T) {
+	b := broker.New(loggerdiscard.NewDiscardLogger(), 1, 10)
+
+	sub := b.NewSubscriber()
+	require.NoError(t, b.Subscribe(sub, "post:1"))
+
+	b.Publish("post:1", broker.EventLike, nil)
+	b.Publish("post:1", broker.EventLike, nil)
+
+	_, ok := <-sub.Messages()
+	require.True(t, ok)
+
+	_, ok = <-sub.Messages()
+	require.False(t, ok)
+}
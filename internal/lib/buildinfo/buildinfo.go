@@ -0,0 +1,56 @@
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Задаются при сборке:
+//
+//	go build -ldflags "-X github.com/solumD/go-blog-api/internal/lib/buildinfo.Commit=$(git rev-parse HEAD) \
+//		-X github.com/solumD/go-blog-api/internal/lib/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Если они не заданы, используются коммит и время коммита, которые go build добавляет сам.
+var (
+	Commit    string
+	BuildTime string
+)
+
+const unknown = "unknown"
+
+// Info - информация о сборке.
+type Info struct {
+	Commit    string
+	BuildTime string
+	GoVersion string
+}
+
+// Get возвращает информацию о текущей сборке.
+func Get() Info {
+	info := Info{
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch {
+			case s.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = s.Value
+			case s.Key == "vcs.time" && info.BuildTime == "":
+				info.BuildTime = s.Value
+			}
+		}
+	}
+
+	if info.Commit == "" {
+		info.Commit = unknown
+	}
+
+	if info.BuildTime == "" {
+		info.BuildTime = unknown
+	}
+
+	return info
+}
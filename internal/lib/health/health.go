@@ -0,0 +1,135 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Checker проверяет, может ли подсистема обслуживать запросы.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc позволяет использовать функцию как Checker.
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Result - результат проверки готовности.
+type Result struct {
+	Ready bool
+	// сервер останавливается и больше не принимает запросы
+	Stopping bool
+	// результаты проверок по названиям: ok или текст ошибки
+	Checks map[string]string
+}
+
+type check struct {
+	name    string
+	checker Checker
+}
+
+// Health хранит проверки готовности сервиса. Подсистемы добавляют свои проверки через Register.
+type Health struct {
+	mu       sync.RWMutex
+	checks   []check
+	stopping atomic.Bool
+}
+
+// New создает набор проверок без проверок.
+func New() *Health {
+	return &Health{}
+}
+
+// Register добавляет проверку name.
+func (h *Health) Register(name string, checker Checker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.checks = append(h.checks, check{name: name, checker: checker})
+}
+
+// Stop помечает сервис как останавливающийся: после этого он не готов при любых проверках.
+func (h *Health) Stop() {
+	h.stopping.Store(true)
+}
+
+// Check выполняет все проверки по очереди.
+func (h *Health) Check(ctx context.Context) Result {
+	h.mu.RLock()
+	checks := h.checks
+	h.mu.RUnlock()
+
+	res := Result{
+		Ready:    !h.stopping.Load(),
+		Stopping: h.stopping.Load(),
+		Checks:   make(map[string]string, len(checks)),
+	}
+
+	for _, c := range checks {
+		if err := c.checker.Check(ctx); err != nil {
+			res.Ready = false
+			res.Checks[c.name] = err.Error()
+			continue
+		}
+
+		res.Checks[c.name] = "ok"
+	}
+
+	return res
+}
+
+// Workers запускает фоновые задачи и проверяет, что ни одна из них не завершилась раньше времени.
+type Workers struct {
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	names   []string
+	running map[string]bool
+}
+
+// NewWorkers создает пустой набор фоновых задач.
+func NewWorkers() *Workers {
+	return &Workers{running: make(map[string]bool)}
+}
+
+// Go запускает задачу name в отдельной горутине. Задача должна работать до отмены ctx.
+func (w *Workers) Go(ctx context.Context, name string, run func(ctx context.Context)) {
+	w.mu.Lock()
+	w.names = append(w.names, name)
+	w.running[name] = true
+	w.mu.Unlock()
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		run(ctx)
+
+		w.mu.Lock()
+		w.running[name] = false
+		w.mu.Unlock()
+	}()
+}
+
+// Wait ждет завершения всех задач.
+func (w *Workers) Wait() {
+	w.wg.Wait()
+}
+
+// Check возвращает ошибку, если какая-то из задач не работает.
+func (w *Workers) Check(_ context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, name := range w.names {
+		if !w.running[name] {
+			return fmt.Errorf("worker %s is not running", name)
+		}
+	}
+
+	return nil
+}
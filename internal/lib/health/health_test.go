@@ -0,0 +1,79 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/solumD/go-blog-api/internal/lib/health"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealth(t *testing.T) {
+	h := health.New()
+
+	res := h.Check(context.Background())
+	require.True(t, res.Ready)
+	require.Empty(t, res.Checks)
+
+	h.Register("storage", health.CheckerFunc(func(ctx context.Context) error {
+		return nil
+	}))
+
+	res = h.Check(context.Background())
+	require.True(t, res.Ready)
+	require.Equal(t, map[string]string{"storage": "ok"}, res.Checks)
+
+	h.Register("cache", health.CheckerFunc(func(ctx context.Context) error {
+		return errors.New("cache is unavailable")
+	}))
+
+	res = h.Check(context.Background())
+	require.False(t, res.Ready)
+	require.False(t, res.Stopping)
+	require.Equal(t, map[string]string{"storage": "ok", "cache": "cache is unavailable"}, res.Checks)
+}
+
+func TestHealthStop(t *testing.T) {
+	h := health.New()
+	h.Register("storage", health.CheckerFunc(func(ctx context.Context) error {
+		return nil
+	}))
+
+	h.Stop()
+
+	res := h.Check(context.Background())
+	require.False(t, res.Ready)
+	require.True(t, res.Stopping)
+	require.Equal(t, map[string]string{"storage": "ok"}, res.Checks)
+}
+
+func TestWorkers(t *testing.T) {
+	w := health.NewWorkers()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w.Go(ctx, "runner", func(ctx context.Context) {
+		<-ctx.Done()
+	})
+
+	done := make(chan struct{})
+	w.Go(ctx, "quitter", func(ctx context.Context) {
+		<-done
+	})
+
+	require.NoError(t, w.Check(context.Background()))
+
+	// задача завершилась раньше отмены контекста
+	close(done)
+
+	require.Eventually(t, func() bool {
+		return w.Check(context.Background()) != nil
+	}, time.Second, 10*time.Millisecond)
+	require.EqualError(t, w.Check(context.Background()), "worker quitter is not running")
+
+	cancel()
+	w.Wait()
+}
@@ -0,0 +1,66 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+)
+
+type ExpiredRemover interface {
+	RemoveExpiredIdempotencyKeys(ctx context.Context, now string) (int64, error)
+}
+
+// Cleaner периодически удаляет истекшие ключи идемпотентности. Истекший ключ
+// и так считается свободным, удаление нужно только чтобы хранилище не росло.
+type Cleaner struct {
+	log      *slog.Logger
+	storage  ExpiredRemover
+	interval time.Duration
+}
+
+// NewCleaner создает очистку, которая удаляет истекшие ключи каждые interval.
+func NewCleaner(log *slog.Logger, storage ExpiredRemover, interval time.Duration) *Cleaner {
+	return &Cleaner{
+		log:      log.With(slog.String("component", "idempotency/cleaner")),
+		storage:  storage,
+		interval: interval,
+	}
+}
+
+// Run запускает очистку и блокируется до отмены контекста.
+func (c *Cleaner) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := c.Clean(ctx)
+			if err != nil {
+				c.log.Error("failed to remove expired idempotency keys", sl.Err(err))
+				continue
+			}
+
+			if n > 0 {
+				c.log.Info("expired idempotency keys removed", slog.Int64("count", n))
+			}
+		}
+	}
+}
+
+// Clean удаляет истекшие ключи и возвращает их количество.
+func (c *Cleaner) Clean(ctx context.Context) (int64, error) {
+	const fn = "lib.idempotency.Cleaner.Clean"
+
+	n, err := c.storage.RemoveExpiredIdempotencyKeys(ctx, time.Now().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", fn, err)
+	}
+
+	return n, nil
+}
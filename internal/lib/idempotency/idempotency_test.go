@@ -0,0 +1,64 @@
+package idempotency_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/solumD/go-blog-api/internal/lib/idempotency"
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	m := idempotency.NewMemory()
+
+	_, ok, err := m.ReserveIdempotencyKey(ctx, "test_user", "key-1", "a", "2024-01-01 12:00:00", "2024-01-01 11:00:00")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// пока первый запрос выполняется, ответа еще нет
+	record, ok, err := m.ReserveIdempotencyKey(ctx, "test_user", "key-1", "b", "2024-01-01 12:00:00", "2024-01-01 11:00:00")
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, "a", record.Fingerprint)
+	require.Zero(t, record.Status)
+
+	// ответ хранится дольше, чем ключ был занят
+	require.NoError(t, m.SaveIdempotentResponse(ctx, "test_user", "key-1", 201, "application/json", []byte(`{}`), "2024-01-01 12:30:00"))
+
+	record, ok, err = m.ReserveIdempotencyKey(ctx, "test_user", "key-1", "a", "2024-01-01 12:00:00", "2024-01-01 12:10:00")
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, 201, record.Status)
+	require.Equal(t, []byte(`{}`), record.Body)
+
+	// истекший ключ снова свободен
+	_, ok, err = m.ReserveIdempotencyKey(ctx, "test_user", "key-1", "b", "2024-01-01 13:00:00", "2024-01-01 12:30:00")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	require.NoError(t, m.RemoveIdempotencyKey(ctx, "test_user", "key-1"))
+
+	_, ok, err = m.ReserveIdempotencyKey(ctx, "test_user", "key-1", "c", "2024-01-01 13:00:00", "2024-01-01 12:00:00")
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestCleaner(t *testing.T) {
+	ctx := context.Background()
+	m := idempotency.NewMemory()
+
+	_, _, err := m.ReserveIdempotencyKey(ctx, "test_user", "expired", "a", "2000-01-01 00:00:00", "1999-12-31 00:00:00")
+	require.NoError(t, err)
+	_, _, err = m.ReserveIdempotencyKey(ctx, "test_user", "active", "a", "2999-01-01 00:00:00", "1999-12-31 00:00:00")
+	require.NoError(t, err)
+
+	n, err := idempotency.NewCleaner(loggerdiscard.NewDiscardLogger(), m, 0).Clean(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+
+	n, err = m.RemoveExpiredIdempotencyKeys(ctx, "2999-01-01 00:00:00")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+}
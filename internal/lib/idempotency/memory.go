@@ -0,0 +1,90 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+type entry struct {
+	record    types.IdempotencyRecord
+	expiresAt string
+}
+
+// Memory хранит ключи идемпотентности в памяти процесса. Подходит,
+// если экземпляр сервиса один: у каждого экземпляра ключи свои.
+type Memory struct {
+	mu   sync.Mutex
+	keys map[[2]string]entry
+}
+
+// NewMemory создает хранилище ключей в памяти.
+func NewMemory() *Memory {
+	return &Memory{
+		keys: make(map[[2]string]entry),
+	}
+}
+
+// ReserveIdempotencyKey сохраняет ключ пользователя с отпечатком запроса и возвращает true,
+// если ключа еще нет или он истек. Иначе возвращает уже сохраненную запись и false.
+func (m *Memory) ReserveIdempotencyKey(_ context.Context, login string, key string, fingerprint string, expires_at string, now string) (types.IdempotencyRecord, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// даты в формате "2006-01-02 15:04:05" можно сравнивать как строки
+	if e, ok := m.keys[[2]string{login, key}]; ok && e.expiresAt > now {
+		return e.record, false, nil
+	}
+
+	record := types.IdempotencyRecord{Fingerprint: fingerprint}
+	m.keys[[2]string{login, key}] = entry{record: record, expiresAt: expires_at}
+
+	return record, true, nil
+}
+
+// SaveIdempotentResponse сохраняет ответ на первый запрос с ключом, который хранится до expires_at.
+func (m *Memory) SaveIdempotentResponse(_ context.Context, login string, key string, status int, content_type string, body []byte, expires_at string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.keys[[2]string{login, key}]
+	if !ok {
+		return nil
+	}
+
+	e.record.Status = status
+	e.record.ContentType = content_type
+	e.record.Body = body
+	e.expiresAt = expires_at
+	m.keys[[2]string{login, key}] = e
+
+	return nil
+}
+
+// RemoveIdempotencyKey удаляет ключ, чтобы запрос с ним можно было выполнить заново.
+func (m *Memory) RemoveIdempotencyKey(_ context.Context, login string, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.keys, [2]string{login, key})
+
+	return nil
+}
+
+// RemoveExpiredIdempotencyKeys удаляет истекшие ключи и возвращает их количество.
+func (m *Memory) RemoveExpiredIdempotencyKeys(_ context.Context, now string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var removed int64
+
+	for k, e := range m.keys {
+		if e.expiresAt <= now {
+			delete(m.keys, k)
+			removed++
+		}
+	}
+
+	return removed, nil
+}
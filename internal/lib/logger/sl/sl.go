@@ -1,6 +1,11 @@
 package sl
 
-import "log/slog"
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
 
 func Err(err error) slog.Attr {
 	return slog.Attr{
@@ -9,3 +14,18 @@ func Err(err error) slog.Attr {
 	}
 
 }
+
+// Trace добавляет в запись ID трейса и спана из контекста.
+// Если в контексте нет спана, в запись ничего не добавляется.
+func Trace(ctx context.Context) slog.Attr {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return slog.Attr{}
+	}
+
+	// группа без ключа разворачивается в отдельные атрибуты
+	return slog.Group("",
+		slog.String("trace_id", sc.TraceID().String()),
+		slog.String("span_id", sc.SpanID().String()),
+	)
+}
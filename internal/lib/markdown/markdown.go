@@ -0,0 +1,178 @@
+package markdown
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/solumD/go-blog-api/internal/types"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// Renderer превращает текст поста в безопасный HTML.
+// Результат кэшируется для каждой ревизии поста, поэтому
+// один и тот же текст не рендерится повторно.
+type Renderer struct {
+	md     goldmark.Markdown
+	policy *bluemonday.Policy
+
+	mu    sync.Mutex
+	size  int
+	order *list.List
+	cache map[string]*list.Element
+}
+
+type entry struct {
+	key  string
+	html string
+}
+
+// New создает рендерер, который хранит в кэше не больше size отрендеренных постов.
+func New(size int) *Renderer {
+	return &Renderer{
+		md: goldmark.New(
+			goldmark.WithExtensions(
+				extension.Strikethrough,
+				extension.NewTable(
+					extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute),
+				),
+				extension.Linkify,
+			),
+		),
+		policy: newPolicy(),
+		size:   size,
+		order:  list.New(),
+		cache:  make(map[string]*list.Element, size),
+	}
+}
+
+// newPolicy возвращает список разрешенных тегов и атрибутов.
+// Все, что в него не входит, вырезается из итогового HTML.
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+
+	p.AllowElements(
+		"p", "br", "hr", "a", "img", "h1", "h2", "h3", "h4", "h5", "h6",
+		"blockquote", "pre", "code", "em", "strong", "del",
+		"ul", "ol", "li", "table", "thead", "tbody", "tr", "th", "td",
+	)
+
+	p.AllowAttrs("href").OnElements("a")
+	p.AllowAttrs("src", "alt", "title").OnElements("img")
+	p.AllowAttrs("align").Matching(regexp.MustCompile(`^(left|center|right)$`)).OnElements("th", "td")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w-]+$`)).OnElements("code")
+	p.AllowAttrs("start").Matching(bluemonday.Integer).OnElements("ol")
+
+	p.AllowURLSchemes("http", "https", "mailto")
+	p.RequireParseableURLs(true)
+	p.RequireNoFollowOnLinks(true)
+	p.AllowRelativeURLs(false)
+
+	return p
+}
+
+// Render возвращает HTML для текста в указанном формате без использования кэша.
+func (r *Renderer) Render(format string, text string) (string, error) {
+	switch format {
+	case types.FormatMarkdown:
+		var buf bytes.Buffer
+		if err := r.md.Convert([]byte(text), &buf); err != nil {
+			return "", fmt.Errorf("failed to convert markdown: %w", err)
+		}
+
+		return r.policy.Sanitize(buf.String()), nil
+	case types.FormatPlain, "":
+		return renderPlain(text), nil
+	}
+
+	return "", fmt.Errorf("unknown post format: %s", format)
+}
+
+// RenderPost заполняет поле HTML поста и оригинала репоста, используя кэш по id и ревизии поста.
+func (r *Renderer) RenderPost(post *types.Post) error {
+	if post.RepostOf != nil && !post.RepostOf.Tombstone {
+		if err := r.RenderPost(post.RepostOf); err != nil {
+			return err
+		}
+	}
+
+	key := fmt.Sprintf("%d:%d", post.ID, post.Revision)
+
+	if rendered, ok := r.get(key); ok {
+		post.HTML = rendered
+		return nil
+	}
+
+	rendered, err := r.Render(post.Format, post.Text)
+	if err != nil {
+		return err
+	}
+
+	r.put(key, rendered)
+	post.HTML = rendered
+
+	return nil
+}
+
+func (r *Renderer) get(key string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	el, ok := r.cache[key]
+	if !ok {
+		return "", false
+	}
+
+	r.order.MoveToFront(el)
+
+	return el.Value.(*entry).html, true
+}
+
+func (r *Renderer) put(key string, rendered string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.size <= 0 {
+		return
+	}
+
+	if el, ok := r.cache[key]; ok {
+		el.Value.(*entry).html = rendered
+		r.order.MoveToFront(el)
+		return
+	}
+
+	r.cache[key] = r.order.PushFront(&entry{key: key, html: rendered})
+
+	// вытесняем самые давно использованные записи
+	for r.order.Len() > r.size {
+		last := r.order.Back()
+		r.order.Remove(last)
+		delete(r.cache, last.Value.(*entry).key)
+	}
+}
+
+// renderPlain экранирует обычный текст и разбивает его на абзацы.
+func renderPlain(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	var b strings.Builder
+	for _, par := range strings.Split(text, "\n\n") {
+		par = strings.TrimSpace(par)
+		if par == "" {
+			continue
+		}
+
+		b.WriteString("<p>")
+		b.WriteString(strings.ReplaceAll(html.EscapeString(par), "\n", "<br>\n"))
+		b.WriteString("</p>\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,128 @@
+package markdown_test
+
+import (
+	"flag"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/solumD/go-blog-api/internal/lib/markdown"
+	"github.com/solumD/go-blog-api/internal/types"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestRenderGolden(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.md"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	r := markdown.New(0)
+
+	for _, file := range files {
+		file := file
+		name := strings.TrimSuffix(filepath.Base(file), ".md")
+
+		t.Run(name, func(t *testing.T) {
+			input, err := os.ReadFile(file)
+			require.NoError(t, err)
+
+			got, err := r.Render(types.FormatMarkdown, string(input))
+			require.NoError(t, err)
+
+			golden := strings.TrimSuffix(file, ".md") + ".golden"
+			if *update {
+				require.NoError(t, os.WriteFile(golden, []byte(got), 0o644))
+			}
+
+			want, err := os.ReadFile(golden)
+			require.NoError(t, err)
+
+			require.Equal(t, string(want), got)
+
+			requireSafe(t, got)
+		})
+	}
+}
+
+// requireSafe проверяет, что в HTML нет запрещенных тегов, обработчиков событий
+// и ссылок с опасными схемами - независимо от содержимого golden-файла.
+func requireSafe(t *testing.T, s string) {
+	t.Helper()
+
+	forbidden := map[string]bool{
+		"script": true, "iframe": true, "style": true, "svg": true,
+		"form": true, "input": true, "object": true, "embed": true,
+	}
+
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			require.ErrorIs(t, z.Err(), io.EOF)
+			return
+		}
+
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+
+		tok := z.Token()
+		require.False(t, forbidden[tok.Data], "forbidden tag <%s>", tok.Data)
+
+		for _, attr := range tok.Attr {
+			require.False(t, strings.HasPrefix(attr.Key, "on"), "event handler %s on <%s>", attr.Key, tok.Data)
+			require.NotEqual(t, "style", attr.Key)
+
+			if attr.Key == "href" || attr.Key == "src" {
+				u, err := url.Parse(attr.Val)
+				require.NoError(t, err)
+				require.Contains(t, []string{"http", "https", "mailto"}, u.Scheme, "unsafe url %q", attr.Val)
+			}
+		}
+	}
+}
+
+func TestRenderPlain(t *testing.T) {
+	r := markdown.New(0)
+
+	got, err := r.Render(types.FormatPlain, "**not bold** <b>tag</b>\nline\n\nnext")
+	require.NoError(t, err)
+	require.Equal(t, "<p>**not bold** &lt;b&gt;tag&lt;/b&gt;<br>\nline</p>\n<p>next</p>\n", got)
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+	r := markdown.New(0)
+
+	_, err := r.Render("html", "text")
+	require.Error(t, err)
+}
+
+func TestRenderPostCache(t *testing.T) {
+	r := markdown.New(1)
+
+	post := types.Post{ID: 1, Revision: 1, Format: types.FormatMarkdown, Text: "*one*"}
+	require.NoError(t, r.RenderPost(&post))
+	require.Equal(t, "<p><em>one</em></p>\n", post.HTML)
+
+	// та же ревизия - берем HTML из кэша, даже если текст в структуре другой
+	post.Text = "*two*"
+	require.NoError(t, r.RenderPost(&post))
+	require.Equal(t, "<p><em>one</em></p>\n", post.HTML)
+
+	// новая ревизия - рендерим заново
+	post.Revision = 2
+	require.NoError(t, r.RenderPost(&post))
+	require.Equal(t, "<p><em>two</em></p>\n", post.HTML)
+
+	// старая ревизия вытеснена из кэша размером 1
+	post.Revision = 1
+	post.Text = "*three*"
+	require.NoError(t, r.RenderPost(&post))
+	require.Equal(t, "<p><em>three</em></p>\n", post.HTML)
+}
@@ -0,0 +1,100 @@
+package media
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+type OrphanStorage interface {
+	GetOrphanMedia(ctx context.Context, created_before string) ([]types.Media, error)
+	RemoveOrphanMedia(ctx context.Context, id int64) (bool, error)
+}
+
+type BlobRemover interface {
+	Delete(ctx context.Context, key string) error
+}
+
+// Collector периодически удаляет загрузки, которые так и не были
+// прикреплены к посту, или пост которых был удален.
+type Collector struct {
+	log      *slog.Logger
+	storage  OrphanStorage
+	blobs    BlobRemover
+	ttl      time.Duration
+	interval time.Duration
+}
+
+// NewCollector создает сборщик, который удаляет файлы без поста старше ttl каждые interval.
+func NewCollector(log *slog.Logger, storage OrphanStorage, blobs BlobRemover, ttl time.Duration, interval time.Duration) *Collector {
+	return &Collector{
+		log:      log.With(slog.String("component", "media/collector")),
+		storage:  storage,
+		blobs:    blobs,
+		ttl:      ttl,
+		interval: interval,
+	}
+}
+
+// Run запускает сборку мусора и блокируется до отмены контекста.
+func (c *Collector) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := c.Collect(ctx)
+			if err != nil {
+				c.log.Error("failed to collect orphaned media", sl.Err(err))
+				continue
+			}
+
+			if n > 0 {
+				c.log.Info("orphaned media collected", slog.Int("count", n))
+			}
+		}
+	}
+}
+
+// Collect удаляет устаревшие загрузки и возвращает их количество.
+func (c *Collector) Collect(ctx context.Context) (int, error) {
+	const fn = "lib.media.Collector.Collect"
+
+	before := time.Now().Add(-c.ttl).Format("2006-01-02 15:04:05")
+
+	orphans, err := c.storage.GetOrphanMedia(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", fn, err)
+	}
+
+	removed := 0
+	for _, m := range orphans {
+		// запись удаляется только если файл до сих пор не прикреплен к посту,
+		// поэтому гонки с одновременным созданием поста нет
+		ok, err := c.storage.RemoveOrphanMedia(ctx, m.ID)
+		if err != nil {
+			return removed, fmt.Errorf("%s: %w", fn, err)
+		}
+
+		if !ok {
+			continue
+		}
+
+		for _, key := range []string{m.BlobKey, m.ThumbnailKey} {
+			if err := c.blobs.Delete(ctx, key); err != nil {
+				c.log.Error("failed to delete blob", slog.String("key", key), sl.Err(err))
+			}
+		}
+
+		removed++
+	}
+
+	return removed, nil
+}
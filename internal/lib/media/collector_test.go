@@ -0,0 +1,65 @@
+package media_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/solumD/go-blog-api/internal/lib/media"
+	"github.com/solumD/go-blog-api/internal/storage/blob"
+	"github.com/solumD/go-blog-api/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+// orphanStorage - хранилище, в котором загрузка 2 успела прикрепиться к посту
+// между выборкой и удалением.
+type orphanStorage struct {
+	orphans  []types.Media
+	attached map[int64]bool
+	removed  []int64
+}
+
+func (s *orphanStorage) GetOrphanMedia(ctx context.Context, created_before string) ([]types.Media, error) {
+	return s.orphans, nil
+}
+
+func (s *orphanStorage) RemoveOrphanMedia(ctx context.Context, id int64) (bool, error) {
+	if s.attached[id] {
+		return false, nil
+	}
+
+	s.removed = append(s.removed, id)
+
+	return true, nil
+}
+
+func TestCollector(t *testing.T) {
+	ctx := context.Background()
+	blobs := blob.NewMemory()
+
+	for _, key := range []string{"aaa1", "aaa1.thumb", "bbb2", "bbb2.thumb"} {
+		require.NoError(t, blobs.Put(ctx, key, bytes.NewReader([]byte(key))))
+	}
+
+	storage := &orphanStorage{
+		orphans: []types.Media{
+			{ID: 1, BlobKey: "aaa1", ThumbnailKey: "aaa1.thumb"},
+			{ID: 2, BlobKey: "bbb2", ThumbnailKey: "bbb2.thumb"},
+		},
+		attached: map[int64]bool{2: true},
+	}
+
+	c := media.NewCollector(loggerdiscard.NewDiscardLogger(), storage, blobs, time.Hour, time.Hour)
+
+	n, err := c.Collect(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, []int64{1}, storage.removed)
+
+	// файлы прикрепленной загрузки остались на месте
+	require.Equal(t, 2, blobs.Len())
+	_, err = blobs.Get(ctx, "aaa1")
+	require.ErrorIs(t, err, blob.ErrNotFound)
+}
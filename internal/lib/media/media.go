@@ -0,0 +1,118 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// maxPixels ограничивает размер декодируемого изображения,
+// чтобы маленький файл не мог занять гигабайты памяти после распаковки.
+const maxPixels = 40_000_000
+
+var (
+	ErrUnsupportedType = errors.New("unsupported media type")
+	ErrTooManyPixels   = errors.New("image dimensions are too large")
+)
+
+// allowedTypes - типы файлов, которые можно загрузить.
+var allowedTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// Image - результат обработки загруженного изображения.
+type Image struct {
+	ContentType   string
+	Width         int
+	Height        int
+	Thumbnail     []byte
+	ThumbnailType string
+}
+
+// Process определяет тип файла по его содержимому, а не по заголовкам запроса,
+// получает размеры изображения и создает его уменьшенную копию,
+// которая вписывается в квадрат со стороной thumbnailSize.
+func Process(data []byte, thumbnailSize int) (*Image, error) {
+	contentType := http.DetectContentType(data)
+	if !allowedTypes[contentType] {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, contentType)
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decode image config: %v", ErrUnsupportedType, err)
+	}
+
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, ErrTooManyPixels
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decode image: %v", ErrUnsupportedType, err)
+	}
+
+	thumb, thumbType, err := thumbnail(src, contentType, thumbnailSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Image{
+		ContentType:   contentType,
+		Width:         cfg.Width,
+		Height:        cfg.Height,
+		Thumbnail:     thumb,
+		ThumbnailType: thumbType,
+	}, nil
+}
+
+// thumbnail уменьшает изображение с сохранением пропорций.
+// JPEG остается JPEG, остальные форматы кодируются в PNG, чтобы не терять прозрачность.
+func thumbnail(src image.Image, contentType string, size int) ([]byte, string, error) {
+	b := src.Bounds()
+	w, h := fit(b.Dx(), b.Dy(), size)
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Over, nil)
+
+	var buf bytes.Buffer
+
+	if contentType == "image/jpeg" {
+		if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85}); err != nil {
+			return nil, "", fmt.Errorf("failed to encode thumbnail: %w", err)
+		}
+
+		return buf.Bytes(), "image/jpeg", nil
+	}
+
+	if err := png.Encode(&buf, dst); err != nil {
+		return nil, "", fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+
+	return buf.Bytes(), "image/png", nil
+}
+
+// fit возвращает размеры, в которые вписывается изображение w x h.
+// Изображения меньше size не увеличиваются.
+func fit(w, h, size int) (int, int) {
+	if w <= size && h <= size {
+		return w, h
+	}
+
+	if w >= h {
+		return size, max(1, h*size/w)
+	}
+
+	return max(1, w*size/h), size
+}
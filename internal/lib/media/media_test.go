@@ -0,0 +1,71 @@
+package media_test
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/solumD/go-blog-api/internal/lib/media"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcess(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 400, 100))
+
+	var pngBuf, jpegBuf bytes.Buffer
+	require.NoError(t, png.Encode(&pngBuf, src))
+	require.NoError(t, jpeg.Encode(&jpegBuf, src, nil))
+
+	testCases := []struct {
+		name          string
+		data          []byte
+		contentType   string
+		thumbnailType string
+		thumbW        int
+		thumbH        int
+	}{
+		{
+			name:          "PNG",
+			data:          pngBuf.Bytes(),
+			contentType:   "image/png",
+			thumbnailType: "image/png",
+			thumbW:        100,
+			thumbH:        25,
+		},
+		{
+			name:          "JPEG",
+			data:          jpegBuf.Bytes(),
+			contentType:   "image/jpeg",
+			thumbnailType: "image/jpeg",
+			thumbW:        100,
+			thumbH:        25,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			img, err := media.Process(tc.data, 100)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.contentType, img.ContentType)
+			require.Equal(t, 400, img.Width)
+			require.Equal(t, 100, img.Height)
+			require.Equal(t, tc.thumbnailType, img.ThumbnailType)
+
+			thumb, _, err := image.DecodeConfig(bytes.NewReader(img.Thumbnail))
+			require.NoError(t, err)
+			require.Equal(t, tc.thumbW, thumb.Width)
+			require.Equal(t, tc.thumbH, thumb.Height)
+		})
+	}
+}
+
+func TestProcessUnsupported(t *testing.T) {
+	_, err := media.Process([]byte("GIF89a"), 100)
+	require.ErrorIs(t, err, media.ErrUnsupportedType)
+
+	_, err = media.Process([]byte("%PDF-1.4"), 100)
+	require.ErrorIs(t, err, media.ErrUnsupportedType)
+}
@@ -0,0 +1,65 @@
+package mentions
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// MaxMentions - сколько упоминаний из одного текста учитывается
+const MaxMentions = 20
+
+// упоминание - @login в начале текста или после символа, который не может быть частью логина,
+// поэтому адреса почты вида user@example.com упоминаниями не считаются
+var mentionRe = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_@.])@([\p{L}\p{N}_.\-]+)`)
+
+type UserChecker interface {
+	IsUserExist(ctx context.Context, login string) (bool, error)
+}
+
+// Parse возвращает логины, упомянутые в тексте, без повторов и в порядке появления,
+// но не больше MaxMentions.
+func Parse(text string) []string {
+	var logins []string
+
+	seen := make(map[string]bool)
+	for _, m := range mentionRe.FindAllStringSubmatch(text, -1) {
+		// точка или дефис в конце обычно относятся к предложению, а не к логину
+		login := strings.TrimRight(m[1], ".-")
+		if login == "" || seen[login] {
+			continue
+		}
+
+		seen[login] = true
+		logins = append(logins, login)
+
+		if len(logins) == MaxMentions {
+			break
+		}
+	}
+
+	return logins
+}
+
+// Resolve возвращает упомянутых в тексте пользователей, которые существуют.
+// Автор текста в результат не попадает.
+func Resolve(ctx context.Context, users UserChecker, text string, author string) ([]string, error) {
+	var logins []string
+
+	for _, login := range Parse(text) {
+		if login == author {
+			continue
+		}
+
+		exist, err := users.IsUserExist(ctx, login)
+		if err != nil {
+			return nil, err
+		}
+
+		if exist {
+			logins = append(logins, login)
+		}
+	}
+
+	return logins, nil
+}
@@ -0,0 +1,84 @@
+package mentions_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/solumD/go-blog-api/internal/lib/mentions"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "No mentions",
+			text: "just a text",
+		},
+		{
+			name: "Start of text",
+			text: "@cool_user hi",
+			want: []string{"cool_user"},
+		},
+		{
+			name: "Punctuation around",
+			text: "thanks, @cool_user. (@another.user-1)",
+			want: []string{"cool_user", "another.user-1"},
+		},
+		{
+			name: "Duplicates",
+			text: "@cool_user and @cool_user again",
+			want: []string{"cool_user"},
+		},
+		{
+			name: "Email is not a mention",
+			text: "write to user@example.com",
+		},
+		{
+			name: "Lone at sign",
+			text: "meet @ noon, @@double",
+		},
+		{
+			name: "Unicode login",
+			text: "привет, @пользователь!",
+			want: []string{"пользователь"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, mentions.Parse(tc.text))
+		})
+	}
+}
+
+func TestParseLimit(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < mentions.MaxMentions+5; i++ {
+		b.WriteString(" @user")
+		b.WriteByte(byte('a' + i))
+	}
+
+	require.Len(t, mentions.Parse(b.String()), mentions.MaxMentions)
+}
+
+type users map[string]bool
+
+func (u users) IsUserExist(ctx context.Context, login string) (bool, error) {
+	return u[login], nil
+}
+
+func TestResolve(t *testing.T) {
+	got, err := mentions.Resolve(context.Background(), users{"cool_user": true, "author_user": true},
+		"@cool_user @ghost_user @author_user", "author_user")
+	require.NoError(t, err)
+	require.Equal(t, []string{"cool_user"}, got)
+}
@@ -0,0 +1,172 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "blog"
+
+// Metrics хранит метрики сервиса в отдельном реестре, а не в глобальном реестре Prometheus.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	queryDuration   *prometheus.HistogramVec
+
+	registrations prometheus.Counter
+	logins        *prometheus.CounterVec
+	postsCreated  prometheus.Counter
+	likes         prometheus.Counter
+}
+
+// New создает и регистрирует метрики сервиса, а также метрики Go и процесса.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests by route pattern and status.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of HTTP requests by route pattern and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "storage_query_duration_seconds",
+			Help:      "Duration of storage method calls.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"method"}),
+		registrations: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "users_registered_total",
+			Help:      "Number of registered users.",
+		}),
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "logins_total",
+			Help:      "Number of login attempts by result: ok or failed.",
+		}, []string{"result"}),
+		postsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "posts_created_total",
+			Help:      "Number of created posts.",
+		}),
+		likes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "likes_total",
+			Help:      "Number of likes put on posts.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.queryDuration,
+		m.registrations,
+		m.logins,
+		m.postsCreated,
+		m.likes,
+	)
+
+	return m
+}
+
+// Handler отдает метрики в формате Prometheus.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// ObserveRequest учитывает HTTP-запрос к маршруту route.
+func (m *Metrics) ObserveRequest(method string, route string, status int, d time.Duration) {
+	code := strconv.Itoa(status)
+
+	m.requests.WithLabelValues(method, route, code).Inc()
+	m.requestDuration.WithLabelValues(method, route, code).Observe(d.Seconds())
+}
+
+// ObserveQuery учитывает вызов метода хранилища.
+func (m *Metrics) ObserveQuery(method string, d time.Duration) {
+	m.queryDuration.WithLabelValues(method).Observe(d.Seconds())
+}
+
+// RegisterDBStats добавляет метрики пула соединений с БД, которые читаются из stats при каждом сборе.
+func (m *Metrics) RegisterDBStats(stats func() sql.DBStats) {
+	gauge := func(name string, help string, value func(s sql.DBStats) float64) prometheus.Collector {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "db",
+			Name:      name,
+			Help:      help,
+		}, func() float64 {
+			return value(stats())
+		})
+	}
+
+	counter := func(name string, help string, value func(s sql.DBStats) float64) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "db",
+			Name:      name,
+			Help:      help,
+		}, func() float64 {
+			return value(stats())
+		})
+	}
+
+	m.registry.MustRegister(
+		gauge("open_connections", "Number of open connections to the database.", func(s sql.DBStats) float64 {
+			return float64(s.OpenConnections)
+		}),
+		gauge("in_use_connections", "Number of connections currently in use.", func(s sql.DBStats) float64 {
+			return float64(s.InUse)
+		}),
+		gauge("idle_connections", "Number of idle connections.", func(s sql.DBStats) float64 {
+			return float64(s.Idle)
+		}),
+		counter("wait_count_total", "Number of connections waited for.", func(s sql.DBStats) float64 {
+			return float64(s.WaitCount)
+		}),
+		counter("wait_duration_seconds_total", "Time blocked waiting for a new connection.", func(s sql.DBStats) float64 {
+			return s.WaitDuration.Seconds()
+		}),
+	)
+}
+
+// UserRegistered учитывает регистрацию пользователя.
+func (m *Metrics) UserRegistered() {
+	m.registrations.Inc()
+}
+
+// LoginAttempted учитывает попытку входа.
+func (m *Metrics) LoginAttempted(ok bool) {
+	result := "ok"
+	if !ok {
+		result = "failed"
+	}
+
+	m.logins.WithLabelValues(result).Inc()
+}
+
+// PostCreated учитывает созданный пост.
+func (m *Metrics) PostCreated() {
+	m.postsCreated.Inc()
+}
+
+// PostLiked учитывает лайк.
+func (m *Metrics) PostLiked() {
+	m.likes.Inc()
+}
@@ -0,0 +1,227 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+	"github.com/solumD/go-blog-api/internal/lib/stream"
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+// EventPost - событие о новом посте. Оно не сохраняется как уведомление,
+// а только отправляется подписчикам автора в реальном времени.
+const EventPost = "post"
+
+// Event - событие, о котором нужно уведомить пользователя.
+type Event struct {
+	Type string
+	// кого уведомить; если пусто, уведомляется автор поста PostID
+	Recipient string
+	// кто совершил действие
+	Actor  string
+	PostID int64
+}
+
+type Storage interface {
+	GetPostCreator(ctx context.Context, id int) (string, error)
+	IsNotificationEnabled(ctx context.Context, login string, notificationType string) (bool, error)
+	SaveNotification(ctx context.Context, recipient string, notificationType string, actor string, postID int64, date_created string) error
+	// GetFollowers не возвращает подписчиков, которые заглушили login, и тех, с кем есть блокировка
+	GetFollowers(ctx context.Context, login string) ([]string, error)
+	IsBlocked(ctx context.Context, first string, second string) (bool, error)
+	IsMuted(ctx context.Context, muter string, muted string) (bool, error)
+	CanViewPost(ctx context.Context, id int, viewer string) (bool, error)
+}
+
+// Publisher отправляет события подключенным клиентам в реальном времени.
+type Publisher interface {
+	Publish(eventType string, recipients []string, data any)
+}
+
+// Notifier асинхронно сохраняет уведомления о событиях,
+// чтобы обработчики запросов не ждали записи в хранилище.
+type Notifier struct {
+	log       *slog.Logger
+	storage   Storage
+	publisher Publisher
+	events    chan Event
+}
+
+// New создает Notifier, который держит в очереди не больше bufferSize событий.
+func New(log *slog.Logger, storage Storage, publisher Publisher, bufferSize int) *Notifier {
+	return &Notifier{
+		log:       log.With(slog.String("component", "notifier")),
+		storage:   storage,
+		publisher: publisher,
+		events:    make(chan Event, bufferSize),
+	}
+}
+
+// Notify ставит событие в очередь и не блокируется.
+// Если очередь заполнена, событие отбрасывается: уведомления не важнее ответа на запрос.
+func (n *Notifier) Notify(e Event) {
+	select {
+	case n.events <- e:
+	default:
+		n.log.Warn("notification queue is full, event dropped", slog.String("type", e.Type))
+	}
+}
+
+// Run обрабатывает события из очереди и блокируется до отмены контекста.
+func (n *Notifier) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			n.drain()
+			return
+		case e := <-n.events:
+			if err := n.deliver(ctx, e); err != nil {
+				n.log.Error("failed to deliver notification", slog.String("type", e.Type), sl.Err(err))
+			}
+		}
+	}
+}
+
+// drain доставляет уведомления, которые остались в очереди при остановке.
+func (n *Notifier) drain() {
+	for {
+		select {
+		case e := <-n.events:
+			if err := n.deliver(context.Background(), e); err != nil {
+				n.log.Error("failed to deliver notification", slog.String("type", e.Type), sl.Err(err))
+			}
+		default:
+			return
+		}
+	}
+}
+
+// deliver сохраняет уведомление, если получатель не отключил уведомления этого типа,
+// и отправляет его получателю в реальном времени.
+// Пользователь не получает уведомлений о своих собственных действиях,
+// а также о действиях заглушенных пользователей и пользователей, с которыми есть блокировка.
+// Об упоминании в посте, который получатель не может прочитать, он не уведомляется.
+func (n *Notifier) deliver(ctx context.Context, e Event) error {
+	const fn = "lib.notifier.deliver"
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// о новом посте не узнают подписчики, которые заглушили автора или с которыми есть блокировка
+	if e.Type == EventPost {
+		followers, err := n.storage.GetFollowers(ctx, e.Actor)
+		if err != nil {
+			return fmt.Errorf("%s: %w", fn, err)
+		}
+
+		n.publisher.Publish(stream.EventPost, followers, stream.PostData{PostID: e.PostID, Author: e.Actor})
+
+		return nil
+	}
+
+	recipient := e.Recipient
+	if recipient == "" {
+		created_by, err := n.storage.GetPostCreator(ctx, int(e.PostID))
+		if errors.Is(err, storage.ErrPostNotFound) {
+			// пост успели удалить
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("%s: %w", fn, err)
+		}
+		recipient = created_by
+	}
+
+	if recipient == e.Actor {
+		return nil
+	}
+
+	blocked, err := n.storage.IsBlocked(ctx, recipient, e.Actor)
+	if err != nil {
+		return fmt.Errorf("%s: %w", fn, err)
+	}
+
+	muted, err := n.storage.IsMuted(ctx, recipient, e.Actor)
+	if err != nil {
+		return fmt.Errorf("%s: %w", fn, err)
+	}
+
+	if blocked || muted {
+		return nil
+	}
+
+	// иначе уведомление раскрыло бы, что скрытый от получателя пост существует
+	if e.Type == types.NotificationMention {
+		visible, err := n.storage.CanViewPost(ctx, int(e.PostID), recipient)
+		if err != nil {
+			return fmt.Errorf("%s: %w", fn, err)
+		}
+
+		if !visible {
+			return nil
+		}
+	}
+
+	// счетчик лайков обновляется в реальном времени независимо от настроек уведомлений
+	if e.Type == types.NotificationLike {
+		n.publisher.Publish(stream.EventLike, []string{recipient}, stream.LikeData{PostID: e.PostID, Actor: e.Actor})
+	}
+
+	enabled, err := n.storage.IsNotificationEnabled(ctx, recipient, e.Type)
+	if err != nil {
+		return fmt.Errorf("%s: %w", fn, err)
+	}
+
+	if !enabled {
+		return nil
+	}
+
+	date_created := time.Now().Format("2006-01-02 15:04:05")
+
+	if err := n.storage.SaveNotification(ctx, recipient, e.Type, e.Actor, e.PostID, date_created); err != nil {
+		return fmt.Errorf("%s: %w", fn, err)
+	}
+
+	// дата в том же виде, что и в GET /user/me/notifications
+	updatedAt, _ := time.Parse("2006-01-02 15:04:05", date_created)
+
+	notification := types.Notification{
+		Type:       e.Type,
+		PostID:     e.PostID,
+		Actor:      e.Actor,
+		Updated_at: updatedAt.Format(time.RFC3339),
+	}
+	notification.Text = Describe(notification)
+
+	n.publisher.Publish(stream.EventNotification, []string{recipient}, notification)
+
+	return nil
+}
+
+// Describe возвращает текст группы уведомлений, например, "alice and 12 others liked your post".
+func Describe(n types.Notification) string {
+	who := n.Actor
+	switch {
+	case n.Others == 1:
+		who += " and 1 other"
+	case n.Others > 1:
+		who += fmt.Sprintf(" and %d others", n.Others)
+	}
+
+	switch n.Type {
+	case types.NotificationLike:
+		return who + " liked your post"
+	case types.NotificationReaction:
+		return who + " reacted to your post"
+	case types.NotificationFollow:
+		return who + " followed you"
+	case types.NotificationMention:
+		return who + " mentioned you in a post"
+	}
+
+	return who
+}
@@ -0,0 +1,268 @@
+package notifier_test
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/solumD/go-blog-api/internal/lib/notifier"
+	"github.com/solumD/go-blog-api/internal/lib/stream"
+	storagepkg "github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/storage/sqlite"
+	"github.com/solumD/go-blog-api/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+type saved struct {
+	recipient string
+	kind      string
+	actor     string
+	postID    int64
+}
+
+type storage struct {
+	mu        sync.Mutex
+	authors   map[int]string
+	followers map[string][]string
+	disabled  map[string]bool
+	blocked   map[string]bool
+	muted     map[string]bool
+	// кто может читать пост, если он не публичный
+	readers map[int][]string
+	saved   []saved
+}
+
+func (s *storage) GetPostCreator(ctx context.Context, id int) (string, error) {
+	author, ok := s.authors[id]
+	if !ok {
+		return "", storagepkg.ErrPostNotFound
+	}
+
+	return author, nil
+}
+
+func (s *storage) IsNotificationEnabled(ctx context.Context, login string, notificationType string) (bool, error) {
+	return !s.disabled[login+":"+notificationType], nil
+}
+
+func (s *storage) SaveNotification(ctx context.Context, recipient string, notificationType string, actor string, postID int64, date_created string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.saved = append(s.saved, saved{recipient, notificationType, actor, postID})
+
+	return nil
+}
+
+func (s *storage) GetFollowers(ctx context.Context, login string) ([]string, error) {
+	return s.followers[login], nil
+}
+
+func (s *storage) IsBlocked(ctx context.Context, first string, second string) (bool, error) {
+	return s.blocked[first+":"+second] || s.blocked[second+":"+first], nil
+}
+
+func (s *storage) IsMuted(ctx context.Context, muter string, muted string) (bool, error) {
+	return s.muted[muter+":"+muted], nil
+}
+
+func (s *storage) CanViewPost(ctx context.Context, id int, viewer string) (bool, error) {
+	readers, ok := s.readers[id]
+	if !ok {
+		return true, nil
+	}
+
+	return slices.Contains(readers, viewer), nil
+}
+
+type published struct {
+	kind       string
+	recipients []string
+}
+
+type publisher struct {
+	mu        sync.Mutex
+	published []published
+}
+
+func (p *publisher) Publish(eventType string, recipients []string, data any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.published = append(p.published, published{eventType, recipients})
+}
+
+func (p *publisher) get() []published {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]published(nil), p.published...)
+}
+
+func (s *storage) get() []saved {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]saved(nil), s.saved...)
+}
+
+func TestNotifier(t *testing.T) {
+	st := &storage{
+		authors:   map[int]string{1: "author_user"},
+		followers: map[string][]string{"author_user": {"alice_user", "bob_user"}},
+		disabled:  map[string]bool{"quiet_user:follow": true},
+		blocked:   map[string]bool{"carol_user:author_user": true},
+		muted:     map[string]bool{"author_user:dave_user": true},
+		readers:   map[int][]string{4: {"author_user"}},
+	}
+	pub := &publisher{}
+
+	n := notifier.New(loggerdiscard.NewDiscardLogger(), st, pub, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go n.Run(ctx)
+
+	// получатель определяется по автору поста
+	n.Notify(notifier.Event{Type: types.NotificationLike, Actor: "alice_user", PostID: 1})
+	// о своих действиях не уведомляем
+	n.Notify(notifier.Event{Type: types.NotificationLike, Actor: "author_user", PostID: 1})
+	// пост удален
+	n.Notify(notifier.Event{Type: types.NotificationLike, Actor: "alice_user", PostID: 2})
+	// уведомления о подписках выключены
+	n.Notify(notifier.Event{Type: types.NotificationFollow, Recipient: "quiet_user", Actor: "alice_user"})
+	// получатель заблокировал того, кто совершил действие, или заглушил его
+	n.Notify(notifier.Event{Type: types.NotificationFollow, Recipient: "carol_user", Actor: "author_user"})
+	n.Notify(notifier.Event{Type: types.NotificationLike, Actor: "dave_user", PostID: 1})
+	n.Notify(notifier.Event{Type: types.NotificationMention, Recipient: "bob_user", Actor: "alice_user", PostID: 1})
+	// упоминание в приватном посте, который получатель не может прочитать
+	n.Notify(notifier.Event{Type: types.NotificationMention, Recipient: "bob_user", Actor: "author_user", PostID: 4})
+	// о новом посте узнают только подписчики, уведомление не сохраняется
+	n.Notify(notifier.Event{Type: notifier.EventPost, Actor: "author_user", PostID: 3})
+
+	want := []saved{
+		{"author_user", types.NotificationLike, "alice_user", 1},
+		{"bob_user", types.NotificationMention, "alice_user", 1},
+	}
+
+	require.Eventually(t, func() bool {
+		return len(st.get()) == len(want)
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, want, st.get())
+
+	wantPublished := []published{
+		{stream.EventLike, []string{"author_user"}},
+		{stream.EventNotification, []string{"author_user"}},
+		// лайк по удаленному посту, подписка с выключенными уведомлениями,
+		// события от заблокированных и заглушенных пользователей
+		// и упоминание в скрытом посте не публикуются
+		{stream.EventNotification, []string{"bob_user"}},
+		{stream.EventPost, []string{"alice_user", "bob_user"}},
+	}
+
+	require.Eventually(t, func() bool {
+		return len(pub.get()) == len(wantPublished)
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, wantPublished, pub.get())
+}
+
+func TestPostEventSkipsMutedAndBlockedFollowers(t *testing.T) {
+	ctx := context.Background()
+
+	// фильтр подписчиков - запрос хранилища, поэтому проверяется на настоящей базе
+	st, err := sqlite.New(filepath.Join(t.TempDir(), "blog.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = st.Close() })
+	require.NoError(t, st.Init(ctx))
+
+	const date = "2026-10-19 12:00:00"
+
+	for _, follower := range []string{"alice_user", "bob_user", "carol_user", "dave_user"} {
+		require.NoError(t, st.Follow(ctx, follower, "author_user", date))
+	}
+
+	require.NoError(t, st.Mute(ctx, "bob_user", "author_user", date))
+	// заглушение автором не скрывает от подписчика его посты
+	require.NoError(t, st.Mute(ctx, "author_user", "dave_user", date))
+	// блокировка удаляет подписку, поэтому подписка возвращается, как оставшаяся с прошлых версий
+	require.NoError(t, st.Block(ctx, "carol_user", "author_user", date))
+	require.NoError(t, st.Follow(ctx, "carol_user", "author_user", date))
+
+	pub := &publisher{}
+
+	n := notifier.New(loggerdiscard.NewDiscardLogger(), st, pub, 10)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go n.Run(runCtx)
+
+	n.Notify(notifier.Event{Type: notifier.EventPost, Actor: "author_user", PostID: 1})
+
+	require.Eventually(t, func() bool {
+		return len(pub.get()) == 1
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, []published{
+		{stream.EventPost, []string{"alice_user", "dave_user"}},
+	}, pub.get())
+}
+
+func TestRunDrainsQueueOnStop(t *testing.T) {
+	st := &storage{authors: map[int]string{1: "author_user"}}
+
+	n := notifier.New(loggerdiscard.NewDiscardLogger(), st, &publisher{}, 10)
+
+	// события в очереди до запуска доставляются, даже если контекст уже отменен
+	n.Notify(notifier.Event{Type: types.NotificationLike, Actor: "alice_user", PostID: 1})
+	n.Notify(notifier.Event{Type: types.NotificationLike, Actor: "bob_user", PostID: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	n.Run(ctx)
+
+	require.Equal(t, []saved{
+		{"author_user", types.NotificationLike, "alice_user", 1},
+		{"author_user", types.NotificationLike, "bob_user", 1},
+	}, st.get())
+}
+
+func TestNotifyDoesNotBlock(t *testing.T) {
+	n := notifier.New(loggerdiscard.NewDiscardLogger(), &storage{}, &publisher{}, 1)
+
+	done := make(chan struct{})
+	go func() {
+		// Run не запущен, поэтому второе событие отбрасывается
+		n.Notify(notifier.Event{Type: types.NotificationFollow, Recipient: "bob_user"})
+		n.Notify(notifier.Event{Type: types.NotificationFollow, Recipient: "bob_user"})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Notify blocked on a full queue")
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	testCases := []struct {
+		notification types.Notification
+		want         string
+	}{
+		{types.Notification{Type: types.NotificationLike, Actor: "alice"}, "alice liked your post"},
+		{types.Notification{Type: types.NotificationLike, Actor: "alice", Others: 1}, "alice and 1 other liked your post"},
+		{types.Notification{Type: types.NotificationReaction, Actor: "alice", Others: 12}, "alice and 12 others reacted to your post"},
+		{types.Notification{Type: types.NotificationFollow, Actor: "alice", Others: 2}, "alice and 2 others followed you"},
+		{types.Notification{Type: types.NotificationMention, Actor: "alice"}, "alice mentioned you in a post"},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.want, notifier.Describe(tc.notification))
+	}
+}
@@ -0,0 +1,138 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+// Verdict - решение правила о посте. Чем больше значение, тем строже решение.
+type Verdict int
+
+const (
+	// пост сохраняется как обычно
+	Allow Verdict = iota
+	// пост сохраняется, но попадает в очередь модерации
+	Flag
+	// пост не сохраняется
+	Reject
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case Allow:
+		return "allow"
+	case Flag:
+		return "flag"
+	case Reject:
+		return "reject"
+	}
+
+	return fmt.Sprintf("verdict(%d)", int(v))
+}
+
+// Reporter - от чьего имени помеченные посты попадают в очередь модерации.
+// Логин короче 8 символов, поэтому не совпадает ни с одним пользователем.
+const Reporter = "policy"
+
+// Content - проверяемый пост. При обновлении заполнены только изменяемые поля.
+type Content struct {
+	// id обновляемого поста, 0 для нового поста
+	PostID int64
+	Author string
+	Title  string
+	Text   string
+}
+
+// Decision - решение о посте и его причина.
+type Decision struct {
+	Verdict Verdict
+	// правило, которое приняло решение
+	Rule   string
+	Reason string
+}
+
+// Rule - одно правило проверки постов.
+type Rule interface {
+	Name() string
+	Check(ctx context.Context, c Content) (Decision, error)
+}
+
+// Pipeline прогоняет пост через все правила. Правила можно заменить
+// на лету, уже начатые проверки доходят до конца со старыми правилами.
+type Pipeline struct {
+	mu    sync.RWMutex
+	rules []Rule
+}
+
+// New создает пайплайн с правилами rules.
+func New(rules ...Rule) *Pipeline {
+	return &Pipeline{rules: rules}
+}
+
+// SetRules заменяет правила пайплайна.
+func (p *Pipeline) SetRules(rules []Rule) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rules = rules
+}
+
+// Check возвращает самое строгое решение правил. Проверка останавливается
+// на первом отказе, а из нескольких пометок возвращается первая.
+func (p *Pipeline) Check(ctx context.Context, c Content) (Decision, error) {
+	const fn = "lib.policy.Pipeline.Check"
+
+	p.mu.RLock()
+	rules := p.rules
+	p.mu.RUnlock()
+
+	result := Decision{Verdict: Allow}
+
+	for _, rule := range rules {
+		d, err := rule.Check(ctx, c)
+		if err != nil {
+			return Decision{}, fmt.Errorf("%s: rule %s: %w", fn, rule.Name(), err)
+		}
+
+		d.Rule = rule.Name()
+
+		if d.Verdict == Reject {
+			return d, nil
+		}
+
+		if d.Verdict > result.Verdict {
+			result = d
+		}
+	}
+
+	return result, nil
+}
+
+// ParseVerdict разбирает действие правила из файла правил.
+func ParseVerdict(action string) (Verdict, error) {
+	switch action {
+	case "flag":
+		return Flag, nil
+	case "reject":
+		return Reject, nil
+	}
+
+	return Allow, fmt.Errorf("invalid action %q, must be flag or reject", action)
+}
+
+// ReportReason - причина жалобы, с которой помеченный пост попадает в очередь модерации.
+func (d Decision) ReportReason() string {
+	if d.Rule == "blocklist" {
+		return types.ReportOther
+	}
+
+	return types.ReportSpam
+}
+
+// Note - пояснение к жалобе для модераторов.
+func (d Decision) Note() string {
+	return d.Rule + ": " + d.Reason
+}
@@ -0,0 +1,243 @@
+package policy_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/solumD/go-blog-api/internal/lib/policy"
+	"github.com/stretchr/testify/require"
+)
+
+type finderStub struct {
+	found bool
+}
+
+func (f finderStub) HasDuplicatePost(ctx context.Context, author string, text string, since string, exclude int64) (bool, error) {
+	return f.found, nil
+}
+
+func TestBlocklist(t *testing.T) {
+	blocklist, err := policy.NewBlocklist([]policy.Pattern{
+		{Pattern: "casino", Type: policy.PatternExact, Action: "flag"},
+		{Pattern: "free money", Action: "reject"},
+		{Pattern: "v?agr*", Type: policy.PatternWildcard, Action: "reject"},
+		{Pattern: `\d{3}-\d{4}`, Type: policy.PatternRegex, Action: "flag"},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name    string
+		content policy.Content
+		verdict policy.Verdict
+		reason  string
+	}{
+		{
+			name:    "Clean",
+			content: policy.Content{Title: "Hello", Text: "just a text"},
+			verdict: policy.Allow,
+		},
+		{
+			name:    "Exact word in title",
+			content: policy.Content{Title: "Best CASINO", Text: "just a text"},
+			verdict: policy.Flag,
+			reason:  `post contains blocked word "CASINO"`,
+		},
+		{
+			name:    "Exact word is not a part of another word",
+			content: policy.Content{Title: "Hello", Text: "casinos and minicasino"},
+			verdict: policy.Allow,
+		},
+		{
+			name:    "Exact phrase",
+			content: policy.Content{Title: "Hello", Text: "get free money now"},
+			verdict: policy.Reject,
+			reason:  `post contains blocked word "free money"`,
+		},
+		{
+			name:    "Wildcard",
+			content: policy.Content{Title: "Hello", Text: "cheap Viagra!"},
+			verdict: policy.Reject,
+			reason:  `post contains blocked word "Viagra"`,
+		},
+		{
+			name:    "Regex",
+			content: policy.Content{Title: "Hello", Text: "call 555-1234"},
+			verdict: policy.Flag,
+			reason:  `post contains blocked word "555-1234"`,
+		},
+		{
+			name:    "Reject wins over flag",
+			content: policy.Content{Title: "casino", Text: "free money"},
+			verdict: policy.Reject,
+			reason:  `post contains blocked word "free money"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			d, err := blocklist.Check(context.Background(), tc.content)
+			require.NoError(t, err)
+			require.Equal(t, tc.verdict, d.Verdict)
+			require.Equal(t, tc.reason, d.Reason)
+		})
+	}
+}
+
+func TestNewBlocklistInvalid(t *testing.T) {
+	_, err := policy.NewBlocklist([]policy.Pattern{{Pattern: "(", Type: policy.PatternRegex, Action: "flag"}})
+	require.Error(t, err)
+
+	_, err = policy.NewBlocklist([]policy.Pattern{{Pattern: "casino", Action: "ban"}})
+	require.Error(t, err)
+
+	_, err = policy.NewBlocklist([]policy.Pattern{{Pattern: "casino", Type: "glob", Action: "flag"}})
+	require.Error(t, err)
+}
+
+func TestLinks(t *testing.T) {
+	links := &policy.Links{Flag: 2, Reject: 4}
+
+	testCases := []struct {
+		name    string
+		text    string
+		verdict policy.Verdict
+	}{
+		{name: "One link", text: "see https://example.com", verdict: policy.Allow},
+		{name: "Flag", text: "see https://example.com and www.example.org", verdict: policy.Flag},
+		{name: "Reject", text: "http://a.com http://b.com http://c.com http://d.com", verdict: policy.Reject},
+	}
+
+	for _, tc := range testCases {
+		d, err := links.Check(context.Background(), policy.Content{Title: "Links", Text: tc.text})
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.verdict, d.Verdict, tc.name)
+	}
+}
+
+func TestDuplicates(t *testing.T) {
+	d, err := policy.NewDuplicates(finderStub{found: true}, time.Minute, policy.Reject).
+		Check(context.Background(), policy.Content{Author: "test_user", Text: "same text"})
+	require.NoError(t, err)
+	require.Equal(t, policy.Reject, d.Verdict)
+
+	// обновление только названия не проверяется
+	d, err = policy.NewDuplicates(finderStub{found: true}, time.Minute, policy.Reject).
+		Check(context.Background(), policy.Content{Author: "test_user", Title: "new title"})
+	require.NoError(t, err)
+	require.Equal(t, policy.Allow, d.Verdict)
+
+	d, err = policy.NewDuplicates(finderStub{}, time.Minute, policy.Reject).
+		Check(context.Background(), policy.Content{Author: "test_user", Text: "same text"})
+	require.NoError(t, err)
+	require.Equal(t, policy.Allow, d.Verdict)
+}
+
+func TestScore(t *testing.T) {
+	score := &policy.Score{Flag: 3, Reject: 5}
+
+	testCases := []struct {
+		name    string
+		text    string
+		verdict policy.Verdict
+	}{
+		{name: "Usual text", text: "I went for a walk today, it was nice!", verdict: policy.Allow},
+		{name: "Caps and exclamations", text: "BUY THIS AMAZING PRODUCT TODAY!!!!", verdict: policy.Flag},
+		{name: "Everything", text: "BUY BUY BUY BUY BUY NOW!!!!! http://a.com", verdict: policy.Reject},
+	}
+
+	for _, tc := range testCases {
+		d, err := score.Check(context.Background(), policy.Content{Title: "Title", Text: tc.text})
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.verdict, d.Verdict, tc.name+": "+d.Reason)
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	links := &policy.Links{Flag: 1}
+	score := &policy.Score{Reject: 1}
+
+	p := policy.New()
+
+	d, err := p.Check(context.Background(), policy.Content{Text: "see http://example.com"})
+	require.NoError(t, err)
+	require.Equal(t, policy.Allow, d.Verdict)
+
+	p.SetRules([]policy.Rule{links})
+
+	d, err = p.Check(context.Background(), policy.Content{Text: "see http://example.com"})
+	require.NoError(t, err)
+	require.Equal(t, policy.Flag, d.Verdict)
+	require.Equal(t, "links", d.Rule)
+
+	p.SetRules([]policy.Rule{links, score})
+
+	d, err = p.Check(context.Background(), policy.Content{Text: "see http://example.com"})
+	require.NoError(t, err)
+	require.Equal(t, policy.Reject, d.Verdict)
+	require.Equal(t, "score", d.Rule)
+}
+
+func TestWatcherReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+
+	write := func(content string, modTime time.Time) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+
+	now := time.Now()
+	write("links:\n  reject: 2\n", now)
+
+	p := policy.New()
+	w := policy.NewWatcher(loggerdiscard.NewDiscardLogger(), path, finderStub{}, p, time.Minute)
+
+	ok, err := w.Reload()
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	content := policy.Content{Text: "http://a.com http://b.com"}
+
+	d, err := p.Check(context.Background(), content)
+	require.NoError(t, err)
+	require.Equal(t, policy.Reject, d.Verdict)
+
+	// файл не менялся
+	ok, err = w.Reload()
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// сломанный файл не заменяет прежние правила
+	write("blocklist:\n  - pattern: casino\n    action: ban\n", now.Add(time.Second))
+
+	_, err = w.Reload()
+	require.Error(t, err)
+
+	d, err = p.Check(context.Background(), content)
+	require.NoError(t, err)
+	require.Equal(t, policy.Reject, d.Verdict)
+
+	write(strings.Join([]string{
+		"links:",
+		"  flag: 2",
+		"duplicates:",
+		"  window: 10m",
+		"  action: flag",
+	}, "\n"), now.Add(2*time.Second))
+
+	ok, err = w.Reload()
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	d, err = p.Check(context.Background(), content)
+	require.NoError(t, err)
+	require.Equal(t, policy.Flag, d.Verdict)
+}
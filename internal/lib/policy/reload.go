@@ -0,0 +1,141 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/solumD/go-blog-api/internal/lib/logger/sl"
+)
+
+// File - файл правил. Не заданные в файле правила не проверяются.
+type File struct {
+	Blocklist []Pattern `yaml:"blocklist"`
+	Links     struct {
+		Flag   int `yaml:"flag"`
+		Reject int `yaml:"reject"`
+	} `yaml:"links"`
+	Duplicates struct {
+		Window time.Duration `yaml:"window"`
+		Action string        `yaml:"action"`
+	} `yaml:"duplicates"`
+	Score struct {
+		Flag   int `yaml:"flag"`
+		Reject int `yaml:"reject"`
+	} `yaml:"score"`
+}
+
+// Load читает правила из файла path.
+func Load(path string, finder DuplicateFinder) ([]Rule, error) {
+	const fn = "lib.policy.Load"
+
+	var f File
+
+	if err := cleanenv.ReadConfig(path, &f); err != nil {
+		return nil, fmt.Errorf("%s: failed to read %s: %w", fn, path, err)
+	}
+
+	var rules []Rule
+
+	if len(f.Blocklist) > 0 {
+		blocklist, err := NewBlocklist(f.Blocklist)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fn, err)
+		}
+
+		rules = append(rules, blocklist)
+	}
+
+	if f.Links.Flag > 0 || f.Links.Reject > 0 {
+		rules = append(rules, &Links{Flag: f.Links.Flag, Reject: f.Links.Reject})
+	}
+
+	if f.Duplicates.Window > 0 {
+		verdict, err := ParseVerdict(f.Duplicates.Action)
+		if err != nil {
+			return nil, fmt.Errorf("%s: duplicates: %w", fn, err)
+		}
+
+		rules = append(rules, NewDuplicates(finder, f.Duplicates.Window, verdict))
+	}
+
+	if f.Score.Flag > 0 || f.Score.Reject > 0 {
+		rules = append(rules, &Score{Flag: f.Score.Flag, Reject: f.Score.Reject})
+	}
+
+	return rules, nil
+}
+
+// Watcher перечитывает файл правил, когда он меняется, и заменяет правила пайплайна.
+type Watcher struct {
+	log      *slog.Logger
+	path     string
+	finder   DuplicateFinder
+	pipeline *Pipeline
+	interval time.Duration
+	modTime  time.Time
+}
+
+// NewWatcher создает наблюдателя, который проверяет файл path каждые interval.
+func NewWatcher(log *slog.Logger, path string, finder DuplicateFinder, pipeline *Pipeline, interval time.Duration) *Watcher {
+	return &Watcher{
+		log:      log.With(slog.String("component", "policy/watcher")),
+		path:     path,
+		finder:   finder,
+		pipeline: pipeline,
+		interval: interval,
+	}
+}
+
+// Reload перечитывает файл правил, если он изменился с прошлой загрузки,
+// и сообщает, были ли заменены правила. При ошибке остаются прежние правила.
+func (w *Watcher) Reload() (bool, error) {
+	const fn = "lib.policy.Watcher.Reload"
+
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", fn, err)
+	}
+
+	if info.ModTime().Equal(w.modTime) {
+		return false, nil
+	}
+
+	// сломанный файл не перечитывается, пока его снова не изменят
+	w.modTime = info.ModTime()
+
+	rules, err := Load(w.path, w.finder)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", fn, err)
+	}
+
+	w.pipeline.SetRules(rules)
+
+	return true, nil
+}
+
+// Run проверяет файл правил и блокируется до отмены контекста.
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ok, err := w.Reload()
+			if err != nil {
+				w.log.Error("failed to reload content policy", sl.Err(err))
+				continue
+			}
+
+			if ok {
+				w.log.Info("content policy reloaded", slog.String("path", w.path))
+			}
+		}
+	}
+}
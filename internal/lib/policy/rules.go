@@ -0,0 +1,282 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Типы шаблонов запрещенных слов
+const (
+	// слово или фраза целиком, без учета регистра
+	PatternExact = "exact"
+	// слово, в котором * - любые буквы, а ? - одна буква, без учета регистра
+	PatternWildcard = "wildcard"
+	// регулярное выражение
+	PatternRegex = "regex"
+)
+
+// wordChar - символ, из которых состоят слова.
+const wordChar = `[\p{L}\p{N}_]`
+
+// Pattern - запрещенное слово из файла правил.
+type Pattern struct {
+	Pattern string `yaml:"pattern"`
+	Type    string `yaml:"type"`
+	Action  string `yaml:"action"`
+}
+
+type blockedPattern struct {
+	re      *regexp.Regexp
+	verdict Verdict
+}
+
+// Blocklist ищет запрещенные слова в названии и тексте поста.
+type Blocklist struct {
+	patterns []blockedPattern
+}
+
+// NewBlocklist компилирует шаблоны запрещенных слов.
+func NewBlocklist(patterns []Pattern) (*Blocklist, error) {
+	const fn = "lib.policy.NewBlocklist"
+
+	b := &Blocklist{}
+
+	for _, p := range patterns {
+		verdict, err := ParseVerdict(p.Action)
+		if err != nil {
+			return nil, fmt.Errorf("%s: pattern %q: %w", fn, p.Pattern, err)
+		}
+
+		var expr string
+
+		// у совпадения всегда есть первая группа, чтобы в причине было
+		// само запрещенное слово без соседних символов
+		switch p.Type {
+		case PatternExact, "":
+			expr = `(?i)(?:^|[^\p{L}\p{N}_])(` + regexp.QuoteMeta(p.Pattern) + `)(?:$|[^\p{L}\p{N}_])`
+		case PatternWildcard:
+			core := regexp.QuoteMeta(p.Pattern)
+			core = strings.ReplaceAll(core, `\*`, wordChar+`*`)
+			core = strings.ReplaceAll(core, `\?`, wordChar)
+			expr = `(?i)(?:^|[^\p{L}\p{N}_])(` + core + `)(?:$|[^\p{L}\p{N}_])`
+		case PatternRegex:
+			expr = `(` + p.Pattern + `)`
+		default:
+			return nil, fmt.Errorf("%s: pattern %q: invalid type %q", fn, p.Pattern, p.Type)
+		}
+
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("%s: pattern %q: %w", fn, p.Pattern, err)
+		}
+
+		b.patterns = append(b.patterns, blockedPattern{re: re, verdict: verdict})
+	}
+
+	return b, nil
+}
+
+func (b *Blocklist) Name() string {
+	return "blocklist"
+}
+
+// Check возвращает самое строгое решение среди найденных слов.
+func (b *Blocklist) Check(_ context.Context, c Content) (Decision, error) {
+	content := c.Title + "\n" + c.Text
+
+	d := Decision{Verdict: Allow}
+
+	for _, p := range b.patterns {
+		if p.verdict <= d.Verdict {
+			continue
+		}
+
+		m := p.re.FindStringSubmatch(content)
+		if m == nil {
+			continue
+		}
+
+		d = Decision{Verdict: p.verdict, Reason: fmt.Sprintf("post contains blocked word %q", m[1])}
+	}
+
+	return d, nil
+}
+
+// linkRe находит ссылки в тексте.
+var linkRe = regexp.MustCompile(`(?i)\bhttps?://|\bwww\.`)
+
+// Links ограничивает количество ссылок в посте. Нулевой порог не проверяется.
+type Links struct {
+	Flag   int
+	Reject int
+}
+
+func (l *Links) Name() string {
+	return "links"
+}
+
+func (l *Links) Check(_ context.Context, c Content) (Decision, error) {
+	n := countLinks(c.Title + "\n" + c.Text)
+
+	switch {
+	case l.Reject > 0 && n >= l.Reject:
+		return Decision{Verdict: Reject, Reason: fmt.Sprintf("post can't have more than %d links", l.Reject-1)}, nil
+	case l.Flag > 0 && n >= l.Flag:
+		return Decision{Verdict: Flag, Reason: fmt.Sprintf("post has %d links", n)}, nil
+	}
+
+	return Decision{Verdict: Allow}, nil
+}
+
+func countLinks(s string) int {
+	return len(linkRe.FindAllStringIndex(s, -1))
+}
+
+// DuplicateFinder ищет такой же пост автора.
+type DuplicateFinder interface {
+	HasDuplicatePost(ctx context.Context, author string, text string, since string, exclude int64) (bool, error)
+}
+
+// Duplicates не дает автору публиковать один и тот же текст чаще, чем раз в window.
+type Duplicates struct {
+	finder  DuplicateFinder
+	window  time.Duration
+	verdict Verdict
+}
+
+// NewDuplicates создает правило поиска повторов среди постов за последние window.
+func NewDuplicates(finder DuplicateFinder, window time.Duration, verdict Verdict) *Duplicates {
+	return &Duplicates{
+		finder:  finder,
+		window:  window,
+		verdict: verdict,
+	}
+}
+
+func (d *Duplicates) Name() string {
+	return "duplicates"
+}
+
+func (d *Duplicates) Check(ctx context.Context, c Content) (Decision, error) {
+	// при обновлении без нового текста сравнивать нечего
+	if c.Text == "" {
+		return Decision{Verdict: Allow}, nil
+	}
+
+	since := time.Now().Add(-d.window).Format("2006-01-02 15:04:05")
+
+	found, err := d.finder.HasDuplicatePost(ctx, c.Author, c.Text, since, c.PostID)
+	if err != nil {
+		return Decision{}, err
+	}
+
+	if !found {
+		return Decision{Verdict: Allow}, nil
+	}
+
+	return Decision{Verdict: d.verdict, Reason: "same post was already published recently"}, nil
+}
+
+// Score оценивает, насколько пост похож на спам, по простым признакам:
+// ссылкам, тексту капсом, повторяющимся символам и словам. Нулевой порог не проверяется.
+type Score struct {
+	Flag   int
+	Reject int
+}
+
+func (s *Score) Name() string {
+	return "score"
+}
+
+func (s *Score) Check(_ context.Context, c Content) (Decision, error) {
+	score, signals := spamScore(c.Title + "\n" + c.Text)
+
+	reason := fmt.Sprintf("spam score %d: %s", score, strings.Join(signals, ", "))
+
+	switch {
+	case s.Reject > 0 && score >= s.Reject:
+		return Decision{Verdict: Reject, Reason: reason}, nil
+	case s.Flag > 0 && score >= s.Flag:
+		return Decision{Verdict: Flag, Reason: reason}, nil
+	}
+
+	return Decision{Verdict: Allow}, nil
+}
+
+// spamScore считает очки спама и возвращает признаки, за которые они начислены.
+func spamScore(s string) (int, []string) {
+	score := 0
+	var signals []string
+
+	if n := countLinks(s); n > 0 {
+		score += n
+		signals = append(signals, fmt.Sprintf("%d links", n))
+	}
+
+	letters, upper := 0, 0
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+
+	if letters >= 20 && upper*10 >= letters*7 {
+		score += 2
+		signals = append(signals, "caps")
+	}
+
+	if hasRun(s, 5) {
+		score++
+		signals = append(signals, "repeated characters")
+	}
+
+	if strings.Count(s, "!") > 3 {
+		score++
+		signals = append(signals, "exclamation marks")
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	counts := make(map[string]int, len(words))
+	for _, w := range words {
+		counts[w]++
+
+		// слово встречается 5 раз и составляет треть текста
+		if counts[w] == 5 && counts[w]*3 >= len(words) {
+			score += 2
+			signals = append(signals, "repeated words")
+			break
+		}
+	}
+
+	return score, signals
+}
+
+// hasRun проверяет, есть ли в строке n одинаковых символов подряд, не считая пробелов.
+func hasRun(s string, n int) bool {
+	var prev rune
+	run := 0
+
+	for _, r := range s {
+		if r == prev && !unicode.IsSpace(r) {
+			run++
+		} else {
+			prev, run = r, 1
+		}
+
+		if run >= n {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,186 @@
+package querycost
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// PageArg - аргумент полей-страниц, размер которых умножает стоимость вложенных полей
+const PageArg = "first"
+
+// ErrUnknownOperation - в запросе нет операции с указанным именем
+var ErrUnknownOperation = errors.New("unknown operation")
+
+// Complexity считает сложность GraphQL запроса до его выполнения: каждое поле стоит 1,
+// а стоимость полей внутри страницы умножается на ее размер из аргумента first,
+// потому что они получаются для каждого элемента страницы. Если first не указан,
+// размер страницы - defaultFirst. Считается операция operationName или единственная
+// операция запроса. Сложность не бывает больше math.MaxInt32.
+func Complexity(query string, operationName string, variables map[string]any, defaultFirst int) (int, error) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	if err != nil {
+		return 0, err
+	}
+
+	op := doc.Operations.ForName(operationName)
+	if op == nil {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownOperation, operationName)
+	}
+
+	c := counter{
+		fragments:    doc.Fragments,
+		definitions:  op.VariableDefinitions,
+		variables:    variables,
+		defaultFirst: defaultFirst,
+		visiting:     make(map[string]bool),
+	}
+
+	return c.selectionSet(op.SelectionSet)
+}
+
+type counter struct {
+	fragments    ast.FragmentDefinitionList
+	definitions  ast.VariableDefinitionList
+	variables    map[string]any
+	defaultFirst int
+	// фрагменты, которые сейчас считаются: так находятся циклы
+	visiting map[string]bool
+}
+
+func (c *counter) selectionSet(set ast.SelectionSet) (int, error) {
+	total := 0
+
+	for _, selection := range set {
+		var cost int
+		var err error
+
+		switch s := selection.(type) {
+		case *ast.Field:
+			cost, err = c.field(s)
+		case *ast.InlineFragment:
+			cost, err = c.selectionSet(s.SelectionSet)
+		case *ast.FragmentSpread:
+			cost, err = c.fragment(s.Name)
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		total = add(total, cost)
+	}
+
+	return total, nil
+}
+
+func (c *counter) field(field *ast.Field) (int, error) {
+	children, err := c.selectionSet(field.SelectionSet)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(field.SelectionSet) > 0 && c.isPage(field) {
+		first, err := c.first(field.Arguments.ForName(PageArg))
+		if err != nil {
+			return 0, err
+		}
+
+		children = mul(children, first)
+	}
+
+	return add(1, children), nil
+}
+
+func (c *counter) fragment(name string) (int, error) {
+	fragment := c.fragments.ForName(name)
+	if fragment == nil {
+		return 0, fmt.Errorf("unknown fragment %q", name)
+	}
+
+	if c.visiting[name] {
+		return 0, fmt.Errorf("fragment %q spreads itself", name)
+	}
+
+	c.visiting[name] = true
+	defer delete(c.visiting, name)
+
+	return c.selectionSet(fragment.SelectionSet)
+}
+
+// isPage проверяет, что поле - страница. Без схемы это видно только по аргументу first
+// или по полям edges и pageInfo внутри.
+func (c *counter) isPage(field *ast.Field) bool {
+	if field.Arguments.ForName(PageArg) != nil {
+		return true
+	}
+
+	for _, selection := range field.SelectionSet {
+		if f, ok := selection.(*ast.Field); ok && (f.Name == "edges" || f.Name == "pageInfo") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// first возвращает размер страницы из аргумента first.
+func (c *counter) first(arg *ast.Argument) (int, error) {
+	if arg == nil {
+		return c.defaultFirst, nil
+	}
+
+	value, err := arg.Value.Value(c.variables)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", PageArg, err)
+	}
+
+	// значение переменной по умолчанию известно только из определения операции
+	if value == nil && arg.Value.Kind == ast.Variable {
+		if def := c.definitions.ForName(arg.Value.Raw); def != nil && def.DefaultValue != nil {
+			value, err = def.DefaultValue.Value(c.variables)
+			if err != nil {
+				return 0, fmt.Errorf("invalid %s: %w", PageArg, err)
+			}
+		}
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return c.defaultFirst, nil
+	case int64:
+		return clamp(v), nil
+	case int:
+		return clamp(int64(v)), nil
+	case float64:
+		return clamp(int64(v)), nil
+	default:
+		return 0, fmt.Errorf("invalid %s: %v", PageArg, value)
+	}
+}
+
+func clamp(v int64) int {
+	if v < 0 {
+		return 0
+	}
+
+	if v > math.MaxInt32 {
+		return math.MaxInt32
+	}
+
+	return int(v)
+}
+
+func add(a int, b int) int {
+	return clamp(int64(a) + int64(b))
+}
+
+func mul(a int, b int) int {
+	if a != 0 && b > math.MaxInt32/a {
+		return math.MaxInt32
+	}
+
+	return a * b
+}
@@ -0,0 +1,112 @@
+package querycost_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/solumD/go-blog-api/internal/lib/querycost"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComplexity(t *testing.T) {
+	testCases := []struct {
+		name      string
+		query     string
+		operation string
+		variables map[string]any
+		want      int
+		wantErr   bool
+	}{
+		{
+			name:  "Flat fields",
+			query: `{ me { login displayName } }`,
+			want:  3,
+		},
+		{
+			name:  "Page with first",
+			query: `{ user(login: "test_user") { posts(first: 5) { edges { node { id title } } } } }`,
+			// user + posts + 5 * (edges + node + id + title)
+			want: 2 + 5*4,
+		},
+		{
+			name:  "Page without first uses default",
+			query: `{ user(login: "test_user") { posts { edges { node { id } } } } }`,
+			want:  2 + 10*3,
+		},
+		{
+			name:  "Nested pages multiply",
+			query: `{ user(login: "test_user") { posts(first: 10) { edges { node { reactions(first: 20) { edges { node { kind } } } } } } } }`,
+			want:  2 + 10*(3+20*3),
+		},
+		{
+			name:      "First from variable",
+			query:     `query Posts($first: Int) { user(login: "test_user") { posts(first: $first) { edges { node { id } } } } }`,
+			variables: map[string]any{"first": float64(3)},
+			want:      2 + 3*3,
+		},
+		{
+			name:  "First from variable default",
+			query: `query Posts($first: Int = 4) { user(login: "test_user") { posts(first: $first) { edges { node { id } } } } }`,
+			want:  2 + 4*3,
+		},
+		{
+			name: "Fragments",
+			query: `
+				query { user(login: "test_user") { ...UserFields } }
+				fragment UserFields on User { login ... on User { bio } }
+			`,
+			want: 3,
+		},
+		{
+			name: "Named operation",
+			query: `
+				query A { me { login } }
+				query B { me { login bio } }
+			`,
+			operation: "B",
+			want:      3,
+		},
+		{
+			name:  "Saturates",
+			query: `{ a(first: 100000) { b(first: 100000) { c(first: 100000) { d } } } }`,
+			want:  math.MaxInt32,
+		},
+		{
+			name: "Fragment cycle",
+			query: `
+				query { me { ...A } }
+				fragment A on User { ...B }
+				fragment B on User { ...A }
+			`,
+			wantErr: true,
+		},
+		{
+			name:      "Unknown operation",
+			query:     `query A { me { login } }`,
+			operation: "B",
+			wantErr:   true,
+		},
+		{
+			name:    "Syntax error",
+			query:   `{ me { login }`,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := querycost.Complexity(tc.query, tc.operation, tc.variables, 10)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit - ограничение частоты запросов: Requests запросов за Per,
+// но не больше Burst подряд. Нулевой Burst равен Requests.
+type Limit struct {
+	Requests int           `yaml:"requests"`
+	Per      time.Duration `yaml:"per"`
+	Burst    int           `yaml:"burst"`
+}
+
+// Capacity - сколько запросов можно сделать подряд.
+func (l Limit) Capacity() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+
+	return l.Requests
+}
+
+// rate - сколько токенов добавляется в ведро за секунду.
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Per.Seconds()
+}
+
+// Result - результат попытки сделать запрос.
+type Result struct {
+	Allowed bool
+	// сколько запросов еще можно сделать подряд
+	Remaining int
+	// через сколько ведро заполнится целиком
+	Reset time.Duration
+	// через сколько можно повторить запрос, если он не разрешен
+	RetryAfter time.Duration
+}
+
+// Bucket - ведро токенов: каждый запрос забирает токен, а токены
+// добавляются равномерно со скоростью limit.
+type Bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// NewBucket создает полное ведро.
+func NewBucket(limit Limit, now time.Time) *Bucket {
+	return &Bucket{
+		tokens:  float64(limit.Capacity()),
+		updated: now,
+	}
+}
+
+// Take забирает токен из ведра, если он есть.
+func (b *Bucket) Take(limit Limit, now time.Time) Result {
+	capacity := float64(limit.Capacity())
+	rate := limit.rate()
+
+	if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+elapsed*rate)
+		b.updated = now
+	}
+
+	res := Result{}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - b.tokens) / rate)
+	}
+
+	res.Remaining = int(b.tokens)
+	res.Reset = seconds((capacity - b.tokens) / rate)
+
+	return res
+}
+
+// full проверяет, заполнится ли ведро целиком к моменту now.
+func (b *Bucket) full(limit Limit, now time.Time) bool {
+	return b.tokens+now.Sub(b.updated).Seconds()*limit.rate() >= float64(limit.Capacity())
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// sweepInterval - как часто хранилище в памяти удаляет полные ведра.
+const sweepInterval = time.Minute
+
+type entry struct {
+	bucket *Bucket
+	limit  Limit
+}
+
+// Memory хранит ведра в памяти процесса. Полные ведра ничем не отличаются
+// от новых, поэтому время от времени удаляются.
+type Memory struct {
+	mu        sync.Mutex
+	buckets   map[string]entry
+	lastSweep time.Time
+}
+
+// NewMemory создает хранилище ведер в памяти.
+func NewMemory() *Memory {
+	return &Memory{
+		buckets:   make(map[string]entry),
+		lastSweep: time.Now(),
+	}
+}
+
+// Take забирает токен из ведра key.
+func (m *Memory) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	if now.Sub(m.lastSweep) >= sweepInterval {
+		for k, e := range m.buckets {
+			if e.bucket.full(e.limit, now) {
+				delete(m.buckets, k)
+			}
+		}
+
+		m.lastSweep = now
+	}
+
+	e, ok := m.buckets[key]
+	if !ok {
+		e = entry{bucket: NewBucket(limit, now), limit: limit}
+		m.buckets[key] = e
+	}
+
+	return e.bucket.Take(limit, now), nil
+}
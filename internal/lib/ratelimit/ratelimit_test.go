@@ -0,0 +1,76 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/solumD/go-blog-api/internal/lib/ratelimit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucket(t *testing.T) {
+	limit := ratelimit.Limit{Requests: 1, Per: time.Second, Burst: 3}
+
+	now := time.Now()
+	b := ratelimit.NewBucket(limit, now)
+
+	// ведро полное, поэтому можно сделать burst запросов подряд
+	for i := 2; i >= 0; i-- {
+		res := b.Take(limit, now)
+		require.True(t, res.Allowed)
+		require.Equal(t, i, res.Remaining)
+	}
+
+	res := b.Take(limit, now)
+	require.False(t, res.Allowed)
+	require.Equal(t, 0, res.Remaining)
+	require.Equal(t, time.Second, res.RetryAfter)
+	require.Equal(t, 3*time.Second, res.Reset)
+
+	// за полсекунды токен еще не появился
+	res = b.Take(limit, now.Add(500*time.Millisecond))
+	require.False(t, res.Allowed)
+	require.Equal(t, 500*time.Millisecond, res.RetryAfter)
+
+	res = b.Take(limit, now.Add(time.Second))
+	require.True(t, res.Allowed)
+
+	// ведро не переполняется
+	res = b.Take(limit, now.Add(time.Hour))
+	require.True(t, res.Allowed)
+	require.Equal(t, 2, res.Remaining)
+}
+
+func TestBucketDefaultBurst(t *testing.T) {
+	limit := ratelimit.Limit{Requests: 2, Per: time.Minute}
+	require.Equal(t, 2, limit.Capacity())
+
+	now := time.Now()
+	b := ratelimit.NewBucket(limit, now)
+
+	require.True(t, b.Take(limit, now).Allowed)
+	require.True(t, b.Take(limit, now).Allowed)
+
+	res := b.Take(limit, now)
+	require.False(t, res.Allowed)
+	require.Equal(t, 30*time.Second, res.RetryAfter)
+}
+
+func TestMemory(t *testing.T) {
+	store := ratelimit.NewMemory()
+	limit := ratelimit.Limit{Requests: 1, Per: time.Hour}
+
+	res, err := store.Take(context.Background(), "first", limit)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+
+	res, err = store.Take(context.Background(), "first", limit)
+	require.NoError(t, err)
+	require.False(t, res.Allowed)
+
+	// у каждого ключа свое ведро
+	res, err = store.Take(context.Background(), "second", limit)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+}
@@ -0,0 +1,164 @@
+package stream
+
+import (
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+// Типы событий, которые отправляются клиентам
+const (
+	// пользователь, на которого подписан клиент, опубликовал пост
+	EventPost = "post"
+	// пост клиента лайкнули
+	EventLike = "like"
+	// клиент получил уведомление
+	EventNotification = "notification"
+	// часть событий после Last-Event-ID потеряна, клиенту нужно перезапросить данные
+	EventReset = "reset"
+)
+
+// Event - событие, которое отправляется получателям.
+type Event struct {
+	ID         uint64
+	Type       string
+	Recipients []string
+	Data       any
+}
+
+// PostData - данные события EventPost.
+type PostData struct {
+	PostID int64  `json:"post_id"`
+	Author string `json:"author"`
+}
+
+// LikeData - данные события EventLike.
+type LikeData struct {
+	PostID int64  `json:"post_id"`
+	Actor  string `json:"actor"`
+}
+
+// Subscription - подписка одного соединения на события пользователя.
+type Subscription struct {
+	login  string
+	events chan Event
+}
+
+// Events возвращает канал событий подписки.
+// Канал закрывается, когда подписка отменена или соединение не успевает читать события.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Hub рассылает события подписанным соединениям внутри процесса
+// и хранит последние события, чтобы клиент мог продолжить с Last-Event-ID.
+type Hub struct {
+	log        *slog.Logger
+	mu         sync.Mutex
+	lastID     uint64
+	replay     []Event
+	replaySize int
+	bufferSize int
+	subs       map[*Subscription]struct{}
+}
+
+// NewHub создает Hub, который хранит replaySize последних событий
+// и держит в очереди каждого соединения не больше bufferSize событий.
+func NewHub(log *slog.Logger, replaySize int, bufferSize int) *Hub {
+	return &Hub{
+		log:        log.With(slog.String("component", "stream")),
+		replaySize: replaySize,
+		bufferSize: bufferSize,
+		subs:       make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish отправляет событие получателям и не блокируется.
+// Соединение, очередь которого заполнена, отключается: клиент переподключится
+// с Last-Event-ID и получит пропущенные события из буфера.
+func (h *Hub) Publish(eventType string, recipients []string, data any) {
+	if len(recipients) == 0 {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastID++
+	e := Event{
+		ID:         h.lastID,
+		Type:       eventType,
+		Recipients: recipients,
+		Data:       data,
+	}
+
+	if h.replaySize > 0 {
+		if len(h.replay) == h.replaySize {
+			h.replay = append(h.replay[:0], h.replay[1:]...)
+		}
+		h.replay = append(h.replay, e)
+	}
+
+	for sub := range h.subs {
+		if !slices.Contains(e.Recipients, sub.login) {
+			continue
+		}
+
+		select {
+		case sub.events <- e:
+		default:
+			h.log.Warn("stream subscriber is too slow, disconnected", slog.String("login", sub.login))
+			h.remove(sub)
+		}
+	}
+}
+
+// Subscribe подписывает соединение пользователя login на события.
+// Если lastID не 0, возвращает события после lastID из буфера.
+// ok == false означает, что часть событий после lastID уже вытеснена из буфера.
+func (h *Hub) Subscribe(login string, lastID uint64) (sub *Subscription, missed []Event, ok bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub = &Subscription{
+		login:  login,
+		events: make(chan Event, h.bufferSize),
+	}
+	h.subs[sub] = struct{}{}
+
+	if lastID == 0 {
+		return sub, nil, true
+	}
+
+	// после перезапуска сервера нумерация событий начинается заново
+	if lastID > h.lastID {
+		return sub, nil, false
+	}
+
+	ok = lastID == h.lastID || (len(h.replay) > 0 && h.replay[0].ID <= lastID+1)
+
+	for _, e := range h.replay {
+		if e.ID > lastID && slices.Contains(e.Recipients, login) {
+			missed = append(missed, e)
+		}
+	}
+
+	return sub, missed, ok
+}
+
+// Unsubscribe отменяет подписку. Повторный вызов ничего не делает.
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(sub)
+}
+
+func (h *Hub) remove(sub *Subscription) {
+	if _, ok := h.subs[sub]; !ok {
+		return
+	}
+
+	delete(h.subs, sub)
+	close(sub.events)
+}
@@ -0,0 +1,95 @@
+package stream_test
+
+import (
+	"testing"
+
+	"github.com/solumD/go-blog-api/internal/lib/logger/loggerdiscard"
+	"github.com/solumD/go-blog-api/internal/lib/stream"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHubDelivery(t *testing.T) {
+	hub := stream.NewHub(loggerdiscard.NewDiscardLogger(), 10, 10)
+
+	alice, _, _ := hub.Subscribe("alice_user", 0)
+	bob, _, _ := hub.Subscribe("bob_user", 0)
+
+	hub.Publish(stream.EventPost, []string{"alice_user", "bob_user"}, nil)
+	hub.Publish(stream.EventLike, []string{"bob_user"}, nil)
+
+	require.Equal(t, uint64(1), (<-alice.Events()).ID)
+	require.Equal(t, uint64(1), (<-bob.Events()).ID)
+	require.Equal(t, uint64(2), (<-bob.Events()).ID)
+	require.Len(t, alice.Events(), 0)
+
+	hub.Unsubscribe(alice)
+	hub.Unsubscribe(alice)
+
+	_, ok := <-alice.Events()
+	require.False(t, ok)
+}
+
+func TestHubDropsSlowSubscriber(t *testing.T) {
+	hub := stream.NewHub(loggerdiscard.NewDiscardLogger(), 10, 1)
+
+	sub, _, _ := hub.Subscribe("alice_user", 0)
+
+	hub.Publish(stream.EventLike, []string{"alice_user"}, nil)
+	hub.Publish(stream.EventLike, []string{"alice_user"}, nil)
+
+	// первое событие успело попасть в очередь, после него канал закрыт
+	e, ok := <-sub.Events()
+	require.True(t, ok)
+	require.Equal(t, uint64(1), e.ID)
+
+	_, ok = <-sub.Events()
+	require.False(t, ok)
+
+	// пропущенное событие можно получить, переподключившись с Last-Event-ID
+	_, missed, complete := hub.Subscribe("alice_user", 1)
+	require.True(t, complete)
+	require.Len(t, missed, 1)
+	require.Equal(t, uint64(2), missed[0].ID)
+}
+
+func TestHubReplay(t *testing.T) {
+	testCases := []struct {
+		name     string
+		lastID   uint64
+		missed   []uint64
+		complete bool
+	}{
+		{name: "No Last-Event-ID", lastID: 0, complete: true},
+		{name: "Up to date", lastID: 6, complete: true},
+		{name: "Only other users' events", lastID: 5, complete: true},
+		{name: "Within buffer", lastID: 3, missed: []uint64{4, 5}, complete: true},
+		{name: "Events lost", lastID: 2, missed: []uint64{4, 5}, complete: false},
+		{name: "Server restarted", lastID: 42, complete: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// в буфере остаются события 4, 5 и 6
+			hub := stream.NewHub(loggerdiscard.NewDiscardLogger(), 3, 10)
+			for i := 0; i < 5; i++ {
+				hub.Publish(stream.EventLike, []string{"alice_user"}, nil)
+			}
+			// событие другого пользователя не повторяется
+			hub.Publish(stream.EventLike, []string{"bob_user"}, nil)
+
+			_, missed, complete := hub.Subscribe("alice_user", tc.lastID)
+
+			var ids []uint64
+			for _, e := range missed {
+				ids = append(ids, e.ID)
+			}
+
+			require.Equal(t, tc.missed, ids)
+			require.Equal(t, tc.complete, complete)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/solumD/go-blog-api/internal/lib/buildinfo"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// ServiceName - название сервиса в трейсах.
+const ServiceName = "go-blog-api"
+
+// Экспортеры спанов.
+const (
+	// спаны не отправляются, но у запросов все равно есть ID трейсов для логов
+	ExporterNone = "none"
+	// спаны выводятся в stdout
+	ExporterStdout = "stdout"
+	// спаны отправляются по OTLP/HTTP на Endpoint
+	ExporterOTLP = "otlp"
+)
+
+// Config - настройки трейсинга.
+type Config struct {
+	Exporter string `yaml:"exporter" env-default:"none"`
+	// адрес коллектора для ExporterOTLP
+	Endpoint string `yaml:"endpoint" env-default:"localhost:4318"`
+	// отправлять спаны без TLS
+	Insecure bool `yaml:"insecure" env-default:"true"`
+	// доля запросов, трейсы которых записываются: от 0 до 1
+	SampleRatio float64 `yaml:"sample_ratio" env-default:"1"`
+}
+
+// New создает провайдер трейсов с экспортером из конфига.
+// Перед остановкой сервиса у провайдера вызывается Shutdown, чтобы отправить оставшиеся спаны.
+func New(ctx context.Context, cfg Config) (*sdktrace.TracerProvider, error) {
+	const fn = "lib.tracing.New"
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(ServiceName),
+		semconv.ServiceVersion(buildinfo.Get().Commit),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fn, err)
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		// входящий traceparent решает, записывать ли трейс, чтобы трейсы не обрывались
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	}
+
+	switch cfg.Exporter {
+	case ExporterNone, "":
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fn, err)
+		}
+
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	case ExporterOTLP:
+		clientOpts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			clientOpts = append(clientOpts, otlptracehttp.WithInsecure())
+		}
+
+		// соединение с коллектором устанавливается при отправке, поэтому сервис запускается и без него
+		exporter, err := otlptracehttp.New(ctx, clientOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fn, err)
+		}
+
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	default:
+		return nil, fmt.Errorf("%s: unknown exporter %q", fn, cfg.Exporter)
+	}
+
+	return sdktrace.NewTracerProvider(opts...), nil
+}
@@ -0,0 +1,26 @@
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/solumD/go-blog-api/internal/lib/tracing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	for _, exporter := range []string{tracing.ExporterNone, tracing.ExporterStdout, tracing.ExporterOTLP} {
+		tp, err := tracing.New(context.Background(), tracing.Config{Exporter: exporter, Endpoint: "localhost:4318", SampleRatio: 1})
+		require.NoError(t, err, exporter)
+
+		// без экспортера у спанов все равно есть ID для логов
+		_, span := tp.Tracer("test").Start(context.Background(), "test")
+		require.True(t, span.SpanContext().IsValid(), exporter)
+		span.End()
+
+		require.NoError(t, tp.Shutdown(context.Background()), exporter)
+	}
+
+	_, err := tracing.New(context.Background(), tracing.Config{Exporter: "jaeger"})
+	require.Error(t, err)
+}
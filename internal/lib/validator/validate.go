@@ -2,7 +2,12 @@ package validator
 
 import (
 	"fmt"
+	"net/url"
+	"slices"
 	"strings"
+	"unicode/utf8"
+
+	"github.com/solumD/go-blog-api/internal/types"
 )
 
 // ValidatePassword проверяет корректность пароля указанным требованиям.
@@ -32,3 +37,133 @@ func ValidateLogin(login string) error {
 
 	return nil
 }
+
+// ValidatePostFormat проверяет, что формат текста поста поддерживается.
+// Если все ок, то возвращает nil, иначе - ошибку.
+func ValidatePostFormat(format string) error {
+	switch format {
+	case types.FormatPlain, types.FormatMarkdown:
+		return nil
+	}
+
+	return fmt.Errorf("post's format must be %s or %s", types.FormatPlain, types.FormatMarkdown)
+}
+
+// ValidateVisibility проверяет, что видимость поста поддерживается.
+// Если все ок, то возвращает nil, иначе - ошибку.
+func ValidateVisibility(visibility string) error {
+	switch visibility {
+	case types.VisibilityPublic, types.VisibilityFollowers, types.VisibilityUnlisted, types.VisibilityPrivate:
+		return nil
+	}
+
+	return fmt.Errorf("post's visibility must be one of: %s, %s, %s, %s",
+		types.VisibilityPublic, types.VisibilityFollowers, types.VisibilityUnlisted, types.VisibilityPrivate)
+}
+
+// ValidateCollectionName проверяет имя коллекции закладок.
+// Если все ок, то возвращает nil, иначе - ошибку.
+func ValidateCollectionName(name string) error {
+	if utf8.RuneCountInString(name) > 50 {
+		return fmt.Errorf("collection name cannot be longer than 50 characters")
+	}
+
+	return nil
+}
+
+// ValidateReaction проверяет, что вид реакции есть среди разрешенных в конфиге.
+// Если все ок, то возвращает nil, иначе - ошибку.
+func ValidateReaction(kind string, kinds []string) error {
+	if slices.Contains(kinds, kind) {
+		return nil
+	}
+
+	return fmt.Errorf("reaction must be one of: %s", strings.Join(kinds, ", "))
+}
+
+// ValidateMessage проверяет текст личного сообщения.
+// Если все ок, то возвращает nil, иначе - ошибку.
+func ValidateMessage(text string) error {
+	if len(text) == 0 {
+		return fmt.Errorf("message can't be empty")
+	}
+
+	if utf8.RuneCountInString(text) > 2000 {
+		return fmt.Errorf("message cannot be longer than 2000 characters")
+	}
+
+	return nil
+}
+
+// ValidateDisplayName проверяет отображаемое имя пользователя.
+// Если все ок, то возвращает nil, иначе - ошибку.
+func ValidateDisplayName(name string) error {
+	if utf8.RuneCountInString(name) > 50 {
+		return fmt.Errorf("display name cannot be longer than 50 characters")
+	}
+
+	if strings.ContainsAny(name, "\r\n") {
+		return fmt.Errorf("display name cannot contain line breaks")
+	}
+
+	return nil
+}
+
+// ValidateBio проверяет описание профиля пользователя.
+// Если все ок, то возвращает nil, иначе - ошибку.
+func ValidateBio(bio string) error {
+	if utf8.RuneCountInString(bio) > 160 {
+		return fmt.Errorf("bio cannot be longer than 160 characters")
+	}
+
+	return nil
+}
+
+// ValidateWebsite проверяет ссылку на сайт пользователя: пустую строку или http(s) адрес.
+// Если все ок, то возвращает nil, иначе - ошибку.
+func ValidateWebsite(website string) error {
+	if website == "" {
+		return nil
+	}
+
+	if len(website) > 100 {
+		return fmt.Errorf("website cannot be longer than 100 characters")
+	}
+
+	u, err := url.Parse(website)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("website must be an http or https url")
+	}
+
+	return nil
+}
+
+// ValidateReportReason проверяет, что причина жалобы поддерживается.
+// Если все ок, то возвращает nil, иначе - ошибку.
+func ValidateReportReason(reason string) error {
+	if slices.Contains(types.ReportReasons, reason) {
+		return nil
+	}
+
+	return fmt.Errorf("reason must be one of: %s", strings.Join(types.ReportReasons, ", "))
+}
+
+// ValidateModerationAction проверяет, что решение модератора поддерживается.
+// Если все ок, то возвращает nil, иначе - ошибку.
+func ValidateModerationAction(action string) error {
+	if slices.Contains(types.ModerationActions, action) {
+		return nil
+	}
+
+	return fmt.Errorf("action must be one of: %s", strings.Join(types.ModerationActions, ", "))
+}
+
+// ValidateNote проверяет комментарий к жалобе или решению модератора.
+// Если все ок, то возвращает nil, иначе - ошибку.
+func ValidateNote(note string) error {
+	if utf8.RuneCountInString(note) > 500 {
+		return fmt.Errorf("note cannot be longer than 500 characters")
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// BlobStore хранит содержимое загруженных файлов по ключу.
+// Метаданные файлов хранятся отдельно, в основном хранилище.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+var keyRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{2,127}$`)
+
+// validateKey не дает использовать ключи, которые могут выйти за пределы хранилища.
+func validateKey(key string) error {
+	if !keyRegexp.MatchString(key) {
+		return ErrInvalidKey
+	}
+
+	return nil
+}
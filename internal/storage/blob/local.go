@@ -0,0 +1,102 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Local хранит файлы в директории на локальном диске.
+type Local struct {
+	root string
+}
+
+// NewLocal создает хранилище файлов в указанной директории.
+func NewLocal(root string) (*Local, error) {
+	const fnNewLocal = "storage.blob.NewLocal"
+
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("%s: failed to create root directory: %w", fnNewLocal, err)
+	}
+
+	return &Local{root: root}, nil
+}
+
+// path раскладывает файлы по поддиректориям, чтобы не держать их все в одной.
+func (l *Local) path(key string) string {
+	return filepath.Join(l.root, key[:2], key)
+}
+
+// Put сохраняет файл. Запись идет во временный файл, который затем
+// переименовывается, поэтому читатели никогда не увидят файл частично.
+func (l *Local) Put(ctx context.Context, key string, r io.Reader) error {
+	const fnPut = "storage.blob.Local.Put"
+
+	if err := validateKey(key); err != nil {
+		return fmt.Errorf("%s: %w", fnPut, err)
+	}
+
+	path := l.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("%s: failed to create directory: %w", fnPut, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), key+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("%s: failed to create temp file: %w", fnPut, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("%s: failed to write file: %w", fnPut, err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("%s: failed to close file: %w", fnPut, err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("%s: failed to move file: %w", fnPut, err)
+	}
+
+	return nil
+}
+
+// Get открывает файл на чтение. Закрыть его должен вызывающий.
+func (l *Local) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	const fnGet = "storage.blob.Local.Get"
+
+	if err := validateKey(key); err != nil {
+		return nil, fmt.Errorf("%s: %w", fnGet, err)
+	}
+
+	f, err := os.Open(l.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: failed to open file: %w", fnGet, err)
+	}
+
+	return f, nil
+}
+
+// Delete удаляет файл. Удаление несуществующего файла не считается ошибкой.
+func (l *Local) Delete(ctx context.Context, key string) error {
+	const fnDelete = "storage.blob.Local.Delete"
+
+	if err := validateKey(key); err != nil {
+		return fmt.Errorf("%s: %w", fnDelete, err)
+	}
+
+	err := os.Remove(l.path(key))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%s: failed to delete file: %w", fnDelete, err)
+	}
+
+	return nil
+}
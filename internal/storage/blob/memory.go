@@ -0,0 +1,70 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Memory хранит файлы в памяти. Используется в тестах.
+type Memory struct {
+	mu    sync.RWMutex
+	blobs map[string][]byte
+}
+
+// NewMemory создает пустое хранилище файлов в памяти.
+func NewMemory() *Memory {
+	return &Memory{blobs: make(map[string][]byte)}
+}
+
+// Put сохраняет файл.
+func (m *Memory) Put(ctx context.Context, key string, r io.Reader) error {
+	const fnPut = "storage.blob.Memory.Put"
+
+	if err := validateKey(key); err != nil {
+		return fmt.Errorf("%s: %w", fnPut, err)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("%s: failed to read file: %w", fnPut, err)
+	}
+
+	m.mu.Lock()
+	m.blobs[key] = data
+	m.mu.Unlock()
+
+	return nil
+}
+
+// Get возвращает содержимое файла.
+func (m *Memory) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	m.mu.RLock()
+	data, ok := m.blobs[key]
+	m.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// Delete удаляет файл.
+func (m *Memory) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	delete(m.blobs, key)
+	m.mu.Unlock()
+
+	return nil
+}
+
+// Len возвращает количество сохраненных файлов.
+func (m *Memory) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.blobs)
+}
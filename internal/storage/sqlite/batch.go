@@ -0,0 +1,100 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+// ApplyPostOperations выполняет операции пользователя с постами в одной транзакции
+// и возвращает ID поста и ошибку каждой операции.
+// Если atomic, первая же ошибка откатывает транзакцию, а следующие операции не выполняются.
+// Иначе каждая операция выполняется в своей точке сохранения: ошибочная откатывается,
+// остальные сохраняются. Изменить или удалить можно только свой пост, иначе ошибка
+// операции - storage.ErrPostNotFound или storage.ErrForbidden.
+func (s *Storage) ApplyPostOperations(ctx context.Context, created_by string, ops []types.PostOperation, atomic bool, date string) ([]int64, []error, error) {
+	const fnApplyPostOperations = "storage.sqlite.ApplyPostOperations"
+	ctx, end := s.begin(ctx, fnApplyPostOperations)
+	defer end()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: failed to begin transaction: %w", fnApplyPostOperations, err)
+	}
+	defer tx.Rollback()
+
+	ids := make([]int64, len(ops))
+	errs := make([]error, len(ops))
+
+	for i, op := range ops {
+		if atomic {
+			ids[i], errs[i] = applyPostOperation(ctx, tx, created_by, op, date)
+			if errs[i] != nil {
+				return ids, errs, nil
+			}
+
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, `SAVEPOINT post_operation`); err != nil {
+			return nil, nil, fmt.Errorf("%s: failed to create savepoint: %w", fnApplyPostOperations, err)
+		}
+
+		ids[i], errs[i] = applyPostOperation(ctx, tx, created_by, op, date)
+		if errs[i] != nil {
+			if _, err := tx.ExecContext(ctx, `ROLLBACK TO post_operation`); err != nil {
+				return nil, nil, fmt.Errorf("%s: failed to roll back to savepoint: %w", fnApplyPostOperations, err)
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, `RELEASE post_operation`); err != nil {
+			return nil, nil, fmt.Errorf("%s: failed to release savepoint: %w", fnApplyPostOperations, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("%s: failed to commit transaction: %w", fnApplyPostOperations, err)
+	}
+
+	return ids, errs, nil
+}
+
+// applyPostOperation выполняет одну операцию в транзакции tx и возвращает ID поста.
+func applyPostOperation(ctx context.Context, tx *sql.Tx, created_by string, op types.PostOperation, date string) (int64, error) {
+	if op.Op == types.OperationCreate {
+		return savePost(ctx, tx, created_by, op.Title, op.Text, op.Format, op.Visibility, op.Media, date)
+	}
+
+	// автор проверяется в той же транзакции: пост могли удалить предыдущей операцией
+	var author string
+
+	err := tx.QueryRowContext(ctx, `SELECT created_by FROM posts WHERE id = ?`, op.ID).Scan(&author)
+	if errors.Is(err, sql.ErrNoRows) {
+		return op.ID, storage.ErrPostNotFound
+	} else if err != nil {
+		return op.ID, fmt.Errorf("failed to check if post exists: %w", err)
+	}
+
+	if author != created_by {
+		return op.ID, fmt.Errorf("post %d: %w", op.ID, storage.ErrForbidden)
+	}
+
+	switch op.Op {
+	case types.OperationUpdate:
+		if err := updatePost(ctx, tx, op.ID, op.Title, op.Text, op.Format, op.Visibility, date); err != nil {
+			return op.ID, err
+		}
+	case types.OperationDelete:
+		if err := removePost(ctx, tx, op.ID); err != nil {
+			return op.ID, err
+		}
+	default:
+		return op.ID, fmt.Errorf("unknown operation: %s", op.Op)
+	}
+
+	return op.ID, nil
+}
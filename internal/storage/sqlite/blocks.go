@@ -0,0 +1,142 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// notBlocked - условие, при котором между автором поста и пользователем @viewer нет блокировки
+// ни в одну сторону.
+const notBlocked = `NOT EXISTS (SELECT 1 FROM blocks WHERE
+	(blocks.blocker = posts.created_by AND blocks.blocked = @viewer)
+	OR (blocks.blocker = @viewer AND blocks.blocked = posts.created_by))`
+
+const notBlockedOriginal = `NOT EXISTS (SELECT 1 FROM blocks WHERE
+	(blocks.blocker = original.created_by AND blocks.blocked = @viewer)
+	OR (blocks.blocker = @viewer AND blocks.blocked = original.created_by))`
+
+// notMuted - условие, при котором пользователь @viewer не заглушил автора поста.
+const notMuted = `NOT EXISTS (SELECT 1 FROM mutes WHERE mutes.muter = @viewer AND mutes.muted = posts.created_by)`
+
+// notMutedOriginal - условие, при котором пользователь @viewer не заглушил автора оригинала репоста.
+const notMutedOriginal = `(posts.repost_of IS NULL OR NOT EXISTS (
+	SELECT 1 FROM posts AS original JOIN mutes ON mutes.muted = original.created_by
+	WHERE original.id = posts.repost_of AND mutes.muter = @viewer))`
+
+// IsBlocked проверяет, заблокировал ли один из пользователей другого.
+func (s *Storage) IsBlocked(ctx context.Context, first string, second string) (bool, error) {
+	const fnIsBlocked = "storage.sqlite.IsBlocked"
+	ctx, end := s.begin(ctx, fnIsBlocked)
+	defer end()
+
+	q := `
+		SELECT EXISTS (SELECT 1 FROM blocks WHERE
+		(blocker = @first AND blocked = @second) OR (blocker = @second AND blocked = @first))`
+
+	var blocked bool
+
+	err := s.db.QueryRowContext(ctx, q, sql.Named("first", first), sql.Named("second", second)).Scan(&blocked)
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to check block between %s and %s: %w", fnIsBlocked, first, second, err)
+	}
+
+	return blocked, nil
+}
+
+// Block блокирует blocked для blocker. Подписки пользователей друг на друга удаляются.
+// Повторная блокировка ничего не меняет.
+func (s *Storage) Block(ctx context.Context, blocker string, blocked string, date_created string) error {
+	const fnBlock = "storage.sqlite.Block"
+	ctx, end := s.begin(ctx, fnBlock)
+	defer end()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", fnBlock, err)
+	}
+	defer tx.Rollback()
+
+	q := `INSERT OR IGNORE INTO blocks(blocker, blocked, date_created) VALUES(?, ?, ?)`
+
+	if _, err := tx.ExecContext(ctx, q, blocker, blocked, date_created); err != nil {
+		return fmt.Errorf("%s: failed to save %s's block: %w", fnBlock, blocker, err)
+	}
+
+	q = `
+		DELETE FROM follows
+		WHERE (follower = @blocker AND followee = @blocked) OR (follower = @blocked AND followee = @blocker)`
+
+	_, err = tx.ExecContext(ctx, q, sql.Named("blocker", blocker), sql.Named("blocked", blocked))
+	if err != nil {
+		return fmt.Errorf("%s: failed to delete follows between %s and %s: %w", fnBlock, blocker, blocked, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", fnBlock, err)
+	}
+
+	return nil
+}
+
+// Unblock снимает блокировку blocked пользователем blocker.
+func (s *Storage) Unblock(ctx context.Context, blocker string, blocked string) error {
+	const fnUnblock = "storage.sqlite.Unblock"
+	ctx, end := s.begin(ctx, fnUnblock)
+	defer end()
+
+	q := `DELETE FROM blocks WHERE blocker = ? AND blocked = ?`
+
+	if _, err := s.db.ExecContext(ctx, q, blocker, blocked); err != nil {
+		return fmt.Errorf("%s: failed to delete %s's block: %w", fnUnblock, blocker, err)
+	}
+
+	return nil
+}
+
+// IsMuted проверяет, заглушил ли muter пользователя muted.
+func (s *Storage) IsMuted(ctx context.Context, muter string, muted string) (bool, error) {
+	const fnIsMuted = "storage.sqlite.IsMuted"
+	ctx, end := s.begin(ctx, fnIsMuted)
+	defer end()
+
+	q := `SELECT EXISTS (SELECT 1 FROM mutes WHERE muter = ? AND muted = ?)`
+
+	var isMuted bool
+
+	if err := s.db.QueryRowContext(ctx, q, muter, muted).Scan(&isMuted); err != nil {
+		return false, fmt.Errorf("%s: failed to check if %s muted %s: %w", fnIsMuted, muter, muted, err)
+	}
+
+	return isMuted, nil
+}
+
+// Mute заглушает muted для muter. Повторное заглушение ничего не меняет.
+func (s *Storage) Mute(ctx context.Context, muter string, muted string, date_created string) error {
+	const fnMute = "storage.sqlite.Mute"
+	ctx, end := s.begin(ctx, fnMute)
+	defer end()
+
+	q := `INSERT OR IGNORE INTO mutes(muter, muted, date_created) VALUES(?, ?, ?)`
+
+	if _, err := s.db.ExecContext(ctx, q, muter, muted, date_created); err != nil {
+		return fmt.Errorf("%s: failed to save %s's mute: %w", fnMute, muter, err)
+	}
+
+	return nil
+}
+
+// Unmute снимает заглушение muted пользователем muter.
+func (s *Storage) Unmute(ctx context.Context, muter string, muted string) error {
+	const fnUnmute = "storage.sqlite.Unmute"
+	ctx, end := s.begin(ctx, fnUnmute)
+	defer end()
+
+	q := `DELETE FROM mutes WHERE muter = ? AND muted = ?`
+
+	if _, err := s.db.ExecContext(ctx, q, muter, muted); err != nil {
+		return fmt.Errorf("%s: failed to delete %s's mute: %w", fnUnmute, muter, err)
+	}
+
+	return nil
+}
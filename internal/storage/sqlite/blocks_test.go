@@ -0,0 +1,207 @@
+package sqlite_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	sqlite "github.com/solumD/go-blog-api/internal/storage/sqlite"
+	"github.com/solumD/go-blog-api/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+const date = "2026-10-19 12:00:00"
+
+func newStorage(t *testing.T) *sqlite.Storage {
+	storage, err := sqlite.New(filepath.Join(t.TempDir(), "blog.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = storage.Close() })
+
+	require.NoError(t, storage.Init(context.Background()))
+
+	return storage
+}
+
+func postIDs(posts []types.Post) []int64 {
+	var ids []int64
+	for _, post := range posts {
+		ids = append(ids, post.ID)
+	}
+
+	return ids
+}
+
+func TestBlocksAndMutesInFeeds(t *testing.T) {
+	testCases := []struct {
+		name string
+		// блокировки и заглушения между пользователями
+		setup func(ctx context.Context, s *sqlite.Storage) error
+		// видит ли viewer_user пост author_user, упоминание в нем,
+		// простой репост этого поста от reposter_user и собственный пост reposter_user
+		wantPosts    bool
+		wantMention  bool
+		wantRepost   bool
+		wantReposter bool
+	}{
+		{
+			name:         "No blocks",
+			setup:        func(ctx context.Context, s *sqlite.Storage) error { return nil },
+			wantPosts:    true,
+			wantMention:  true,
+			wantRepost:   true,
+			wantReposter: true,
+		},
+		{
+			name: "Author blocked viewer",
+			setup: func(ctx context.Context, s *sqlite.Storage) error {
+				return s.Block(ctx, "author_user", "viewer_user", date)
+			},
+			wantReposter: true,
+		},
+		{
+			name: "Viewer blocked author",
+			setup: func(ctx context.Context, s *sqlite.Storage) error {
+				return s.Block(ctx, "viewer_user", "author_user", date)
+			},
+			wantReposter: true,
+		},
+		{
+			name: "Reposter blocked viewer",
+			setup: func(ctx context.Context, s *sqlite.Storage) error {
+				return s.Block(ctx, "reposter_user", "viewer_user", date)
+			},
+			wantPosts:   true,
+			wantMention: true,
+		},
+		{
+			name: "Viewer blocked reposter",
+			setup: func(ctx context.Context, s *sqlite.Storage) error {
+				return s.Block(ctx, "viewer_user", "reposter_user", date)
+			},
+			wantPosts:   true,
+			wantMention: true,
+		},
+		{
+			name: "Block removed",
+			setup: func(ctx context.Context, s *sqlite.Storage) error {
+				if err := s.Block(ctx, "author_user", "viewer_user", date); err != nil {
+					return err
+				}
+
+				return s.Unblock(ctx, "author_user", "viewer_user")
+			},
+			wantPosts:    true,
+			wantMention:  true,
+			wantRepost:   true,
+			wantReposter: true,
+		},
+		{
+			name: "Viewer muted author",
+			setup: func(ctx context.Context, s *sqlite.Storage) error {
+				return s.Mute(ctx, "viewer_user", "author_user", date)
+			},
+			// заглушение скрывает упоминания и репосты, но не страницу автора
+			wantPosts:    true,
+			wantReposter: true,
+		},
+		{
+			name: "Author muted viewer",
+			setup: func(ctx context.Context, s *sqlite.Storage) error {
+				return s.Mute(ctx, "author_user", "viewer_user", date)
+			},
+			wantPosts:    true,
+			wantMention:  true,
+			wantRepost:   true,
+			wantReposter: true,
+		},
+		{
+			name: "Viewer muted reposter",
+			setup: func(ctx context.Context, s *sqlite.Storage) error {
+				return s.Mute(ctx, "viewer_user", "reposter_user", date)
+			},
+			wantPosts:    true,
+			wantMention:  true,
+			wantRepost:   true,
+			wantReposter: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			s := newStorage(t)
+
+			postID, err := s.SavePost(ctx, "author_user", "Very Cool Title", "Hi @viewer_user", "plain", "public", nil, date)
+			require.NoError(t, err)
+
+			_, err = s.SetPostMentions(ctx, postID, []string{"viewer_user"}, date)
+			require.NoError(t, err)
+
+			repostID, err := s.Repost(ctx, int(postID), "reposter_user", "", "plain", "public", date)
+			require.NoError(t, err)
+
+			ownID, err := s.SavePost(ctx, "reposter_user", "Own Title", "Own Text", "plain", "public", nil, date)
+			require.NoError(t, err)
+
+			require.NoError(t, tc.setup(ctx, s))
+
+			var wantAuthor, wantReposter []int64
+			if tc.wantPosts {
+				wantAuthor = []int64{postID}
+			}
+			if tc.wantReposter {
+				wantReposter = append(wantReposter, ownID)
+			}
+			// посты одного времени идут от нового id к старому
+			if tc.wantRepost {
+				wantReposter = append(wantReposter, repostID)
+			}
+
+			posts, err := s.GetPosts(ctx, "author_user", "viewer_user")
+			require.NoError(t, err)
+			require.Equal(t, wantAuthor, postIDs(posts.Posts), "GetPosts of author")
+
+			listed, err := s.ListPosts(ctx, "author_user", "viewer_user", "", 0, 10)
+			require.NoError(t, err)
+			require.Equal(t, wantAuthor, postIDs(listed), "ListPosts of author")
+
+			posts, err = s.GetPosts(ctx, "reposter_user", "viewer_user")
+			require.NoError(t, err)
+			require.Equal(t, wantReposter, postIDs(posts.Posts), "GetPosts of reposter")
+
+			listed, err = s.ListPosts(ctx, "reposter_user", "viewer_user", "", 0, 10)
+			require.NoError(t, err)
+			require.Equal(t, wantReposter, postIDs(listed), "ListPosts of reposter")
+
+			var wantMentions []int64
+			if tc.wantMention {
+				wantMentions = []int64{postID}
+			}
+
+			mentions, err := s.GetMentions(ctx, "viewer_user", 10, 0)
+			require.NoError(t, err)
+			require.Equal(t, wantMentions, postIDs(mentions), "GetMentions")
+
+			byIDs, err := s.GetPostsByIDs(ctx, []int64{postID}, "viewer_user")
+			require.NoError(t, err)
+			_, found := byIDs[postID]
+			require.Equal(t, tc.wantPosts, found, "GetPostsByIDs")
+
+			visible, err := s.CanViewPost(ctx, int(postID), "viewer_user")
+			require.NoError(t, err)
+			require.Equal(t, tc.wantPosts, visible, "CanViewPost")
+
+			// у блокировок нет направления: проверка одинакова для обоих пользователей
+			blocked, err := s.IsBlocked(ctx, "viewer_user", "author_user")
+			require.NoError(t, err)
+			reverse, err := s.IsBlocked(ctx, "author_user", "viewer_user")
+			require.NoError(t, err)
+			require.Equal(t, blocked, reverse, "IsBlocked")
+			require.Equal(t, !tc.wantPosts, blocked, "IsBlocked")
+		})
+	}
+}
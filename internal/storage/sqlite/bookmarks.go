@@ -0,0 +1,145 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+// IsPostBookmarkedByUser проверяет, есть ли у пользователя закладка на пост.
+func (s *Storage) IsPostBookmarkedByUser(ctx context.Context, id int, login string) (bool, error) {
+	const fnIsPostBookmarkedByUser = "storage.sqlite.IsPostBookmarkedByUser"
+	ctx, end := s.begin(ctx, fnIsPostBookmarkedByUser)
+	defer end()
+
+	q := `SELECT COUNT(*) FROM bookmarks WHERE post_id = ? AND login = ?`
+
+	var count int
+
+	if err := s.db.QueryRowContext(ctx, q, id, login).Scan(&count); err != nil {
+		return false, fmt.Errorf("%s: failed to check if post is bookmarked: %w", fnIsPostBookmarkedByUser, err)
+	}
+
+	return count > 0, nil
+}
+
+// BookmarkPost сохраняет закладку пользователя на пост.
+// Если закладка уже есть, она переносится в указанную коллекцию.
+func (s *Storage) BookmarkPost(ctx context.Context, id int, login string, collection string, date_created string) error {
+	const fnBookmarkPost = "storage.sqlite.BookmarkPost"
+	ctx, end := s.begin(ctx, fnBookmarkPost)
+	defer end()
+
+	q := `
+		INSERT INTO bookmarks(post_id, login, collection, date_created) VALUES(?, ?, ?, ?)
+		ON CONFLICT(login, post_id) DO UPDATE SET collection = excluded.collection`
+
+	_, err := s.db.ExecContext(ctx, q, id, login, collection, date_created)
+	if err != nil {
+		return fmt.Errorf("%s: failed to save %s's bookmark: %w", fnBookmarkPost, login, err)
+	}
+
+	return nil
+}
+
+// UnbookmarkPost удаляет закладку пользователя на пост.
+func (s *Storage) UnbookmarkPost(ctx context.Context, id int, login string) error {
+	const fnUnbookmarkPost = "storage.sqlite.UnbookmarkPost"
+	ctx, end := s.begin(ctx, fnUnbookmarkPost)
+	defer end()
+
+	q := `DELETE FROM bookmarks WHERE post_id = ? AND login = ?`
+
+	_, err := s.db.ExecContext(ctx, q, id, login)
+	if err != nil {
+		return fmt.Errorf("%s: failed to delete %s's bookmark: %w", fnUnbookmarkPost, login, err)
+	}
+
+	return nil
+}
+
+// GetBookmarks получает посты из закладок пользователя, начиная с последних добавленных.
+// Пустая collection означает все закладки. Посты, которые стали скрыты от пользователя, пропускаются.
+func (s *Storage) GetBookmarks(ctx context.Context, login string, collection string, limit int, offset int) ([]types.Post, error) {
+	const fnGetBookmarks = "storage.sqlite.GetBookmarks"
+	ctx, end := s.begin(ctx, fnGetBookmarks)
+	defer end()
+
+	q := `
+		SELECT ` + postColumns + ` FROM bookmarks
+		JOIN posts ON posts.id = bookmarks.post_id
+		WHERE bookmarks.login = @viewer AND (@collection = '' OR bookmarks.collection = @collection) AND ` + visibleTo + `
+		ORDER BY bookmarks.date_created DESC, bookmarks.id DESC
+		LIMIT @limit OFFSET @offset`
+
+	rows, err := s.db.QueryContext(ctx, q, sql.Named("viewer", login), sql.Named("collection", collection),
+		sql.Named("limit", limit), sql.Named("offset", offset))
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get %s's bookmarks: %w", fnGetBookmarks, login, err)
+	}
+	defer rows.Close()
+
+	posts := make([]types.Post, 0, limit)
+	for rows.Next() {
+		var post types.Post
+		if err := scanPost(rows, &post); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan %s's bookmarks: %w", fnGetBookmarks, login, err)
+		}
+
+		posts = append(posts, post)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: failed to get %s's bookmarks: %w", fnGetBookmarks, login, err)
+	}
+	rows.Close()
+
+	for i := range posts {
+		if err := s.fillPost(ctx, &posts[i], login); err != nil {
+			return nil, fmt.Errorf("%s: %w", fnGetBookmarks, err)
+		}
+
+		if err := s.fillRepost(ctx, &posts[i], login); err != nil {
+			return nil, fmt.Errorf("%s: %w", fnGetBookmarks, err)
+		}
+	}
+
+	return posts, nil
+}
+
+// GetBookmarkCollections получает коллекции закладок пользователя и количество закладок в каждой.
+// Закладки без коллекции попадают в коллекцию с пустым именем.
+func (s *Storage) GetBookmarkCollections(ctx context.Context, login string) ([]types.BookmarkCollection, error) {
+	const fnGetBookmarkCollections = "storage.sqlite.GetBookmarkCollections"
+	ctx, end := s.begin(ctx, fnGetBookmarkCollections)
+	defer end()
+
+	q := `
+		SELECT collection, COUNT(*) FROM bookmarks
+		WHERE login = ?
+		GROUP BY collection
+		ORDER BY collection`
+
+	rows, err := s.db.QueryContext(ctx, q, login)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get %s's collections: %w", fnGetBookmarkCollections, login, err)
+	}
+	defer rows.Close()
+
+	collections := make([]types.BookmarkCollection, 0)
+	for rows.Next() {
+		var c types.BookmarkCollection
+		if err := rows.Scan(&c.Name, &c.Count); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan %s's collections: %w", fnGetBookmarkCollections, login, err)
+		}
+		collections = append(collections, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: failed to get %s's collections: %w", fnGetBookmarkCollections, login, err)
+	}
+
+	return collections, nil
+}
@@ -0,0 +1,340 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+// CreateConversation создает переписку created_by с members.
+func (s *Storage) CreateConversation(ctx context.Context, created_by string, members []string, date_created string) (int64, error) {
+	const fnCreateConversation = "storage.sqlite.CreateConversation"
+	ctx, end := s.begin(ctx, fnCreateConversation)
+	defer end()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to begin transaction: %w", fnCreateConversation, err)
+	}
+	defer tx.Rollback()
+
+	q := `INSERT INTO conversations(created_by, date_created, date_updated) VALUES(?, ?, ?)`
+
+	res, err := tx.ExecContext(ctx, q, created_by, date_created, date_created)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to save %s's conversation: %w", fnCreateConversation, created_by, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to get id of %s's conversation: %w", fnCreateConversation, created_by, err)
+	}
+
+	q = `INSERT INTO conversation_members(conversation_id, login) VALUES(?, ?)`
+
+	for _, login := range append([]string{created_by}, members...) {
+		if _, err := tx.ExecContext(ctx, q, id, login); err != nil {
+			return 0, fmt.Errorf("%s: failed to add %s to conversation %d: %w", fnCreateConversation, login, id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: failed to commit transaction: %w", fnCreateConversation, err)
+	}
+
+	return id, nil
+}
+
+// FindDirectConversation возвращает id переписки, в которой участвуют только first и second.
+// Если такой переписки нет, возвращает storage.ErrConversationNotFound.
+func (s *Storage) FindDirectConversation(ctx context.Context, first string, second string) (int64, error) {
+	const fnFindDirectConversation = "storage.sqlite.FindDirectConversation"
+	ctx, end := s.begin(ctx, fnFindDirectConversation)
+	defer end()
+
+	q := `
+		SELECT a.conversation_id FROM conversation_members a
+		JOIN conversation_members b ON b.conversation_id = a.conversation_id AND b.login = @second
+		WHERE a.login = @first
+		AND (SELECT COUNT(*) FROM conversation_members m WHERE m.conversation_id = a.conversation_id) = 2
+		LIMIT 1`
+
+	var id int64
+
+	err := s.db.QueryRowContext(ctx, q, sql.Named("first", first), sql.Named("second", second)).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, storage.ErrConversationNotFound
+	} else if err != nil {
+		return 0, fmt.Errorf("%s: failed to find conversation of %s and %s: %w", fnFindDirectConversation, first, second, err)
+	}
+
+	return id, nil
+}
+
+// IsConversationMember проверяет, участвует ли пользователь в переписке.
+func (s *Storage) IsConversationMember(ctx context.Context, id int64, login string) (bool, error) {
+	const fnIsConversationMember = "storage.sqlite.IsConversationMember"
+	ctx, end := s.begin(ctx, fnIsConversationMember)
+	defer end()
+
+	q := `SELECT COUNT(*) FROM conversation_members WHERE conversation_id = ? AND login = ?`
+
+	var count int
+
+	if err := s.db.QueryRowContext(ctx, q, id, login).Scan(&count); err != nil {
+		return false, fmt.Errorf("%s: failed to check if %s is in conversation %d: %w", fnIsConversationMember, login, id, err)
+	}
+
+	return count > 0, nil
+}
+
+// HasBlockedMember проверяет, есть ли в переписке участник, с которым у login есть блокировка.
+func (s *Storage) HasBlockedMember(ctx context.Context, id int64, login string) (bool, error) {
+	const fnHasBlockedMember = "storage.sqlite.HasBlockedMember"
+	ctx, end := s.begin(ctx, fnHasBlockedMember)
+	defer end()
+
+	q := `
+		SELECT EXISTS (SELECT 1 FROM conversation_members JOIN blocks ON
+			(blocks.blocker = conversation_members.login AND blocks.blocked = @login)
+			OR (blocks.blocker = @login AND blocks.blocked = conversation_members.login)
+		WHERE conversation_members.conversation_id = @id)`
+
+	var blocked bool
+
+	err := s.db.QueryRowContext(ctx, q, sql.Named("id", id), sql.Named("login", login)).Scan(&blocked)
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to check blocks of %s in conversation %d: %w", fnHasBlockedMember, login, id, err)
+	}
+
+	return blocked, nil
+}
+
+// GetConversation получает переписку с участниками, последним сообщением
+// и количеством непрочитанных viewer сообщений.
+func (s *Storage) GetConversation(ctx context.Context, id int64, viewer string) (*types.Conversation, error) {
+	const fnGetConversation = "storage.sqlite.GetConversation"
+	ctx, end := s.begin(ctx, fnGetConversation)
+	defer end()
+
+	q := `SELECT id, created_by, date_created, date_updated FROM conversations WHERE id = ?`
+
+	var c types.Conversation
+
+	err := s.db.QueryRowContext(ctx, q, id).Scan(&c.ID, &c.Created_by, &c.Created_at, &c.Updated_at)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrConversationNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: failed to get conversation %d: %w", fnGetConversation, id, err)
+	}
+
+	if err := s.fillConversation(ctx, &c, viewer); err != nil {
+		return nil, fmt.Errorf("%s: %w", fnGetConversation, err)
+	}
+
+	return &c, nil
+}
+
+// GetConversations получает переписки пользователя, начиная с последних обновленных.
+func (s *Storage) GetConversations(ctx context.Context, login string, limit int, offset int) ([]types.Conversation, error) {
+	const fnGetConversations = "storage.sqlite.GetConversations"
+	ctx, end := s.begin(ctx, fnGetConversations)
+	defer end()
+
+	q := `
+		SELECT c.id, c.created_by, c.date_created, c.date_updated FROM conversation_members m
+		JOIN conversations c ON c.id = m.conversation_id
+		WHERE m.login = ?
+		ORDER BY c.date_updated DESC, c.id DESC
+		LIMIT ? OFFSET ?`
+
+	rows, err := s.db.QueryContext(ctx, q, login, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get %s's conversations: %w", fnGetConversations, login, err)
+	}
+	defer rows.Close()
+
+	conversations := make([]types.Conversation, 0, limit)
+	for rows.Next() {
+		var c types.Conversation
+		if err := rows.Scan(&c.ID, &c.Created_by, &c.Created_at, &c.Updated_at); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan %s's conversations: %w", fnGetConversations, login, err)
+		}
+
+		conversations = append(conversations, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: failed to get %s's conversations: %w", fnGetConversations, login, err)
+	}
+	rows.Close()
+
+	for i := range conversations {
+		if err := s.fillConversation(ctx, &conversations[i], login); err != nil {
+			return nil, fmt.Errorf("%s: %w", fnGetConversations, err)
+		}
+	}
+
+	return conversations, nil
+}
+
+// SaveMessage сохраняет сообщение в переписке. Свое сообщение отправитель сразу считает прочитанным.
+func (s *Storage) SaveMessage(ctx context.Context, id int64, sender string, text string, date_created string) (int64, error) {
+	const fnSaveMessage = "storage.sqlite.SaveMessage"
+	ctx, end := s.begin(ctx, fnSaveMessage)
+	defer end()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to begin transaction: %w", fnSaveMessage, err)
+	}
+	defer tx.Rollback()
+
+	q := `INSERT INTO messages(conversation_id, sender, text, date_created) VALUES(?, ?, ?, ?)`
+
+	res, err := tx.ExecContext(ctx, q, id, sender, text, date_created)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to save %s's message: %w", fnSaveMessage, sender, err)
+	}
+
+	messageID, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to get id of %s's message: %w", fnSaveMessage, sender, err)
+	}
+
+	q = `UPDATE conversations SET date_updated = ? WHERE id = ?`
+	if _, err := tx.ExecContext(ctx, q, date_created, id); err != nil {
+		return 0, fmt.Errorf("%s: failed to update conversation %d: %w", fnSaveMessage, id, err)
+	}
+
+	q = `UPDATE conversation_members SET last_read_id = ? WHERE conversation_id = ? AND login = ?`
+	if _, err := tx.ExecContext(ctx, q, messageID, id, sender); err != nil {
+		return 0, fmt.Errorf("%s: failed to mark conversation %d as read: %w", fnSaveMessage, id, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: failed to commit transaction: %w", fnSaveMessage, err)
+	}
+
+	return messageID, nil
+}
+
+// GetMessages получает сообщения переписки, начиная с последних.
+func (s *Storage) GetMessages(ctx context.Context, id int64, limit int, offset int) ([]types.Message, error) {
+	const fnGetMessages = "storage.sqlite.GetMessages"
+	ctx, end := s.begin(ctx, fnGetMessages)
+	defer end()
+
+	q := `
+		SELECT id, conversation_id, sender, text, date_created FROM messages
+		WHERE conversation_id = ?
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?`
+
+	rows, err := s.db.QueryContext(ctx, q, id, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get messages of conversation %d: %w", fnGetMessages, id, err)
+	}
+	defer rows.Close()
+
+	messages := make([]types.Message, 0, limit)
+	for rows.Next() {
+		var m types.Message
+		if err := rows.Scan(&m.ID, &m.ConversationID, &m.Sender, &m.Text, &m.Created_at); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan messages of conversation %d: %w", fnGetMessages, id, err)
+		}
+
+		messages = append(messages, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: failed to get messages of conversation %d: %w", fnGetMessages, id, err)
+	}
+
+	return messages, nil
+}
+
+// ReadConversation отмечает прочитанными сообщения переписки до messageID включительно,
+// а если messageID равен 0 - все сообщения. Отметка о прочтении никогда не сдвигается назад.
+// Возвращает id последнего прочитанного сообщения.
+func (s *Storage) ReadConversation(ctx context.Context, id int64, login string, messageID int64) (int64, error) {
+	const fnReadConversation = "storage.sqlite.ReadConversation"
+	ctx, end := s.begin(ctx, fnReadConversation)
+	defer end()
+
+	q := `
+		UPDATE conversation_members SET last_read_id = MAX(last_read_id, (
+			SELECT COALESCE(MAX(id), 0) FROM messages
+			WHERE conversation_id = @id AND (@message_id = 0 OR id <= @message_id)))
+		WHERE conversation_id = @id AND login = @login
+		RETURNING last_read_id`
+
+	var lastReadID int64
+
+	err := s.db.QueryRowContext(ctx, q, sql.Named("id", id), sql.Named("login", login), sql.Named("message_id", messageID)).Scan(&lastReadID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, storage.ErrConversationNotFound
+	} else if err != nil {
+		return 0, fmt.Errorf("%s: failed to mark conversation %d as read: %w", fnReadConversation, id, err)
+	}
+
+	return lastReadID, nil
+}
+
+// fillConversation добавляет к переписке участников, последнее сообщение
+// и количество непрочитанных viewer сообщений.
+func (s *Storage) fillConversation(ctx context.Context, c *types.Conversation, viewer string) error {
+	const fnFillConversation = "storage.sqlite.fillConversation"
+
+	q := `SELECT login, last_read_id FROM conversation_members WHERE conversation_id = ? ORDER BY login`
+
+	rows, err := s.db.QueryContext(ctx, q, c.ID)
+	if err != nil {
+		return fmt.Errorf("%s: failed to get members of conversation %d: %w", fnFillConversation, c.ID, err)
+	}
+	defer rows.Close()
+
+	c.Members = []types.ConversationMember{}
+	for rows.Next() {
+		var m types.ConversationMember
+		if err := rows.Scan(&m.Login, &m.LastReadID); err != nil {
+			return fmt.Errorf("%s: failed to scan members of conversation %d: %w", fnFillConversation, c.ID, err)
+		}
+
+		c.Members = append(c.Members, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%s: failed to get members of conversation %d: %w", fnFillConversation, c.ID, err)
+	}
+	rows.Close()
+
+	q = `SELECT id, conversation_id, sender, text, date_created FROM messages WHERE conversation_id = ? ORDER BY id DESC LIMIT 1`
+
+	var m types.Message
+
+	err = s.db.QueryRowContext(ctx, q, c.ID).Scan(&m.ID, &m.ConversationID, &m.Sender, &m.Text, &m.Created_at)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s: failed to get last message of conversation %d: %w", fnFillConversation, c.ID, err)
+	}
+
+	if err == nil {
+		c.LastMessage = &m
+	}
+
+	q = `
+		SELECT COUNT(*) FROM messages
+		WHERE conversation_id = @id AND sender != @viewer AND id > (
+			SELECT last_read_id FROM conversation_members WHERE conversation_id = @id AND login = @viewer)`
+
+	err = s.db.QueryRowContext(ctx, q, sql.Named("id", c.ID), sql.Named("viewer", viewer)).Scan(&c.Unread)
+	if err != nil {
+		return fmt.Errorf("%s: failed to count unread messages of conversation %d: %w", fnFillConversation, c.ID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+)
+
+// IsFollowing проверяет, подписан ли follower на followee.
+func (s *Storage) IsFollowing(ctx context.Context, follower string, followee string) (bool, error) {
+	const fnIsFollowing = "storage.sqlite.IsFollowing"
+	ctx, end := s.begin(ctx, fnIsFollowing)
+	defer end()
+
+	q := `SELECT COUNT(*) FROM follows WHERE follower = ? AND followee = ?`
+
+	var count int
+
+	if err := s.db.QueryRowContext(ctx, q, follower, followee).Scan(&count); err != nil {
+		return false, fmt.Errorf("%s: failed to check if user follows %s: %w", fnIsFollowing, followee, err)
+	}
+
+	return count > 0, nil
+}
+
+// Follow подписывает follower на followee.
+func (s *Storage) Follow(ctx context.Context, follower string, followee string, date_created string) error {
+	const fnFollow = "storage.sqlite.Follow"
+	ctx, end := s.begin(ctx, fnFollow)
+	defer end()
+
+	q := `INSERT INTO follows(follower, followee, date_created) VALUES(?, ?, ?)`
+
+	_, err := s.db.ExecContext(ctx, q, follower, followee, date_created)
+	if err != nil {
+		return fmt.Errorf("%s: failed to save %s's follow: %w", fnFollow, follower, err)
+	}
+
+	return nil
+}
+
+// Unfollow отписывает follower от followee.
+func (s *Storage) Unfollow(ctx context.Context, follower string, followee string) error {
+	const fnUnfollow = "storage.sqlite.Unfollow"
+	ctx, end := s.begin(ctx, fnUnfollow)
+	defer end()
+
+	q := `DELETE FROM follows WHERE follower = ? AND followee = ?`
+
+	_, err := s.db.ExecContext(ctx, q, follower, followee)
+	if err != nil {
+		return fmt.Errorf("%s: failed to delete %s's follow: %w", fnUnfollow, follower, err)
+	}
+
+	return nil
+}
+
+// GetFollowers возвращает логины подписчиков пользователя login, кроме тех, кто его заглушил,
+// и тех, с кем у него есть блокировка.
+func (s *Storage) GetFollowers(ctx context.Context, login string) ([]string, error) {
+	const fnGetFollowers = "storage.sqlite.GetFollowers"
+	ctx, end := s.begin(ctx, fnGetFollowers)
+	defer end()
+
+	q := `
+		SELECT follower FROM follows
+		WHERE followee = ? AND NOT EXISTS (
+			SELECT 1 FROM mutes WHERE mutes.muter = follows.follower AND mutes.muted = follows.followee
+		) AND NOT EXISTS (
+			SELECT 1 FROM blocks
+			WHERE (blocks.blocker = follows.follower AND blocks.blocked = follows.followee)
+				OR (blocks.blocker = follows.followee AND blocks.blocked = follows.follower))`
+
+	rows, err := s.db.QueryContext(ctx, q, login)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get %s's followers: %w", fnGetFollowers, login, err)
+	}
+	defer rows.Close()
+
+	var followers []string
+	for rows.Next() {
+		var follower string
+		if err := rows.Scan(&follower); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan %s's followers: %w", fnGetFollowers, login, err)
+		}
+		followers = append(followers, follower)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: failed to get %s's followers: %w", fnGetFollowers, login, err)
+	}
+
+	return followers, nil
+}
@@ -0,0 +1,112 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+// ReserveIdempotencyKey сохраняет ключ пользователя с отпечатком запроса и возвращает true,
+// если ключа еще нет или он истек. Иначе возвращает уже сохраненную запись и false.
+// Из одновременных запросов с одним ключом ключ достается только одному.
+func (s *Storage) ReserveIdempotencyKey(ctx context.Context, login string, key string, fingerprint string, expires_at string, now string) (types.IdempotencyRecord, bool, error) {
+	const fnReserveIdempotencyKey = "storage.sqlite.ReserveIdempotencyKey"
+	ctx, end := s.begin(ctx, fnReserveIdempotencyKey)
+	defer end()
+
+	q := `
+		INSERT INTO idempotency_keys(login, key, fingerprint, expires_at)
+		VALUES(@login, @key, @fingerprint, @expires_at)
+		ON CONFLICT(login, key) DO UPDATE SET
+			fingerprint = excluded.fingerprint,
+			status = 0,
+			content_type = '',
+			body = NULL,
+			expires_at = excluded.expires_at
+		WHERE idempotency_keys.expires_at <= @now
+	`
+
+	res, err := s.db.ExecContext(ctx, q,
+		sql.Named("login", login),
+		sql.Named("key", key),
+		sql.Named("fingerprint", fingerprint),
+		sql.Named("expires_at", expires_at),
+		sql.Named("now", now),
+	)
+	if err != nil {
+		return types.IdempotencyRecord{}, false, fmt.Errorf("%s: failed to reserve key: %w", fnReserveIdempotencyKey, err)
+	}
+
+	reserved, err := res.RowsAffected()
+	if err != nil {
+		return types.IdempotencyRecord{}, false, fmt.Errorf("%s: failed to get affected rows: %w", fnReserveIdempotencyKey, err)
+	}
+
+	if reserved > 0 {
+		return types.IdempotencyRecord{Fingerprint: fingerprint}, true, nil
+	}
+
+	q = `SELECT fingerprint, status, content_type, body FROM idempotency_keys WHERE login = ? AND key = ?`
+
+	var record types.IdempotencyRecord
+
+	err = s.db.QueryRowContext(ctx, q, login, key).Scan(&record.Fingerprint, &record.Status, &record.ContentType, &record.Body)
+	if err != nil {
+		return types.IdempotencyRecord{}, false, fmt.Errorf("%s: failed to get key: %w", fnReserveIdempotencyKey, err)
+	}
+
+	return record, false, nil
+}
+
+// SaveIdempotentResponse сохраняет ответ на первый запрос с ключом, который хранится до expires_at.
+func (s *Storage) SaveIdempotentResponse(ctx context.Context, login string, key string, status int, content_type string, body []byte, expires_at string) error {
+	const fnSaveIdempotentResponse = "storage.sqlite.SaveIdempotentResponse"
+	ctx, end := s.begin(ctx, fnSaveIdempotentResponse)
+	defer end()
+
+	q := `UPDATE idempotency_keys SET status = ?, content_type = ?, body = ?, expires_at = ? WHERE login = ? AND key = ?`
+
+	if _, err := s.db.ExecContext(ctx, q, status, content_type, body, expires_at, login, key); err != nil {
+		return fmt.Errorf("%s: failed to save response: %w", fnSaveIdempotentResponse, err)
+	}
+
+	return nil
+}
+
+// RemoveIdempotencyKey удаляет ключ, чтобы запрос с ним можно было выполнить заново.
+func (s *Storage) RemoveIdempotencyKey(ctx context.Context, login string, key string) error {
+	const fnRemoveIdempotencyKey = "storage.sqlite.RemoveIdempotencyKey"
+	ctx, end := s.begin(ctx, fnRemoveIdempotencyKey)
+	defer end()
+
+	q := `DELETE FROM idempotency_keys WHERE login = ? AND key = ?`
+
+	if _, err := s.db.ExecContext(ctx, q, login, key); err != nil {
+		return fmt.Errorf("%s: failed to remove key: %w", fnRemoveIdempotencyKey, err)
+	}
+
+	return nil
+}
+
+// RemoveExpiredIdempotencyKeys удаляет истекшие ключи и возвращает их количество.
+func (s *Storage) RemoveExpiredIdempotencyKeys(ctx context.Context, now string) (int64, error) {
+	const fnRemoveExpiredIdempotencyKeys = "storage.sqlite.RemoveExpiredIdempotencyKeys"
+	ctx, end := s.begin(ctx, fnRemoveExpiredIdempotencyKeys)
+	defer end()
+
+	q := `DELETE FROM idempotency_keys WHERE expires_at <= ?`
+
+	res, err := s.db.ExecContext(ctx, q, now)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to remove expired keys: %w", fnRemoveExpiredIdempotencyKeys, err)
+	}
+
+	removed, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to get affected rows: %w", fnRemoveExpiredIdempotencyKeys, err)
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,150 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+// SaveMedia сохраняет информацию о загруженном файле. Сам файл хранится в BlobStore.
+func (s *Storage) SaveMedia(ctx context.Context, media types.Media, date_created string) (int64, error) {
+	const fnSaveMedia = "storage.sqlite.SaveMedia"
+	ctx, end := s.begin(ctx, fnSaveMedia)
+	defer end()
+
+	q := `
+		INSERT INTO media(owner, content_type, size, width, height, blob_key, thumbnail_key, thumbnail_type, date_created)
+		VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	data := []any{
+		media.Owner, media.ContentType, media.Size, media.Width, media.Height,
+		media.BlobKey, media.ThumbnailKey, media.ThumbnailType, date_created,
+	}
+
+	res, err := s.db.ExecContext(ctx, q, data...)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to save media: %w", fnSaveMedia, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to get last insert media's id: %w", fnSaveMedia, err)
+	}
+
+	return id, nil
+}
+
+// GetMedia получает информацию о загруженном файле.
+func (s *Storage) GetMedia(ctx context.Context, id int64) (*types.Media, error) {
+	const fnGetMedia = "storage.sqlite.GetMedia"
+	ctx, end := s.begin(ctx, fnGetMedia)
+	defer end()
+
+	q := `
+		SELECT id, owner, post_id, content_type, size, width, height, blob_key, thumbnail_key, thumbnail_type, date_created,
+		NOT ` + notAvatar + `
+		FROM media WHERE id = ?`
+
+	var m types.Media
+	var postID sql.NullInt64
+
+	err := s.db.QueryRowContext(ctx, q, id).Scan(
+		&m.ID, &m.Owner, &postID, &m.ContentType, &m.Size, &m.Width, &m.Height,
+		&m.BlobKey, &m.ThumbnailKey, &m.ThumbnailType, &m.Created_at, &m.Avatar,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrMediaNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: failed to get media: %w", fnGetMedia, err)
+	}
+	m.PostID = postID.Int64
+
+	return &m, nil
+}
+
+// notAvatar - условие, при котором загрузка media не является аватаром пользователя.
+const notAvatar = `NOT EXISTS (SELECT 1 FROM users WHERE users.avatar_id = media.id)`
+
+// GetOrphanMedia получает загрузки без поста, созданные раньше created_before. Аватары сиротами не считаются.
+func (s *Storage) GetOrphanMedia(ctx context.Context, created_before string) ([]types.Media, error) {
+	const fnGetOrphanMedia = "storage.sqlite.GetOrphanMedia"
+	ctx, end := s.begin(ctx, fnGetOrphanMedia)
+	defer end()
+
+	q := `
+		SELECT id, blob_key, thumbnail_key FROM media
+		WHERE post_id IS NULL AND date_created < ? AND ` + notAvatar + `
+		ORDER BY id`
+
+	rows, err := s.db.QueryContext(ctx, q, created_before)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get orphan media: %w", fnGetOrphanMedia, err)
+	}
+	defer rows.Close()
+
+	orphans := make([]types.Media, 0)
+	for rows.Next() {
+		var m types.Media
+		if err := rows.Scan(&m.ID, &m.BlobKey, &m.ThumbnailKey); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan orphan media: %w", fnGetOrphanMedia, err)
+		}
+		orphans = append(orphans, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: failed to get orphan media: %w", fnGetOrphanMedia, err)
+	}
+
+	return orphans, nil
+}
+
+// RemoveOrphanMedia удаляет загрузку, если она все еще не прикреплена к посту и не стала аватаром.
+// Возвращает true, если запись была удалена.
+func (s *Storage) RemoveOrphanMedia(ctx context.Context, id int64) (bool, error) {
+	const fnRemoveOrphanMedia = "storage.sqlite.RemoveOrphanMedia"
+	ctx, end := s.begin(ctx, fnRemoveOrphanMedia)
+	defer end()
+
+	q := `DELETE FROM media WHERE id = ? AND post_id IS NULL AND ` + notAvatar
+
+	res, err := s.db.ExecContext(ctx, q, id)
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to delete media: %w", fnRemoveOrphanMedia, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to delete media: %w", fnRemoveOrphanMedia, err)
+	}
+
+	return n > 0, nil
+}
+
+// getPostMedia получает загрузки, прикрепленные к посту.
+func (s *Storage) getPostMedia(ctx context.Context, postID int64) ([]types.Media, error) {
+	q := `
+		SELECT id, owner, content_type, size, width, height, date_created FROM media
+		WHERE post_id = ?
+		ORDER BY id`
+
+	rows, err := s.db.QueryContext(ctx, q, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post's media: %w", err)
+	}
+	defer rows.Close()
+
+	var media []types.Media
+	for rows.Next() {
+		var m types.Media
+		if err := rows.Scan(&m.ID, &m.Owner, &m.ContentType, &m.Size, &m.Width, &m.Height, &m.Created_at); err != nil {
+			return nil, fmt.Errorf("failed to scan post's media: %w", err)
+		}
+		media = append(media, m)
+	}
+
+	return media, rows.Err()
+}
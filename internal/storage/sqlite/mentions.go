@@ -0,0 +1,137 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+// SetPostMentions заменяет упоминания в посте на logins и возвращает пользователей,
+// которые упомянуты в посте впервые. Упоминания, которых больше нет в тексте, становятся неактивными.
+// Пользователи, между которыми и автором поста есть блокировка, не упоминаются.
+func (s *Storage) SetPostMentions(ctx context.Context, id int64, logins []string, date_created string) ([]string, error) {
+	const fnSetPostMentions = "storage.sqlite.SetPostMentions"
+	ctx, end := s.begin(ctx, fnSetPostMentions)
+	defer end()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to begin transaction: %w", fnSetPostMentions, err)
+	}
+	defer tx.Rollback()
+
+	q := `SELECT login FROM mentions WHERE post_id = ?`
+
+	rows, err := tx.QueryContext(ctx, q, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get post's mentions: %w", fnSetPostMentions, err)
+	}
+	defer rows.Close()
+
+	var known []string
+	for rows.Next() {
+		var login string
+		if err := rows.Scan(&login); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan post's mentions: %w", fnSetPostMentions, err)
+		}
+		known = append(known, login)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: failed to get post's mentions: %w", fnSetPostMentions, err)
+	}
+	rows.Close()
+
+	for _, login := range known {
+		q := `UPDATE mentions SET active = ? WHERE post_id = ? AND login = ?`
+
+		if _, err := tx.ExecContext(ctx, q, slices.Contains(logins, login), id, login); err != nil {
+			return nil, fmt.Errorf("%s: failed to update %s's mention: %w", fnSetPostMentions, login, err)
+		}
+	}
+
+	var added []string
+	for _, login := range logins {
+		if slices.Contains(known, login) {
+			continue
+		}
+
+		q := `
+			INSERT INTO mentions(post_id, login, date_created)
+			SELECT posts.id, @login, @date_created FROM posts
+			WHERE posts.id = @id AND NOT EXISTS (SELECT 1 FROM blocks WHERE
+				(blocks.blocker = @login AND blocks.blocked = posts.created_by)
+				OR (blocks.blocker = posts.created_by AND blocks.blocked = @login))`
+
+		res, err := tx.ExecContext(ctx, q, sql.Named("id", id), sql.Named("login", login), sql.Named("date_created", date_created))
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to save %s's mention: %w", fnSetPostMentions, login, err)
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to save %s's mention: %w", fnSetPostMentions, login, err)
+		}
+
+		if n > 0 {
+			added = append(added, login)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: failed to commit transaction: %w", fnSetPostMentions, err)
+	}
+
+	return added, nil
+}
+
+// GetMentions получает посты, в которых упомянут пользователь, начиная с последних упоминаний.
+// Посты, которые скрыты от пользователя, и посты заглушенных им пользователей пропускаются.
+func (s *Storage) GetMentions(ctx context.Context, login string, limit int, offset int) ([]types.Post, error) {
+	const fnGetMentions = "storage.sqlite.GetMentions"
+	ctx, end := s.begin(ctx, fnGetMentions)
+	defer end()
+
+	q := `
+		SELECT ` + postColumns + ` FROM mentions
+		JOIN posts ON posts.id = mentions.post_id
+		WHERE mentions.login = @viewer AND mentions.active AND ` + visibleTo + ` AND ` + notMuted + `
+		ORDER BY mentions.date_created DESC, mentions.id DESC
+		LIMIT @limit OFFSET @offset`
+
+	rows, err := s.db.QueryContext(ctx, q, sql.Named("viewer", login), sql.Named("limit", limit), sql.Named("offset", offset))
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get %s's mentions: %w", fnGetMentions, login, err)
+	}
+	defer rows.Close()
+
+	posts := make([]types.Post, 0, limit)
+	for rows.Next() {
+		var post types.Post
+		if err := scanPost(rows, &post); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan %s's mentions: %w", fnGetMentions, login, err)
+		}
+
+		posts = append(posts, post)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: failed to get %s's mentions: %w", fnGetMentions, login, err)
+	}
+	rows.Close()
+
+	for i := range posts {
+		if err := s.fillPost(ctx, &posts[i], login); err != nil {
+			return nil, fmt.Errorf("%s: %w", fnGetMentions, err)
+		}
+
+		if err := s.fillRepost(ctx, &posts[i], login); err != nil {
+			return nil, fmt.Errorf("%s: %w", fnGetMentions, err)
+		}
+	}
+
+	return posts, nil
+}
@@ -0,0 +1,309 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// migration описывает изменение схемы, которое применяется поверх таблиц, созданных в Init.
+type migration struct {
+	version int
+	query   string
+}
+
+// migrations содержит все изменения схемы в порядке их применения.
+// Уже примененные миграции менять нельзя - только добавлять новые в конец.
+// Внешние ключи в sqlite выключены, поэтому связи между таблицами не объявляются:
+// связанные строки удаляются вместе с постом в removePost.
+var migrations = []migration{
+	{
+		version: 1,
+		query: `
+			ALTER TABLE posts ADD COLUMN format VARCHAR(10) NOT NULL DEFAULT 'plain';
+			ALTER TABLE posts ADD COLUMN revision INTEGER NOT NULL DEFAULT 1;
+		`,
+	},
+	{
+		version: 2,
+		query: `
+			CREATE TABLE IF NOT EXISTS media(
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				owner VARCHAR(50) NOT NULL,
+				post_id INTEGER,
+				content_type VARCHAR(50) NOT NULL,
+				size INTEGER NOT NULL,
+				width INTEGER NOT NULL,
+				height INTEGER NOT NULL,
+				blob_key VARCHAR(128) NOT NULL,
+				thumbnail_key VARCHAR(128) NOT NULL,
+				thumbnail_type VARCHAR(50) NOT NULL,
+				date_created TIMESTAMP NOT NULL);
+
+			CREATE INDEX IF NOT EXISTS idx_media_post_id ON media(post_id);
+		`,
+	},
+	{
+		version: 3,
+		query: `
+			ALTER TABLE posts ADD COLUMN visibility VARCHAR(10) NOT NULL DEFAULT 'public';
+
+			CREATE TABLE IF NOT EXISTS follows(
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				follower VARCHAR(50) NOT NULL,
+				followee VARCHAR(50) NOT NULL,
+				date_created TIMESTAMP NOT NULL,
+				UNIQUE(follower, followee));
+
+			CREATE INDEX IF NOT EXISTS idx_follows_followee ON follows(followee);
+		`,
+	},
+	{
+		version: 4,
+		query: `
+			CREATE TABLE IF NOT EXISTS bookmarks(
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				post_id INTEGER NOT NULL,
+				login VARCHAR(50) NOT NULL,
+				collection VARCHAR(50) NOT NULL DEFAULT '',
+				date_created TIMESTAMP NOT NULL,
+				UNIQUE(login, post_id));
+
+			CREATE INDEX IF NOT EXISTS idx_bookmarks_post_id ON bookmarks(post_id);
+		`,
+	},
+	{
+		// существующие лайки становятся реакциями вида like
+		version: 5,
+		query: `
+			DELETE FROM reactions WHERE liked_by IS NULL OR liked_by = ''
+				OR id NOT IN (SELECT MIN(id) FROM reactions GROUP BY post_id, liked_by);
+
+			ALTER TABLE reactions RENAME COLUMN liked_by TO login;
+			ALTER TABLE reactions ADD COLUMN kind VARCHAR(20) NOT NULL DEFAULT 'like';
+
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_reactions_post_id_login_kind ON reactions(post_id, login, kind);
+
+			UPDATE posts SET likes = (
+				SELECT COUNT(*) FROM reactions WHERE reactions.post_id = posts.id AND reactions.kind = 'like');
+		`,
+	},
+	{
+		// репост без текста - простой репост, с текстом - цитата
+		version: 6,
+		query: `
+			ALTER TABLE posts ADD COLUMN repost_of INTEGER;
+
+			CREATE INDEX IF NOT EXISTS idx_posts_repost_of ON posts(repost_of);
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_posts_plain_repost ON posts(created_by, repost_of)
+				WHERE repost_of IS NOT NULL AND text = '';
+		`,
+	},
+	{
+		// упоминание, убранное при редактировании, не удаляется, а становится неактивным,
+		// чтобы при повторном упоминании пользователь не получил уведомление второй раз
+		version: 7,
+		query: `
+			CREATE TABLE IF NOT EXISTS mentions(
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				post_id INTEGER NOT NULL,
+				login VARCHAR(50) NOT NULL,
+				active BOOLEAN NOT NULL DEFAULT 1,
+				date_created TIMESTAMP NOT NULL,
+				UNIQUE(post_id, login));
+
+			CREATE INDEX IF NOT EXISTS idx_mentions_login ON mentions(login);
+		`,
+	},
+	{
+		// у уведомлений без поста (например, о подписке) post_id равен 0,
+		// чтобы они группировались вместе
+		version: 8,
+		query: `
+			CREATE TABLE IF NOT EXISTS notifications(
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				recipient VARCHAR(50) NOT NULL,
+				type VARCHAR(20) NOT NULL,
+				actor VARCHAR(50) NOT NULL,
+				post_id INTEGER NOT NULL DEFAULT 0,
+				read BOOLEAN NOT NULL DEFAULT 0,
+				date_created TIMESTAMP NOT NULL);
+
+			CREATE INDEX IF NOT EXISTS idx_notifications_recipient ON notifications(recipient, type, post_id);
+
+			CREATE TABLE IF NOT EXISTS notification_prefs(
+				login VARCHAR(50) NOT NULL,
+				type VARCHAR(20) NOT NULL,
+				enabled BOOLEAN NOT NULL,
+				PRIMARY KEY(login, type));
+		`,
+	},
+	{
+		version: 9,
+		query: `
+			CREATE TABLE IF NOT EXISTS conversations(
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				created_by VARCHAR(50) NOT NULL,
+				date_created TIMESTAMP NOT NULL,
+				date_updated TIMESTAMP NOT NULL);
+
+			CREATE TABLE IF NOT EXISTS conversation_members(
+				conversation_id INTEGER NOT NULL,
+				login VARCHAR(50) NOT NULL,
+				last_read_id INTEGER NOT NULL DEFAULT 0,
+				PRIMARY KEY(conversation_id, login));
+
+			CREATE INDEX IF NOT EXISTS idx_conversation_members_login ON conversation_members(login, conversation_id);
+
+			CREATE TABLE IF NOT EXISTS messages(
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				conversation_id INTEGER NOT NULL,
+				sender VARCHAR(50) NOT NULL,
+				text TEXT NOT NULL,
+				date_created TIMESTAMP NOT NULL);
+
+			CREATE INDEX IF NOT EXISTS idx_messages_conversation ON messages(conversation_id, id);
+			CREATE INDEX IF NOT EXISTS idx_conversations_updated ON conversations(date_updated, id);
+		`,
+	},
+	{
+		version: 10,
+		query: `
+			ALTER TABLE users ADD COLUMN display_name VARCHAR(50) NOT NULL DEFAULT '';
+			ALTER TABLE users ADD COLUMN bio VARCHAR(160) NOT NULL DEFAULT '';
+			ALTER TABLE users ADD COLUMN website VARCHAR(100) NOT NULL DEFAULT '';
+			ALTER TABLE users ADD COLUMN avatar_id INTEGER NOT NULL DEFAULT 0;
+		`,
+	},
+	{
+		version: 11,
+		query: `
+			CREATE TABLE IF NOT EXISTS blocks(
+				blocker VARCHAR(50) NOT NULL,
+				blocked VARCHAR(50) NOT NULL,
+				date_created TIMESTAMP NOT NULL,
+				PRIMARY KEY(blocker, blocked));
+			CREATE INDEX IF NOT EXISTS idx_blocks_blocked ON blocks(blocked, blocker);
+			CREATE TABLE IF NOT EXISTS mutes(
+				muter VARCHAR(50) NOT NULL,
+				muted VARCHAR(50) NOT NULL,
+				date_created TIMESTAMP NOT NULL,
+				PRIMARY KEY(muter, muted));
+		`,
+	},
+	{
+		version: 12,
+		query: `
+			ALTER TABLE posts ADD COLUMN hidden BOOLEAN NOT NULL DEFAULT FALSE;
+			ALTER TABLE users ADD COLUMN suspended BOOLEAN NOT NULL DEFAULT FALSE;
+			CREATE TABLE IF NOT EXISTS moderators(
+				login VARCHAR(50) PRIMARY KEY);
+			CREATE TABLE IF NOT EXISTS reports(
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				post_id INTEGER NOT NULL,
+				author VARCHAR(50) NOT NULL,
+				reporter VARCHAR(50) NOT NULL,
+				reason VARCHAR(20) NOT NULL,
+				note VARCHAR(500) NOT NULL DEFAULT '',
+				resolved BOOLEAN NOT NULL DEFAULT FALSE,
+				date_created TIMESTAMP NOT NULL,
+				UNIQUE(post_id, reporter));
+			CREATE INDEX IF NOT EXISTS idx_reports_open ON reports(resolved, post_id);
+			CREATE TABLE IF NOT EXISTS moderation_actions(
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				post_id INTEGER NOT NULL,
+				author VARCHAR(50) NOT NULL,
+				moderator VARCHAR(50) NOT NULL,
+				action VARCHAR(20) NOT NULL,
+				note VARCHAR(500) NOT NULL DEFAULT '',
+				reports INTEGER NOT NULL,
+				date_created TIMESTAMP NOT NULL);
+		`,
+	},
+	{
+		// ответ сохраняется, когда первый запрос выполнен, до этого status равен 0
+		version: 13,
+		query: `
+			CREATE TABLE IF NOT EXISTS idempotency_keys(
+				login VARCHAR(50) NOT NULL,
+				key VARCHAR(255) NOT NULL,
+				fingerprint VARCHAR(64) NOT NULL,
+				status INTEGER NOT NULL DEFAULT 0,
+				content_type VARCHAR(100) NOT NULL DEFAULT '',
+				body BLOB,
+				expires_at TIMESTAMP NOT NULL,
+				PRIMARY KEY(login, key));
+			CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
+		`,
+	},
+}
+
+// migrate применяет еще не примененные миграции, каждую в отдельной транзакции.
+func (s *Storage) migrate(ctx context.Context) error {
+	const fnMigrate = "storage.sqlite.migrate"
+
+	q := `
+		CREATE TABLE IF NOT EXISTS schema_migrations(
+			version INTEGER PRIMARY KEY,
+			date_applied TIMESTAMP NOT NULL);
+	`
+
+	if _, err := s.db.ExecContext(ctx, q); err != nil {
+		return fmt.Errorf("%s: failed to create migrations table: %w", fnMigrate, err)
+	}
+
+	var current int
+
+	q = `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`
+	if err := s.db.QueryRowContext(ctx, q).Scan(&current); err != nil {
+		return fmt.Errorf("%s: failed to get current schema version: %w", fnMigrate, err)
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("%s: failed to begin transaction: %w", fnMigrate, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, m.query); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("%s: failed to apply migration %d: %w", fnMigrate, m.version, err)
+		}
+
+		q := `INSERT INTO schema_migrations(version, date_applied) VALUES(?, ?)`
+		if _, err := tx.ExecContext(ctx, q, m.version, time.Now().Format("2006-01-02 15:04:05")); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("%s: failed to save migration %d: %w", fnMigrate, m.version, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("%s: failed to commit migration %d: %w", fnMigrate, m.version, err)
+		}
+	}
+
+	return nil
+}
+
+// CheckMigrations проверяет, что к БД применены все миграции.
+func (s *Storage) CheckMigrations(ctx context.Context) error {
+	const fnCheckMigrations = "storage.sqlite.CheckMigrations"
+	ctx, end := s.begin(ctx, fnCheckMigrations)
+	defer end()
+
+	var current int
+
+	q := `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`
+	if err := s.db.QueryRowContext(ctx, q).Scan(&current); err != nil {
+		return fmt.Errorf("%s: failed to get current schema version: %w", fnCheckMigrations, err)
+	}
+
+	if latest := migrations[len(migrations)-1].version; current < latest {
+		return fmt.Errorf("%s: schema version is %d, want %d", fnCheckMigrations, current, latest)
+	}
+
+	return nil
+}
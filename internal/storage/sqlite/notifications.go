@@ -0,0 +1,195 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+// SaveNotification сохраняет уведомление для пользователя recipient.
+func (s *Storage) SaveNotification(ctx context.Context, recipient string, notificationType string, actor string, postID int64, date_created string) error {
+	const fnSaveNotification = "storage.sqlite.SaveNotification"
+	ctx, end := s.begin(ctx, fnSaveNotification)
+	defer end()
+
+	q := `INSERT INTO notifications(recipient, type, actor, post_id, date_created) VALUES(?, ?, ?, ?, ?)`
+
+	_, err := s.db.ExecContext(ctx, q, recipient, notificationType, actor, postID, date_created)
+	if err != nil {
+		return fmt.Errorf("%s: failed to save %s's notification: %w", fnSaveNotification, recipient, err)
+	}
+
+	return nil
+}
+
+// GetNotifications получает уведомления пользователя, сгруппированные по типу и посту,
+// начиная с групп с самыми новыми уведомлениями. Группа прочитана, если прочитаны все ее уведомления.
+func (s *Storage) GetNotifications(ctx context.Context, login string, limit int, offset int) ([]types.Notification, error) {
+	const fnGetNotifications = "storage.sqlite.GetNotifications"
+	ctx, end := s.begin(ctx, fnGetNotifications)
+	defer end()
+
+	q := `
+		SELECT type, post_id,
+			(SELECT actor FROM notifications AS last
+				WHERE last.recipient = n.recipient AND last.type = n.type AND last.post_id = n.post_id
+				ORDER BY last.id DESC LIMIT 1),
+			COUNT(DISTINCT actor) - 1, MIN(read), MAX(date_created)
+		FROM notifications AS n
+		WHERE recipient = ?
+		GROUP BY recipient, type, post_id
+		ORDER BY MAX(id) DESC
+		LIMIT ? OFFSET ?`
+
+	rows, err := s.db.QueryContext(ctx, q, login, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get %s's notifications: %w", fnGetNotifications, login, err)
+	}
+	defer rows.Close()
+
+	notifications := make([]types.Notification, 0, limit)
+	for rows.Next() {
+		var n types.Notification
+		var updated string
+		if err := rows.Scan(&n.Type, &n.PostID, &n.Actor, &n.Others, &n.Read, &updated); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan %s's notifications: %w", fnGetNotifications, login, err)
+		}
+
+		// у результата MAX нет типа колонки, поэтому драйвер не разбирает дату сам
+		updatedAt, err := time.Parse("2006-01-02 15:04:05", updated)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to parse %s's notification date: %w", fnGetNotifications, login, err)
+		}
+		n.Updated_at = updatedAt.Format(time.RFC3339)
+		notifications = append(notifications, n)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: failed to get %s's notifications: %w", fnGetNotifications, login, err)
+	}
+
+	return notifications, nil
+}
+
+// CountUnreadNotifications получает количество групп уведомлений пользователя с непрочитанными уведомлениями.
+func (s *Storage) CountUnreadNotifications(ctx context.Context, login string) (int, error) {
+	const fnCountUnreadNotifications = "storage.sqlite.CountUnreadNotifications"
+	ctx, end := s.begin(ctx, fnCountUnreadNotifications)
+	defer end()
+
+	q := `SELECT COUNT(DISTINCT type || ':' || post_id) FROM notifications WHERE recipient = ? AND NOT read`
+
+	var count int
+
+	if err := s.db.QueryRowContext(ctx, q, login).Scan(&count); err != nil {
+		return 0, fmt.Errorf("%s: failed to count %s's unread notifications: %w", fnCountUnreadNotifications, login, err)
+	}
+
+	return count, nil
+}
+
+// ReadNotifications отмечает все уведомления пользователя прочитанными.
+func (s *Storage) ReadNotifications(ctx context.Context, login string) error {
+	const fnReadNotifications = "storage.sqlite.ReadNotifications"
+	ctx, end := s.begin(ctx, fnReadNotifications)
+	defer end()
+
+	q := `UPDATE notifications SET read = 1 WHERE recipient = ? AND NOT read`
+
+	_, err := s.db.ExecContext(ctx, q, login)
+	if err != nil {
+		return fmt.Errorf("%s: failed to read %s's notifications: %w", fnReadNotifications, login, err)
+	}
+
+	return nil
+}
+
+// IsNotificationEnabled проверяет, включены ли у пользователя уведомления этого типа.
+// По умолчанию все уведомления включены.
+func (s *Storage) IsNotificationEnabled(ctx context.Context, login string, notificationType string) (bool, error) {
+	const fnIsNotificationEnabled = "storage.sqlite.IsNotificationEnabled"
+	ctx, end := s.begin(ctx, fnIsNotificationEnabled)
+	defer end()
+
+	q := `SELECT enabled FROM notification_prefs WHERE login = ? AND type = ?`
+
+	var enabled bool
+
+	err := s.db.QueryRowContext(ctx, q, login, notificationType).Scan(&enabled)
+	if errors.Is(err, sql.ErrNoRows) {
+		return true, nil
+	} else if err != nil {
+		return false, fmt.Errorf("%s: failed to get %s's notification preferences: %w", fnIsNotificationEnabled, login, err)
+	}
+
+	return enabled, nil
+}
+
+// GetNotificationPrefs получает настройки уведомлений пользователя для всех типов уведомлений.
+func (s *Storage) GetNotificationPrefs(ctx context.Context, login string) (map[string]bool, error) {
+	const fnGetNotificationPrefs = "storage.sqlite.GetNotificationPrefs"
+	ctx, end := s.begin(ctx, fnGetNotificationPrefs)
+	defer end()
+
+	prefs := make(map[string]bool, len(types.NotificationTypes))
+	for _, t := range types.NotificationTypes {
+		prefs[t] = true
+	}
+
+	q := `SELECT type, enabled FROM notification_prefs WHERE login = ?`
+
+	rows, err := s.db.QueryContext(ctx, q, login)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get %s's notification preferences: %w", fnGetNotificationPrefs, login, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t string
+		var enabled bool
+		if err := rows.Scan(&t, &enabled); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan %s's notification preferences: %w", fnGetNotificationPrefs, login, err)
+		}
+		prefs[t] = enabled
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: failed to get %s's notification preferences: %w", fnGetNotificationPrefs, login, err)
+	}
+
+	return prefs, nil
+}
+
+// SetNotificationPrefs сохраняет настройки уведомлений пользователя.
+// Типы, которых нет в prefs, не меняются.
+func (s *Storage) SetNotificationPrefs(ctx context.Context, login string, prefs map[string]bool) error {
+	const fnSetNotificationPrefs = "storage.sqlite.SetNotificationPrefs"
+	ctx, end := s.begin(ctx, fnSetNotificationPrefs)
+	defer end()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", fnSetNotificationPrefs, err)
+	}
+	defer tx.Rollback()
+
+	q := `
+		INSERT INTO notification_prefs(login, type, enabled) VALUES(?, ?, ?)
+		ON CONFLICT(login, type) DO UPDATE SET enabled = excluded.enabled`
+
+	for t, enabled := range prefs {
+		if _, err := tx.ExecContext(ctx, q, login, t, enabled); err != nil {
+			return fmt.Errorf("%s: failed to save %s's notification preferences: %w", fnSetNotificationPrefs, login, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", fnSetNotificationPrefs, err)
+	}
+
+	return nil
+}
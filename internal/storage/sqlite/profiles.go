@@ -0,0 +1,125 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+// GetProfile получает профиль пользователя login глазами пользователя viewer.
+// Число постов и лайков считается только по постам, видимым viewer.
+func (s *Storage) GetProfile(ctx context.Context, login string, viewer string) (*types.Profile, error) {
+	const fnGetProfile = "storage.sqlite.GetProfile"
+	ctx, end := s.begin(ctx, fnGetProfile)
+	defer end()
+
+	q := `
+		SELECT users.login, users.display_name, users.bio, users.website, users.avatar_id, users.date_registered,
+		(SELECT COUNT(*) FROM follows WHERE follows.followee = users.login),
+		(SELECT COUNT(*) FROM follows WHERE follows.follower = users.login)
+		FROM users WHERE users.login = @login`
+
+	var profile types.Profile
+
+	err := s.db.QueryRowContext(ctx, q, sql.Named("login", login)).Scan(
+		&profile.Login, &profile.DisplayName, &profile.Bio, &profile.Website, &profile.AvatarID,
+		&profile.Joined_at, &profile.Followers, &profile.Following,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrUserNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: failed to get %s's profile: %w", fnGetProfile, login, err)
+	}
+
+	q = `
+		SELECT COUNT(*), COALESCE(SUM(posts.likes), 0) FROM posts
+		WHERE posts.created_by = @login AND ` + visibleTo + `
+		AND (posts.visibility != 'unlisted' OR posts.created_by = @viewer)`
+
+	err = s.db.QueryRowContext(ctx, q, sql.Named("login", login), sql.Named("viewer", viewer)).
+		Scan(&profile.Posts, &profile.Likes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to count %s's posts: %w", fnGetProfile, login, err)
+	}
+
+	return &profile, nil
+}
+
+// GetProfiles получает профили пользователей из logins без числа постов и лайков,
+// которое зависит от того, кто смотрит профиль. Несуществующих пользователей в ответе нет.
+func (s *Storage) GetProfiles(ctx context.Context, logins []string) (map[string]types.Profile, error) {
+	const fnGetProfiles = "storage.sqlite.GetProfiles"
+	ctx, end := s.begin(ctx, fnGetProfiles)
+	defer end()
+
+	q := `
+		SELECT users.login, users.display_name, users.bio, users.website, users.avatar_id, users.date_registered,
+		(SELECT COUNT(*) FROM follows WHERE follows.followee = users.login),
+		(SELECT COUNT(*) FROM follows WHERE follows.follower = users.login)
+		FROM users WHERE users.login IN (SELECT value FROM json_each(?))`
+
+	rows, err := s.db.QueryContext(ctx, q, jsonArray(logins))
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get profiles: %w", fnGetProfiles, err)
+	}
+	defer rows.Close()
+
+	profiles := make(map[string]types.Profile, len(logins))
+	for rows.Next() {
+		var profile types.Profile
+		err := rows.Scan(
+			&profile.Login, &profile.DisplayName, &profile.Bio, &profile.Website, &profile.AvatarID,
+			&profile.Joined_at, &profile.Followers, &profile.Following,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to scan profiles: %w", fnGetProfiles, err)
+		}
+
+		profiles[profile.Login] = profile
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: failed to get profiles: %w", fnGetProfiles, err)
+	}
+
+	return profiles, nil
+}
+
+// UpdateProfile обновляет профиль пользователя login. Аватаром может стать только
+// своя загрузка, не прикрепленная к посту; avatarID = 0 убирает аватар.
+func (s *Storage) UpdateProfile(ctx context.Context, login string, displayName string, bio string, website string, avatarID int64) error {
+	const fnUpdateProfile = "storage.sqlite.UpdateProfile"
+	ctx, end := s.begin(ctx, fnUpdateProfile)
+	defer end()
+
+	q := `
+		UPDATE users SET display_name = @display_name, bio = @bio, website = @website, avatar_id = @avatar
+		WHERE login = @login AND (@avatar = 0 OR EXISTS (
+			SELECT 1 FROM media WHERE media.id = @avatar AND media.owner = @login AND media.post_id IS NULL))`
+
+	res, err := s.db.ExecContext(ctx, q,
+		sql.Named("display_name", displayName),
+		sql.Named("bio", bio),
+		sql.Named("website", website),
+		sql.Named("avatar", avatarID),
+		sql.Named("login", login),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: failed to update %s's profile: %w", fnUpdateProfile, login, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: failed to update %s's profile: %w", fnUpdateProfile, login, err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%s: %w: %d", fnUpdateProfile, storage.ErrMediaNotAvailable, avatarID)
+	}
+
+	return nil
+}
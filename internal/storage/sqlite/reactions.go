@@ -0,0 +1,257 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+// IsPostReactedByUser проверяет, ставил ли пользователь на пост реакцию вида kind.
+func (s *Storage) IsPostReactedByUser(ctx context.Context, id int, login string, kind string) (bool, error) {
+	const fnIsPostReactedByUser = "storage.sqlite.IsPostReactedByUser"
+	ctx, end := s.begin(ctx, fnIsPostReactedByUser)
+	defer end()
+
+	q := `SELECT COUNT(*) FROM reactions WHERE post_id = ? AND login = ? AND kind = ?`
+
+	var count int
+
+	if err := s.db.QueryRowContext(ctx, q, id, login, kind).Scan(&count); err != nil {
+		return false, fmt.Errorf("%s: failed to check if post has reaction: %w", fnIsPostReactedByUser, err)
+	}
+
+	return count > 0, nil
+}
+
+// AddReaction сохраняет реакцию пользователя на пост.
+// Для лайков в той же транзакции увеличивается счетчик лайков поста.
+// Повторная реакция того же вида - storage.ErrAlreadyReacted.
+func (s *Storage) AddReaction(ctx context.Context, id int, login string, kind string) error {
+	const fnAddReaction = "storage.sqlite.AddReaction"
+	ctx, end := s.begin(ctx, fnAddReaction)
+	defer end()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", fnAddReaction, err)
+	}
+	defer tx.Rollback()
+
+	// одновременные одинаковые запросы проходят проверку в обработчике оба,
+	// поэтому повторная реакция отсекается уникальным индексом
+	q := `INSERT OR IGNORE INTO reactions (post_id, login, kind) VALUES(?, ?, ?)`
+
+	res, err := tx.ExecContext(ctx, q, id, login, kind)
+	if err != nil {
+		return fmt.Errorf("%s: failed to save %s's %s on post: %w", fnAddReaction, login, kind, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: failed to save %s's %s on post: %w", fnAddReaction, login, kind, err)
+	}
+
+	if n == 0 {
+		return storage.ErrAlreadyReacted
+	}
+
+	if kind == types.ReactionLike {
+		q = `UPDATE posts SET likes = likes + 1 WHERE id = ?`
+
+		_, err = tx.ExecContext(ctx, q, id)
+		if err != nil {
+			return fmt.Errorf("%s: failed to increase post's likes: %w", fnAddReaction, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", fnAddReaction, err)
+	}
+
+	return nil
+}
+
+// RemoveReaction удаляет реакцию пользователя на пост.
+// Для лайков в той же транзакции уменьшается счетчик лайков поста.
+func (s *Storage) RemoveReaction(ctx context.Context, id int, login string, kind string) error {
+	const fnRemoveReaction = "storage.sqlite.RemoveReaction"
+	ctx, end := s.begin(ctx, fnRemoveReaction)
+	defer end()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", fnRemoveReaction, err)
+	}
+	defer tx.Rollback()
+
+	q := `DELETE FROM reactions WHERE post_id = ? AND login = ? AND kind = ?`
+
+	res, err := tx.ExecContext(ctx, q, id, login, kind)
+	if err != nil {
+		return fmt.Errorf("%s: failed to delete %s's %s on post: %w", fnRemoveReaction, login, kind, err)
+	}
+
+	// счетчик уменьшаем, только если реакция действительно была удалена
+	removed, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: failed to delete %s's %s on post: %w", fnRemoveReaction, login, kind, err)
+	}
+
+	if kind == types.ReactionLike && removed > 0 {
+		q = `UPDATE posts SET likes = likes - 1 WHERE id = ?`
+
+		_, err = tx.ExecContext(ctx, q, id)
+		if err != nil {
+			return fmt.Errorf("%s: failed to decrease post's likes: %w", fnRemoveReaction, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", fnRemoveReaction, err)
+	}
+
+	return nil
+}
+
+// CountPostsReactions получает количество реакций каждого вида на каждый пост из ids.
+// Постов без реакций в ответе нет.
+func (s *Storage) CountPostsReactions(ctx context.Context, ids []int64) (map[int64]map[string]int, error) {
+	const fnCountPostsReactions = "storage.sqlite.CountPostsReactions"
+	ctx, end := s.begin(ctx, fnCountPostsReactions)
+	defer end()
+
+	q := `SELECT post_id, kind, COUNT(*) FROM reactions
+		WHERE post_id IN (SELECT value FROM json_each(?))
+		GROUP BY post_id, kind ORDER BY post_id, kind`
+
+	rows, err := s.db.QueryContext(ctx, q, jsonArray(ids))
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to count reactions: %w", fnCountPostsReactions, err)
+	}
+	defer rows.Close()
+
+	counts := make(map[int64]map[string]int)
+	for rows.Next() {
+		var postID int64
+		var kind string
+		var count int
+		if err := rows.Scan(&postID, &kind, &count); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan reactions: %w", fnCountPostsReactions, err)
+		}
+
+		if counts[postID] == nil {
+			counts[postID] = make(map[string]int)
+		}
+		counts[postID][kind] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: failed to count reactions: %w", fnCountPostsReactions, err)
+	}
+
+	return counts, nil
+}
+
+// ListPostsReactions получает для каждого поста из ids первые limit реакций вида kind
+// в порядке, в котором их ставили, начиная после реакции с id after_id.
+func (s *Storage) ListPostsReactions(ctx context.Context, ids []int64, kind string, after_id int64, limit int) (map[int64][]types.Reaction, error) {
+	const fnListPostsReactions = "storage.sqlite.ListPostsReactions"
+	ctx, end := s.begin(ctx, fnListPostsReactions)
+	defer end()
+
+	q := `
+		SELECT id, post_id, login, kind FROM (
+			SELECT id, post_id, login, kind, ROW_NUMBER() OVER (PARTITION BY post_id ORDER BY id) AS n
+			FROM reactions
+			WHERE post_id IN (SELECT value FROM json_each(@ids)) AND kind = @kind AND id > @after_id
+		)
+		WHERE n <= @limit
+		ORDER BY post_id, id`
+
+	rows, err := s.db.QueryContext(ctx, q,
+		sql.Named("ids", jsonArray(ids)),
+		sql.Named("kind", kind),
+		sql.Named("after_id", after_id),
+		sql.Named("limit", limit),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get reactions: %w", fnListPostsReactions, err)
+	}
+	defer rows.Close()
+
+	reactions := make(map[int64][]types.Reaction)
+	for rows.Next() {
+		var reaction types.Reaction
+		if err := rows.Scan(&reaction.ID, &reaction.PostID, &reaction.Login, &reaction.Kind); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan reactions: %w", fnListPostsReactions, err)
+		}
+
+		reactions[reaction.PostID] = append(reactions[reaction.PostID], reaction)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: failed to get reactions: %w", fnListPostsReactions, err)
+	}
+
+	return reactions, nil
+}
+
+// getPostReactions получает количество реакций каждого вида на пост.
+func (s *Storage) getPostReactions(ctx context.Context, postID int64) (map[string]int, error) {
+	q := `SELECT kind, COUNT(*) FROM reactions WHERE post_id = ? GROUP BY kind`
+
+	rows, err := s.db.QueryContext(ctx, q, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post's reactions: %w", err)
+	}
+	defer rows.Close()
+
+	var reactions map[string]int
+	for rows.Next() {
+		var kind string
+		var count int
+		if err := rows.Scan(&kind, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan post's reactions: %w", err)
+		}
+
+		if reactions == nil {
+			reactions = make(map[string]int)
+		}
+		reactions[kind] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get post's reactions: %w", err)
+	}
+
+	return reactions, nil
+}
+
+// getUserReactions получает виды реакций, которые пользователь поставил на пост.
+func (s *Storage) getUserReactions(ctx context.Context, postID int64, login string) ([]string, error) {
+	q := `SELECT kind FROM reactions WHERE post_id = ? AND login = ? ORDER BY id`
+
+	rows, err := s.db.QueryContext(ctx, q, postID, login)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user's reactions: %w", err)
+	}
+	defer rows.Close()
+
+	var kinds []string
+	for rows.Next() {
+		var kind string
+		if err := rows.Scan(&kind); err != nil {
+			return nil, fmt.Errorf("failed to scan user's reactions: %w", err)
+		}
+		kinds = append(kinds, kind)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get user's reactions: %w", err)
+	}
+
+	return kinds, nil
+}
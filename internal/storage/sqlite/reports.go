@@ -0,0 +1,315 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/solumD/go-blog-api/internal/types"
+)
+
+// isModerator - условие, при котором пользователь @viewer - модератор.
+const isModerator = `EXISTS (SELECT 1 FROM moderators WHERE moderators.login = @viewer)`
+
+// SetModerators заменяет список модераторов на logins.
+func (s *Storage) SetModerators(ctx context.Context, logins []string) error {
+	const fnSetModerators = "storage.sqlite.SetModerators"
+	ctx, end := s.begin(ctx, fnSetModerators)
+	defer end()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", fnSetModerators, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM moderators`); err != nil {
+		return fmt.Errorf("%s: failed to delete moderators: %w", fnSetModerators, err)
+	}
+
+	for _, login := range logins {
+		q := `INSERT OR IGNORE INTO moderators(login) VALUES(?)`
+
+		if _, err := tx.ExecContext(ctx, q, login); err != nil {
+			return fmt.Errorf("%s: failed to save moderator %s: %w", fnSetModerators, login, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", fnSetModerators, err)
+	}
+
+	return nil
+}
+
+// IsModerator проверяет, является ли пользователь модератором.
+func (s *Storage) IsModerator(ctx context.Context, login string) (bool, error) {
+	const fnIsModerator = "storage.sqlite.IsModerator"
+	ctx, end := s.begin(ctx, fnIsModerator)
+	defer end()
+
+	q := `SELECT ` + isModerator
+
+	var moderator bool
+
+	if err := s.db.QueryRowContext(ctx, q, sql.Named("viewer", login)).Scan(&moderator); err != nil {
+		return false, fmt.Errorf("%s: failed to check if %s is a moderator: %w", fnIsModerator, login, err)
+	}
+
+	return moderator, nil
+}
+
+// IsSuspended проверяет, заблокирован ли пользователь модератором.
+func (s *Storage) IsSuspended(ctx context.Context, login string) (bool, error) {
+	const fnIsSuspended = "storage.sqlite.IsSuspended"
+	ctx, end := s.begin(ctx, fnIsSuspended)
+	defer end()
+
+	q := `SELECT suspended FROM users WHERE login = ?`
+
+	var suspended bool
+
+	if err := s.db.QueryRowContext(ctx, q, login).Scan(&suspended); err != nil {
+		return false, fmt.Errorf("%s: failed to check if %s is suspended: %w", fnIsSuspended, login, err)
+	}
+
+	return suspended, nil
+}
+
+// ReportPost сохраняет жалобу пользователя reporter на пост. Один пользователь
+// может пожаловаться на пост только один раз, повторная жалоба - storage.ErrAlreadyReported.
+func (s *Storage) ReportPost(ctx context.Context, id int, reporter string, reason string, note string, date_created string) error {
+	const fnReportPost = "storage.sqlite.ReportPost"
+	ctx, end := s.begin(ctx, fnReportPost)
+	defer end()
+
+	q := `
+		INSERT OR IGNORE INTO reports(post_id, author, reporter, reason, note, date_created)
+		SELECT id, created_by, @reporter, @reason, @note, @date_created FROM posts WHERE id = @id`
+
+	res, err := s.db.ExecContext(ctx, q,
+		sql.Named("id", id),
+		sql.Named("reporter", reporter),
+		sql.Named("reason", reason),
+		sql.Named("note", note),
+		sql.Named("date_created", date_created),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: failed to save %s's report: %w", fnReportPost, reporter, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: failed to save %s's report: %w", fnReportPost, reporter, err)
+	}
+
+	if n == 0 {
+		return storage.ErrAlreadyReported
+	}
+
+	return nil
+}
+
+// GetReportGroups получает открытые жалобы, сгруппированные по постам:
+// сначала посты с наибольшим числом жалоб, при равенстве - с самой свежей жалобой.
+func (s *Storage) GetReportGroups(ctx context.Context, limit int, offset int) ([]types.ReportGroup, error) {
+	const fnGetReportGroups = "storage.sqlite.GetReportGroups"
+	ctx, end := s.begin(ctx, fnGetReportGroups)
+	defer end()
+
+	q := `
+		SELECT post_id, author, COUNT(*), MIN(date_created), MAX(date_created) FROM reports
+		WHERE NOT resolved
+		GROUP BY post_id, author
+		ORDER BY COUNT(*) DESC, MAX(date_created) DESC, post_id DESC
+		LIMIT ? OFFSET ?`
+
+	rows, err := s.db.QueryContext(ctx, q, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get reports: %w", fnGetReportGroups, err)
+	}
+	defer rows.Close()
+
+	groups := make([]types.ReportGroup, 0, limit)
+	for rows.Next() {
+		var g types.ReportGroup
+		var first, last string
+		if err := rows.Scan(&g.PostID, &g.Author, &g.Reports, &first, &last); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan reports: %w", fnGetReportGroups, err)
+		}
+
+		// у результата MIN и MAX нет типа колонки, поэтому драйвер не разбирает дату сам
+		firstReported, err := time.Parse("2006-01-02 15:04:05", first)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to parse report date: %w", fnGetReportGroups, err)
+		}
+
+		lastReported, err := time.Parse("2006-01-02 15:04:05", last)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to parse report date: %w", fnGetReportGroups, err)
+		}
+
+		g.First_reported = firstReported.Format(time.RFC3339)
+		g.Last_reported = lastReported.Format(time.RFC3339)
+		groups = append(groups, g)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: failed to get reports: %w", fnGetReportGroups, err)
+	}
+	rows.Close()
+
+	for i := range groups {
+		if err := s.fillReportGroup(ctx, &groups[i]); err != nil {
+			return nil, fmt.Errorf("%s: %w", fnGetReportGroups, err)
+		}
+	}
+
+	return groups, nil
+}
+
+// fillReportGroup заполняет причины и комментарии открытых жалоб на пост.
+func (s *Storage) fillReportGroup(ctx context.Context, g *types.ReportGroup) error {
+	q := `SELECT reason, note FROM reports WHERE post_id = ? AND NOT resolved ORDER BY id`
+
+	rows, err := s.db.QueryContext(ctx, q, g.PostID)
+	if err != nil {
+		return fmt.Errorf("failed to get reasons of reports on post %d: %w", g.PostID, err)
+	}
+	defer rows.Close()
+
+	g.Reasons = make(map[string]int)
+	for rows.Next() {
+		var reason, note string
+		if err := rows.Scan(&reason, &note); err != nil {
+			return fmt.Errorf("failed to scan reasons of reports on post %d: %w", g.PostID, err)
+		}
+
+		g.Reasons[reason]++
+		if note != "" {
+			g.Notes = append(g.Notes, note)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to get reasons of reports on post %d: %w", g.PostID, err)
+	}
+
+	return nil
+}
+
+// ResolveReports закрывает открытые жалобы на пост решением модератора и записывает это решение.
+// Если открытых жалоб нет - storage.ErrReportsNotFound. Возвращает автора поста.
+func (s *Storage) ResolveReports(ctx context.Context, id int64, moderator string, action string, note string, date_created string) (string, error) {
+	const fnResolveReports = "storage.sqlite.ResolveReports"
+	ctx, end := s.begin(ctx, fnResolveReports)
+	defer end()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("%s: failed to begin transaction: %w", fnResolveReports, err)
+	}
+	defer tx.Rollback()
+
+	q := `UPDATE reports SET resolved = TRUE WHERE post_id = ? AND NOT resolved RETURNING author`
+
+	rows, err := tx.QueryContext(ctx, q, id)
+	if err != nil {
+		return "", fmt.Errorf("%s: failed to resolve reports on post %d: %w", fnResolveReports, id, err)
+	}
+	defer rows.Close()
+
+	var author string
+	var count int
+	for rows.Next() {
+		if err := rows.Scan(&author); err != nil {
+			return "", fmt.Errorf("%s: failed to scan reports on post %d: %w", fnResolveReports, id, err)
+		}
+		count++
+	}
+
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("%s: failed to resolve reports on post %d: %w", fnResolveReports, id, err)
+	}
+	rows.Close()
+
+	if count == 0 {
+		return "", storage.ErrReportsNotFound
+	}
+
+	switch action {
+	case types.ModerationHide:
+		q = `UPDATE posts SET hidden = TRUE WHERE id = ?`
+
+		if _, err := tx.ExecContext(ctx, q, id); err != nil {
+			return "", fmt.Errorf("%s: failed to hide post %d: %w", fnResolveReports, id, err)
+		}
+	case types.ModerationDelete:
+		if err := removePost(ctx, tx, id); err != nil {
+			return "", fmt.Errorf("%s: %w", fnResolveReports, err)
+		}
+	case types.ModerationSuspend:
+		q = `UPDATE posts SET hidden = TRUE WHERE id = ?`
+
+		if _, err := tx.ExecContext(ctx, q, id); err != nil {
+			return "", fmt.Errorf("%s: failed to hide post %d: %w", fnResolveReports, id, err)
+		}
+
+		q = `UPDATE users SET suspended = TRUE WHERE login = ?`
+
+		if _, err := tx.ExecContext(ctx, q, author); err != nil {
+			return "", fmt.Errorf("%s: failed to suspend %s: %w", fnResolveReports, author, err)
+		}
+	}
+
+	q = `
+		INSERT INTO moderation_actions(post_id, author, moderator, action, note, reports, date_created)
+		VALUES(?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = tx.ExecContext(ctx, q, id, author, moderator, action, note, count, date_created)
+	if err != nil {
+		return "", fmt.Errorf("%s: failed to save moderation action: %w", fnResolveReports, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("%s: failed to commit transaction: %w", fnResolveReports, err)
+	}
+
+	return author, nil
+}
+
+// GetModerationActions получает решения модераторов, начиная с последних.
+func (s *Storage) GetModerationActions(ctx context.Context, limit int, offset int) ([]types.ModerationAction, error) {
+	const fnGetModerationActions = "storage.sqlite.GetModerationActions"
+	ctx, end := s.begin(ctx, fnGetModerationActions)
+	defer end()
+
+	q := `
+		SELECT id, post_id, author, moderator, action, note, reports, date_created FROM moderation_actions
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?`
+
+	rows, err := s.db.QueryContext(ctx, q, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get moderation actions: %w", fnGetModerationActions, err)
+	}
+	defer rows.Close()
+
+	actions := make([]types.ModerationAction, 0, limit)
+	for rows.Next() {
+		var a types.ModerationAction
+		if err := rows.Scan(&a.ID, &a.PostID, &a.Author, &a.Moderator, &a.Action, &a.Note, &a.Reports, &a.Created_at); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan moderation actions: %w", fnGetModerationActions, err)
+		}
+
+		actions = append(actions, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: failed to get moderation actions: %w", fnGetModerationActions, err)
+	}
+
+	return actions, nil
+}
@@ -0,0 +1,70 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/solumD/go-blog-api/internal/storage"
+)
+
+// Repost сохраняет репост поста. Если text пустой, это простой репост, иначе - цитата.
+func (s *Storage) Repost(ctx context.Context, id int, created_by string, text string, format string, visibility string, date_created string) (int64, error) {
+	const fnRepost = "storage.sqlite.Repost"
+	ctx, end := s.begin(ctx, fnRepost)
+	defer end()
+
+	q := `
+		INSERT INTO posts(created_by, title, text, format, visibility, repost_of, date_created, date_updated)
+		VALUES(?, '', ?, ?, ?, ?, ?, ?)`
+
+	res, err := s.db.ExecContext(ctx, q, created_by, text, format, visibility, id, date_created, date_created)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to save %s's repost: %w", fnRepost, created_by, err)
+	}
+
+	repostID, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to get last insert repost's id: %w", fnRepost, err)
+	}
+
+	return repostID, nil
+}
+
+// GetRepostID получает id простого репоста поста пользователем.
+// Если пользователь не делал простой репост, возвращает storage.ErrRepostNotFound.
+func (s *Storage) GetRepostID(ctx context.Context, id int, created_by string) (int, error) {
+	const fnGetRepostID = "storage.sqlite.GetRepostID"
+	ctx, end := s.begin(ctx, fnGetRepostID)
+	defer end()
+
+	q := `SELECT id FROM posts WHERE repost_of = ? AND created_by = ? AND text = ''`
+
+	var repostID int
+
+	err := s.db.QueryRowContext(ctx, q, id, created_by).Scan(&repostID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, storage.ErrRepostNotFound
+	} else if err != nil {
+		return 0, fmt.Errorf("%s: failed to get %s's repost: %w", fnGetRepostID, created_by, err)
+	}
+
+	return repostID, nil
+}
+
+// IsPostRepostedByUser проверяет, делал ли пользователь простой репост поста.
+func (s *Storage) IsPostRepostedByUser(ctx context.Context, id int, created_by string) (bool, error) {
+	const fnIsPostRepostedByUser = "storage.sqlite.IsPostRepostedByUser"
+	ctx, end := s.begin(ctx, fnIsPostRepostedByUser)
+	defer end()
+
+	_, err := s.GetRepostID(ctx, id, created_by)
+	if errors.Is(err, storage.ErrRepostNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("%s: %w", fnIsPostRepostedByUser, err)
+	}
+
+	return true, nil
+}
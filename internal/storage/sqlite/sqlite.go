@@ -3,16 +3,37 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/solumD/go-blog-api/internal/storage"
 	"github.com/solumD/go-blog-api/internal/types"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 type Storage struct {
-	db *sql.DB
+	db       *sql.DB
+	observer QueryObserver
+	tracer   trace.Tracer
 }
 
+// QueryObserver получает длительность каждого вызова метода хранилища.
+type QueryObserver interface {
+	ObserveQuery(method string, d time.Duration)
+}
+
+const tracerName = "github.com/solumD/go-blog-api/internal/storage/sqlite"
+
+type noopObserver struct{}
+
+func (noopObserver) ObserveQuery(string, time.Duration) {}
+
 // New создает новое sqlite хранилище.
 func New(path string) (*Storage, error) {
 	const fnNew = "storage.sqlite.New"
@@ -27,12 +48,71 @@ func New(path string) (*Storage, error) {
 		return nil, fmt.Errorf("%s: %w", fnNew, err)
 	}
 
-	return &Storage{db: db}, nil
+	return &Storage{db: db, observer: noopObserver{}, tracer: noop.NewTracerProvider().Tracer(tracerName)}, nil
+}
+
+// SetQueryObserver задает, куда отправлять длительность вызовов методов.
+// Вызывается до начала работы с хранилищем.
+func (s *Storage) SetQueryObserver(observer QueryObserver) {
+	s.observer = observer
+}
+
+// SetTracerProvider задает, кому отправлять спаны вызовов методов.
+// Вызывается до начала работы с хранилищем.
+func (s *Storage) SetTracerProvider(tracerProvider trace.TracerProvider) {
+	s.tracer = tracerProvider.Tracer(tracerName)
+}
+
+// begin открывает дочерний спан для вызова метода fn. Возвращенная функция
+// закрывает спан и отправляет длительность вызова.
+func (s *Storage) begin(ctx context.Context, fn string) (context.Context, func()) {
+	start := time.Now()
+
+	ctx, span := s.tracer.Start(ctx, fn,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemSqlite),
+	)
+
+	return ctx, func() {
+		span.End()
+		s.observer.ObserveQuery(strings.TrimPrefix(fn, "storage.sqlite."), time.Since(start))
+	}
+}
+
+// Stats возвращает статистику пула соединений с БД.
+func (s *Storage) Stats() sql.DBStats {
+	return s.db.Stats()
+}
+
+// Close закрывает соединение с БД.
+func (s *Storage) Close() error {
+	const fnClose = "storage.sqlite.Close"
+
+	if err := s.db.Close(); err != nil {
+		return fmt.Errorf("%s: %w", fnClose, err)
+	}
+
+	return nil
+}
+
+// Ping проверяет соединение с БД.
+func (s *Storage) Ping(ctx context.Context) error {
+	const fnPing = "storage.sqlite.Ping"
+	ctx, end := s.begin(ctx, fnPing)
+	defer end()
+
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("%s: %w", fnPing, err)
+	}
+
+	return nil
 }
 
 // IsUserExist проверяет, есть ли в БД пользователь с указанным логином.
 func (s *Storage) IsUserExist(ctx context.Context, login string) (bool, error) {
 	const fnIsUserExist = "storage.sqlite.IsUserExist"
+	ctx, end := s.begin(ctx, fnIsUserExist)
+	defer end()
 
 	q := `SELECT COUNT(*) FROM users WHERE login = ?`
 
@@ -47,7 +127,9 @@ func (s *Storage) IsUserExist(ctx context.Context, login string) (bool, error) {
 
 // GetPassword получает захэшированный пароль пользователя.
 func (s *Storage) GetPassword(ctx context.Context, login string) (string, error) {
-	const fnGetPassword = "storage.sqlite.GetUserPassword"
+	const fnGetPassword = "storage.sqlite.GetPassword"
+	ctx, end := s.begin(ctx, fnGetPassword)
+	defer end()
 
 	query := `SELECT password FROM users where login = ?`
 	row := s.db.QueryRowContext(ctx, query, login)
@@ -64,6 +146,8 @@ func (s *Storage) GetPassword(ctx context.Context, login string) (string, error)
 // SaveUser сохраняет пользователя и его захэшированный пароль.
 func (s *Storage) SaveUser(ctx context.Context, login string, password string, date_registered string) (int64, error) {
 	const fnSaveUser = "storage.sqlite.SaveUser"
+	ctx, end := s.begin(ctx, fnSaveUser)
+	defer end()
 
 	q := `
 		INSERT INTO users(login, password, date_registered) VALUES(?, ?, ?)
@@ -85,7 +169,9 @@ func (s *Storage) SaveUser(ctx context.Context, login string, password string, d
 
 // IsPostExist проверяет, существует ли пост.
 func (s *Storage) IsPostExist(ctx context.Context, id int) (bool, error) {
-	const fnIsPostExist = "storage.sqlite.IsUserExist"
+	const fnIsPostExist = "storage.sqlite.IsPostExist"
+	ctx, end := s.begin(ctx, fnIsPostExist)
+	defer end()
 
 	q := `SELECT COUNT(*) FROM posts WHERE id = ?`
 
@@ -101,14 +187,16 @@ func (s *Storage) IsPostExist(ctx context.Context, id int) (bool, error) {
 // GetPostCreator получает id создателя поста.
 func (s *Storage) GetPostCreator(ctx context.Context, id int) (string, error) {
 	const fnGetPostCreator = "storage.sqlite.GetPostCreator"
+	ctx, end := s.begin(ctx, fnGetPostCreator)
+	defer end()
 
 	q := `SELECT created_by FROM posts WHERE id = ?`
 
 	var created_by string
 
 	err := s.db.QueryRowContext(ctx, q, &id).Scan(&created_by)
-	if err == sql.ErrNoRows {
-		return "", err
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", storage.ErrPostNotFound
 	} else if err != nil {
 		return "", fmt.Errorf("%s: failed to check if post exists: %w", fnGetPostCreator, err)
 	}
@@ -116,16 +204,94 @@ func (s *Storage) GetPostCreator(ctx context.Context, id int) (string, error) {
 	return created_by, nil
 }
 
-// GetPosts получает посты пользователя.
-func (s *Storage) GetPosts(ctx context.Context, created_by string) (*types.UsersPosts, error) {
+// HasDuplicatePost проверяет, публиковал или обновлял ли автор пост с таким же
+// текстом начиная с since. Пост exclude не учитывается.
+func (s *Storage) HasDuplicatePost(ctx context.Context, author string, text string, since string, exclude int64) (bool, error) {
+	const fnHasDuplicatePost = "storage.sqlite.HasDuplicatePost"
+	ctx, end := s.begin(ctx, fnHasDuplicatePost)
+	defer end()
+
+	q := `
+		SELECT EXISTS (
+			SELECT 1 FROM posts
+			WHERE created_by = @author AND text = @text AND date_updated >= @since AND id != @exclude
+		)`
+
+	var found bool
+
+	err := s.db.QueryRowContext(ctx, q,
+		sql.Named("author", author),
+		sql.Named("text", text),
+		sql.Named("since", since),
+		sql.Named("exclude", exclude),
+	).Scan(&found)
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to check %s's posts: %w", fnHasDuplicatePost, author, err)
+	}
+
+	return found, nil
+}
+
+// visibleTo - условие, при котором пост виден пользователю @viewer (пустая строка - аноним).
+// Автор видит все свои посты, подписчики - посты для подписчиков, остальные - только публичные.
+// Посты скрыты в обе стороны, если один из пользователей заблокировал другого.
+// Скрытые модератором посты видят только автор и модераторы.
+// Простой репост виден, только если виден и оригинал.
+const visibleTo = `(` + visibleToPost + ` AND (posts.repost_of IS NULL OR posts.text != '' OR EXISTS (
+	SELECT 1 FROM posts AS original WHERE original.id = posts.repost_of AND ` + visibleToOriginal + `)))`
+
+const visibleToPost = `((
+	posts.created_by = @viewer
+	OR posts.visibility IN ('public', 'unlisted')
+	OR (posts.visibility = 'followers' AND EXISTS (
+		SELECT 1 FROM follows WHERE follows.follower = @viewer AND follows.followee = posts.created_by)))
+	AND ` + notBlocked + `
+	AND (NOT posts.hidden OR posts.created_by = @viewer OR ` + isModerator + `))`
+
+const visibleToOriginal = `((
+	original.created_by = @viewer
+	OR original.visibility IN ('public', 'unlisted')
+	OR (original.visibility = 'followers' AND EXISTS (
+		SELECT 1 FROM follows WHERE follows.follower = @viewer AND follows.followee = original.created_by)))
+	AND ` + notBlockedOriginal + `
+	AND (NOT original.hidden OR original.created_by = @viewer OR ` + isModerator + `))`
+
+// postColumns - колонки поста, которые читает scanPost.
+// Наличие закладки проверяется для пользователя @viewer.
+const postColumns = `posts.id, posts.created_by, posts.title, posts.text, posts.format, posts.visibility,
+	posts.revision, posts.likes, posts.repost_of, posts.hidden, posts.date_created, posts.date_updated,
+	(SELECT COUNT(*) FROM posts AS repost WHERE repost.repost_of = posts.id),
+	EXISTS(SELECT 1 FROM bookmarks WHERE bookmarks.post_id = posts.id AND bookmarks.login = @viewer)`
+
+func scanPost(row interface{ Scan(dest ...any) error }, post *types.Post) error {
+	var repostOf sql.NullInt64
+
+	err := row.Scan(&post.ID, &post.Created_by, &post.Title, &post.Text, &post.Format, &post.Visibility,
+		&post.Revision, &post.Likes, &repostOf, &post.Hidden, &post.Created_at, &post.Updated_at, &post.Reposts, &post.Bookmarked)
+	if err != nil {
+		return err
+	}
+
+	post.RepostOfID = repostOf.Int64
+
+	return nil
+}
+
+// GetPosts получает посты и репосты пользователя, которые видны viewer, начиная с последних.
+// Посты "по ссылке" в список попадают только для самого автора.
+// Репосты постов пользователей, которых viewer заглушил, пропускаются.
+func (s *Storage) GetPosts(ctx context.Context, created_by string, viewer string) (*types.UsersPosts, error) {
 	const fnGetPosts = "storage.sqlite.GetPosts"
+	ctx, end := s.begin(ctx, fnGetPosts)
+	defer end()
 
 	q := `
-		SELECT posts.id, created_by, title, text, likes, date_created, date_updated FROM posts 
-		WHERE created_by = ?
-		ORDER BY posts.date_created desc`
+		SELECT ` + postColumns + ` FROM posts 
+		WHERE posts.created_by = @created_by AND ` + visibleTo + ` AND ` + notMutedOriginal + `
+		AND (posts.visibility != 'unlisted' OR posts.created_by = @viewer)
+		ORDER BY posts.date_created desc, posts.id desc`
 
-	rows, err := s.db.QueryContext(ctx, q, created_by)
+	rows, err := s.db.QueryContext(ctx, q, sql.Named("created_by", created_by), sql.Named("viewer", viewer))
 	if err != nil {
 		return nil, fmt.Errorf("%s: failed to get %s's posts: %w", fnGetPosts, created_by, err)
 	}
@@ -134,32 +300,26 @@ func (s *Storage) GetPosts(ctx context.Context, created_by string) (*types.Users
 	posts := make([]types.Post, 0, 5)
 	for rows.Next() {
 		var post types.Post
-		if err := rows.Scan(&post.ID, &post.Created_by, &post.Title, &post.Text, &post.Likes, &post.Created_at, &post.Updated_at); err != nil {
+		if err := scanPost(rows, &post); err != nil {
 			return nil, fmt.Errorf("%s: failed to scan %s's posts: %w", fnGetPosts, created_by, err)
 		}
 
-		if post.Likes > 0 {
-			qq := `SELECT liked_by FROM reactions WHERE post_id = ?
-			ORDER BY id`
-
-			postLikers := make([]string, 0, 5)
+		posts = append(posts, post)
+	}
 
-			rr, err := s.db.QueryContext(ctx, qq, post.ID)
-			if err != nil {
-				return nil, fmt.Errorf("%s: failed to get post's likers: %w", fnGetPosts, err)
-			}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: failed to get %s's posts: %w", fnGetPosts, created_by, err)
+	}
+	rows.Close()
 
-			for rr.Next() {
-				var liker string
-				if err := rr.Scan(&liker); err != nil {
-					return nil, fmt.Errorf("%s: failed to scan post's likers: %w", fnGetPosts, err)
-				}
-				postLikers = append(postLikers, liker)
-			}
-			post.LikedBy = postLikers
+	for i := range posts {
+		if err := s.fillPost(ctx, &posts[i], viewer); err != nil {
+			return nil, fmt.Errorf("%s: %w", fnGetPosts, err)
 		}
 
-		posts = append(posts, post)
+		if err := s.fillRepost(ctx, &posts[i], viewer); err != nil {
+			return nil, fmt.Errorf("%s: %w", fnGetPosts, err)
+		}
 	}
 
 	UserPosts := types.UsersPosts{Posts: posts}
@@ -167,143 +327,435 @@ func (s *Storage) GetPosts(ctx context.Context, created_by string) (*types.Users
 	return &UserPosts, nil
 }
 
-// SavePost сохраняет пост пользователя.
-func (s *Storage) SavePost(ctx context.Context, created_by string, title string, text string, date_created string) (int64, error) {
-	const fnSavePost = "storage.sqlite.SavePost"
+// ListPosts получает страницу постов и репостов пользователя, как GetPosts, но без загрузок,
+// реакций и оригиналов репостов: их получают отдельно сразу для всей страницы.
+// Страница начинается после поста с датой after_date и id after_id (after_id = 0 - с последнего поста).
+// Дата может быть в том же виде, что и Created_at поста: она приводится к виду, в котором хранится.
+func (s *Storage) ListPosts(ctx context.Context, created_by string, viewer string, after_date string, after_id int64, limit int) ([]types.Post, error) {
+	const fnListPosts = "storage.sqlite.ListPosts"
+	ctx, end := s.begin(ctx, fnListPosts)
+	defer end()
 
 	q := `
-        INSERT INTO posts(created_by, title, text, date_created, date_updated) VALUES(?,?,?,?,?)`
+		SELECT ` + postColumns + ` FROM posts
+		WHERE posts.created_by = @created_by AND ` + visibleTo + ` AND ` + notMutedOriginal + `
+		AND (posts.visibility != 'unlisted' OR posts.created_by = @viewer)
+		AND (@after_id = 0 OR posts.date_created < datetime(@after_date)
+			OR (posts.date_created = datetime(@after_date) AND posts.id < @after_id))
+		ORDER BY posts.date_created desc, posts.id desc
+		LIMIT @limit`
+
+	rows, err := s.db.QueryContext(ctx, q,
+		sql.Named("created_by", created_by),
+		sql.Named("viewer", viewer),
+		sql.Named("after_date", after_date),
+		sql.Named("after_id", after_id),
+		sql.Named("limit", limit),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get %s's posts: %w", fnListPosts, created_by, err)
+	}
+	defer rows.Close()
 
-	data := []any{created_by, title, text, date_created, date_created}
+	posts := make([]types.Post, 0, limit)
+	for rows.Next() {
+		var post types.Post
+		if err := scanPost(rows, &post); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan %s's posts: %w", fnListPosts, created_by, err)
+		}
 
-	res, err := s.db.ExecContext(ctx, q, data...)
-	if err != nil {
-		return 0, fmt.Errorf("%s: failed to save post: %w", fnSavePost, err)
+		posts = append(posts, post)
 	}
 
-	id, err := res.LastInsertId()
-	if err != nil {
-		return 0, fmt.Errorf("%s: failed to get last insert post's id: %w", fnSavePost, err)
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: failed to get %s's posts: %w", fnListPosts, created_by, err)
 	}
 
-	return id, nil
+	return posts, nil
 }
 
-// UpdatePostTitle обновляет название поста.
-func (s *Storage) UpdatePostTitle(ctx context.Context, id int, title string, date_updated string) error {
-	const fnUpdatePostTitle = "storage.sqlite.UpdatePostTitle"
-
-	q := `UPDATE posts SET 
-				title = ?,
-				date_updated = ?
-			  WHERE id = ?
+// GetPostsByIDs получает посты с id из ids, которые видны viewer, без загрузок, реакций
+// и оригиналов репостов. Постов, которых нет или которые скрыты от viewer, в ответе нет.
+func (s *Storage) GetPostsByIDs(ctx context.Context, ids []int64, viewer string) (map[int64]types.Post, error) {
+	const fnGetPostsByIDs = "storage.sqlite.GetPostsByIDs"
+	ctx, end := s.begin(ctx, fnGetPostsByIDs)
+	defer end()
 
-					`
-	data := []any{title, date_updated, id}
+	q := `SELECT ` + postColumns + ` FROM posts
+		WHERE posts.id IN (SELECT value FROM json_each(@ids)) AND ` + visibleTo
 
-	_, err := s.db.ExecContext(ctx, q, data...)
+	rows, err := s.db.QueryContext(ctx, q, sql.Named("ids", jsonArray(ids)), sql.Named("viewer", viewer))
 	if err != nil {
-		return fmt.Errorf("%s: failed to save post: %w", fnUpdatePostTitle, err)
+		return nil, fmt.Errorf("%s: failed to get posts: %w", fnGetPostsByIDs, err)
 	}
+	defer rows.Close()
 
-	return nil
+	posts := make(map[int64]types.Post, len(ids))
+	for rows.Next() {
+		var post types.Post
+		if err := scanPost(rows, &post); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan posts: %w", fnGetPostsByIDs, err)
+		}
+
+		posts[post.ID] = post
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: failed to get posts: %w", fnGetPostsByIDs, err)
+	}
+
+	return posts, nil
 }
 
-// UpdatePostTest обновляет текст поста.
-func (s *Storage) UpdatePostText(ctx context.Context, id int, text string, date_updated string) error {
-	const fnUpdatePostText = "storage.sqlite.UpdatePostText"
+// jsonArray кодирует список для запросов с json_each: так список любой длины
+// передается одним параметром.
+func jsonArray[T any](values []T) string {
+	data, err := json.Marshal(values)
+	if err != nil || values == nil {
+		return "[]"
+	}
 
-	q := `UPDATE posts SET 
-				text = ?,
-				date_updated = ?
-			  WHERE id = ?
+	return string(data)
+}
 
-					`
-	data := []any{text, date_updated, id}
+// GetPost получает пост по id, если он виден viewer.
+// Если поста нет или он скрыт от viewer, возвращает storage.ErrPostNotFound.
+func (s *Storage) GetPost(ctx context.Context, id int, viewer string) (*types.Post, error) {
+	const fnGetPost = "storage.sqlite.GetPost"
+	ctx, end := s.begin(ctx, fnGetPost)
+	defer end()
 
-	_, err := s.db.ExecContext(ctx, q, data...)
-	if err != nil {
-		return fmt.Errorf("%s: failed to save post: %w", fnUpdatePostText, err)
+	post, err := s.getPost(ctx, int64(id), viewer)
+	if errors.Is(err, storage.ErrPostNotFound) {
+		return nil, err
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: %w", fnGetPost, err)
 	}
 
-	return nil
+	if err := s.fillRepost(ctx, post, viewer); err != nil {
+		return nil, fmt.Errorf("%s: %w", fnGetPost, err)
+	}
+
+	return post, nil
 }
 
-// RemovePost удаляет пост.
-func (s *Storage) RemovePost(ctx context.Context, id int) error {
-	const fnRemovePost = "storage.sqlite.RemovePost"
+// getPost получает пост по id, если он виден viewer, но не получает оригинал репоста.
+func (s *Storage) getPost(ctx context.Context, id int64, viewer string) (*types.Post, error) {
+	q := `SELECT ` + postColumns + ` FROM posts WHERE posts.id = @id AND ` + visibleTo
 
-	q := `DELETE FROM posts WHERE id = ?`
+	var post types.Post
 
-	_, err := s.db.ExecContext(ctx, q, id)
-	if err != nil {
-		return fmt.Errorf("%s: failed to delete post: %w", fnRemovePost, err)
+	err := scanPost(s.db.QueryRowContext(ctx, q, sql.Named("id", id), sql.Named("viewer", viewer)), &post)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrPostNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get post: %w", err)
 	}
 
-	return nil
+	if err := s.fillPost(ctx, &post, viewer); err != nil {
+		return nil, err
+	}
+
+	return &post, nil
 }
 
-// IsPostLikedByUser проверяет, лайкал ли пользователь пост.
-func (s *Storage) IsPostLikedByUser(ctx context.Context, id int, liked_by string) (bool, error) {
-	const fnIsPostLikedByUser = "storage.sqlite.IsPostLikedByUser"
+// CanViewPost проверяет, виден ли пост viewer.
+func (s *Storage) CanViewPost(ctx context.Context, id int, viewer string) (bool, error) {
+	const fnCanViewPost = "storage.sqlite.CanViewPost"
+	ctx, end := s.begin(ctx, fnCanViewPost)
+	defer end()
 
-	q := `SELECT COUNT(*) FROM reactions WHERE post_id = ? AND liked_by = ?`
+	q := `SELECT COUNT(*) FROM posts WHERE posts.id = @id AND ` + visibleTo
 
 	var count int
 
-	if err := s.db.QueryRowContext(ctx, q, &id, &liked_by).Scan(&count); err != nil {
-		return false, fmt.Errorf("%s: failed to check if user exists: %w", fnIsPostLikedByUser, err)
+	if err := s.db.QueryRowContext(ctx, q, sql.Named("id", id), sql.Named("viewer", viewer)).Scan(&count); err != nil {
+		return false, fmt.Errorf("%s: failed to check if post is visible: %w", fnCanViewPost, err)
 	}
 
 	return count > 0, nil
 }
 
-// LikePost увеличивает счетчик лайков поста и сохраняет лайк от пользователя.
-func (s *Storage) LikePost(ctx context.Context, id int, liked_by string) error {
-	const fnLikePost = "storage.sqlite.LikePost"
+// fillRepost дополняет репост оригинальным постом. Если оригинал удален или скрыт от viewer,
+// вместо него остается заглушка с id и признаком tombstone.
+// У самого оригинала его оригинал не получается, остается только repost_of_id.
+func (s *Storage) fillRepost(ctx context.Context, post *types.Post, viewer string) error {
+	if post.RepostOfID == 0 {
+		return nil
+	}
+
+	original, err := s.getPost(ctx, post.RepostOfID, viewer)
+	if errors.Is(err, storage.ErrPostNotFound) {
+		post.RepostOf = &types.Post{ID: post.RepostOfID, Tombstone: true}
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get original post: %w", err)
+	}
+
+	post.RepostOf = original
 
-	q := `UPDATE posts SET likes = likes + 1 WHERE id = ?`
+	return nil
+}
 
-	_, err := s.db.ExecContext(ctx, q, id)
+// fillPost дополняет пост прикрепленными загрузками, реакциями, списком лайкнувших
+// и реакциями viewer.
+func (s *Storage) fillPost(ctx context.Context, post *types.Post, viewer string) error {
+	media, err := s.getPostMedia(ctx, post.ID)
 	if err != nil {
-		return fmt.Errorf("%s: failed to increase post's likes: %w", fnLikePost, err)
+		return err
 	}
+	post.Media = media
 
-	q = `INSERT INTO reactions (post_id, liked_by) VALUES(?, ?)`
-
-	_, err = s.db.ExecContext(ctx, q, id, liked_by)
+	reactions, err := s.getPostReactions(ctx, post.ID)
 	if err != nil {
-		return fmt.Errorf("%s: failed to save %s's like on post: %w", fnLikePost, liked_by, err)
+		return err
+	}
+	post.Reactions = reactions
+
+	if viewer != "" && len(reactions) > 0 {
+		myReactions, err := s.getUserReactions(ctx, post.ID, viewer)
+		if err != nil {
+			return err
+		}
+		post.MyReactions = myReactions
+	}
+
+	if post.Likes > 0 {
+		q := `SELECT login FROM reactions WHERE post_id = ? AND kind = ?
+			ORDER BY id`
+
+		rows, err := s.db.QueryContext(ctx, q, post.ID, types.ReactionLike)
+		if err != nil {
+			return fmt.Errorf("failed to get post's likers: %w", err)
+		}
+		defer rows.Close()
+
+		postLikers := make([]string, 0, 5)
+		for rows.Next() {
+			var liker string
+			if err := rows.Scan(&liker); err != nil {
+				return fmt.Errorf("failed to scan post's likers: %w", err)
+			}
+			postLikers = append(postLikers, liker)
+		}
+		post.LikedBy = postLikers
+
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to get post's likers: %w", err)
+		}
 	}
 
 	return nil
+}
 
+// SavePost сохраняет пост пользователя.
+// Загрузки из media прикрепляются к посту в той же транзакции; если какая-то из них
+// не существует, принадлежит другому пользователю или уже прикреплена, пост не сохраняется.
+func (s *Storage) SavePost(ctx context.Context, created_by string, title string, text string, format string, visibility string, media []int64, date_created string) (int64, error) {
+	const fnSavePost = "storage.sqlite.SavePost"
+	ctx, end := s.begin(ctx, fnSavePost)
+	defer end()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to begin transaction: %w", fnSavePost, err)
+	}
+	defer tx.Rollback()
+
+	id, err := savePost(ctx, tx, created_by, title, text, format, visibility, media, date_created)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", fnSavePost, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: failed to commit transaction: %w", fnSavePost, err)
+	}
+
+	return id, nil
 }
 
-// UnlikePost уменьшает счетчик лайков поста и удаляет лайк от пользователя.
-func (s *Storage) UnlikePost(ctx context.Context, id int, liked_by string) error {
-	const fnUnlikePost = "storage.sqlite.UnlikePost"
+// savePost сохраняет пост, как SavePost, в транзакции tx.
+func savePost(ctx context.Context, tx *sql.Tx, created_by string, title string, text string, format string, visibility string, media []int64, date_created string) (int64, error) {
+	q := `
+        INSERT INTO posts(created_by, title, text, format, visibility, date_created, date_updated) VALUES(?,?,?,?,?,?,?)`
+
+	data := []any{created_by, title, text, format, visibility, date_created, date_created}
+
+	res, err := tx.ExecContext(ctx, q, data...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save post: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert post's id: %w", err)
+	}
+
+	q = `UPDATE media SET post_id = ? WHERE id = ? AND owner = ? AND post_id IS NULL AND ` + notAvatar
+
+	for _, mediaID := range media {
+		res, err := tx.ExecContext(ctx, q, id, mediaID, created_by)
+		if err != nil {
+			return 0, fmt.Errorf("failed to attach media %d: %w", mediaID, err)
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to attach media %d: %w", mediaID, err)
+		}
+
+		if n == 0 {
+			return 0, fmt.Errorf("%w: %d", storage.ErrMediaNotAvailable, mediaID)
+		}
+	}
+
+	return id, nil
+}
+
+// UpdatePost одним запросом обновляет непустые поля поста: название, текст, формат и видимость.
+func (s *Storage) UpdatePost(ctx context.Context, id int, title string, text string, format string, visibility string, date_updated string) error {
+	const fnUpdatePost = "storage.sqlite.UpdatePost"
+	ctx, end := s.begin(ctx, fnUpdatePost)
+	defer end()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", fnUpdatePost, err)
+	}
+	defer tx.Rollback()
+
+	if err := updatePost(ctx, tx, int64(id), title, text, format, visibility, date_updated); err != nil {
+		return fmt.Errorf("%s: %w", fnUpdatePost, err)
+	}
 
-	q := `UPDATE posts SET likes = likes - 1 WHERE id = ?`
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", fnUpdatePost, err)
+	}
 
-	_, err := s.db.ExecContext(ctx, q, id)
+	return nil
+}
+
+// updatePost обновляет пост, как UpdatePost, в транзакции tx.
+func updatePost(ctx context.Context, tx *sql.Tx, id int64, title string, text string, format string, visibility string, date_updated string) error {
+	// ревизия растет при изменении текста и формата, но не названия и видимости
+	q := `
+		UPDATE posts SET
+			title = CASE WHEN @title = '' THEN title ELSE @title END,
+			text = CASE WHEN @text = '' THEN text ELSE @text END,
+			format = CASE WHEN @format = '' THEN format ELSE @format END,
+			visibility = CASE WHEN @visibility = '' THEN visibility ELSE @visibility END,
+			revision = revision + (@text != '') + (@format != ''),
+			date_updated = @date_updated
+		WHERE id = @id
+	`
+
+	_, err := tx.ExecContext(ctx, q,
+		sql.Named("title", title),
+		sql.Named("text", text),
+		sql.Named("format", format),
+		sql.Named("visibility", visibility),
+		sql.Named("date_updated", date_updated),
+		sql.Named("id", id),
+	)
 	if err != nil {
-		return fmt.Errorf("%s: failed to decrease post's likes: %w", fnUnlikePost, err)
+		return fmt.Errorf("failed to update post: %w", err)
 	}
 
-	q = `DELETE FROM reactions WHERE post_id = ? AND liked_by = ?`
+	return nil
+}
+
+// RemovePost удаляет пост вместе с его лайками, закладками, упоминаниями, уведомлениями
+// и простыми репостами. Цитаты поста остаются и показывают вместо него заглушку. Прикрепленные
+// к посту загрузки открепляются и позже удаляются сборщиком мусора.
+func (s *Storage) RemovePost(ctx context.Context, id int) error {
+	const fnRemovePost = "storage.sqlite.RemovePost"
+	ctx, end := s.begin(ctx, fnRemovePost)
+	defer end()
 
-	_, err = s.db.ExecContext(ctx, q, id, liked_by)
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("%s: failed to delete %s's like on post: %w", fnUnlikePost, liked_by, err)
+		return fmt.Errorf("%s: failed to begin transaction: %w", fnRemovePost, err)
+	}
+	defer tx.Rollback()
+
+	if err := removePost(ctx, tx, int64(id)); err != nil {
+		return fmt.Errorf("%s: %w", fnRemovePost, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", fnRemovePost, err)
 	}
 
 	return nil
 }
 
-// Init создает таблицы и индексы, если они еще не были созданы.
+// removePost удаляет пост, как RemovePost, в транзакции tx.
+func removePost(ctx context.Context, tx *sql.Tx, id int64) error {
+	// удаляемые посты: сам пост и его простые репосты
+	const removed = `(SELECT id FROM posts WHERE id = @id OR (repost_of = @id AND text = ''))`
+
+	q := `UPDATE media SET post_id = NULL WHERE post_id IN ` + removed
+
+	if _, err := tx.ExecContext(ctx, q, sql.Named("id", id)); err != nil {
+		return fmt.Errorf("failed to detach post's media: %w", err)
+	}
+
+	// внешние ключи выключены, поэтому лайки, закладки, упоминания
+	// и уведомления поста удаляем сами
+	q = `DELETE FROM reactions WHERE post_id IN ` + removed
+
+	if _, err := tx.ExecContext(ctx, q, sql.Named("id", id)); err != nil {
+		return fmt.Errorf("failed to delete post's likes: %w", err)
+	}
+
+	q = `DELETE FROM bookmarks WHERE post_id IN ` + removed
+
+	if _, err := tx.ExecContext(ctx, q, sql.Named("id", id)); err != nil {
+		return fmt.Errorf("failed to delete post's bookmarks: %w", err)
+	}
+
+	q = `DELETE FROM mentions WHERE post_id IN ` + removed
+
+	if _, err := tx.ExecContext(ctx, q, sql.Named("id", id)); err != nil {
+		return fmt.Errorf("failed to delete post's mentions: %w", err)
+	}
+
+	q = `DELETE FROM notifications WHERE post_id IN ` + removed
+
+	if _, err := tx.ExecContext(ctx, q, sql.Named("id", id)); err != nil {
+		return fmt.Errorf("failed to delete post's notifications: %w", err)
+	}
+
+	q = `DELETE FROM posts WHERE id IN ` + removed
+
+	if _, err := tx.ExecContext(ctx, q, sql.Named("id", id)); err != nil {
+		return fmt.Errorf("failed to delete post: %w", err)
+	}
+
+	return nil
+}
+
+// IsPostLikedByUser проверяет, лайкал ли пользователь пост.
+func (s *Storage) IsPostLikedByUser(ctx context.Context, id int, liked_by string) (bool, error) {
+	return s.IsPostReactedByUser(ctx, id, liked_by, types.ReactionLike)
+}
+
+// LikePost сохраняет лайк от пользователя.
+func (s *Storage) LikePost(ctx context.Context, id int, liked_by string) error {
+	return s.AddReaction(ctx, id, liked_by, types.ReactionLike)
+}
+
+// UnlikePost удаляет лайк от пользователя.
+func (s *Storage) UnlikePost(ctx context.Context, id int, liked_by string) error {
+	return s.RemoveReaction(ctx, id, liked_by, types.ReactionLike)
+}
+
+// Init создает таблицы и индексы, если они еще не были созданы,
+// и применяет миграции схемы.
 func (s *Storage) Init(ctx context.Context) error {
 	const fnInit = "storage.sqlite.Init"
+	ctx, end := s.begin(ctx, fnInit)
+	defer end()
 
 	q := `
 		CREATE TABLE IF NOT EXISTS users(
@@ -337,5 +789,9 @@ func (s *Storage) Init(ctx context.Context) error {
 		return fmt.Errorf("%s: failed to init tables: %w", fnInit, err)
 	}
 
+	if err := s.migrate(ctx); err != nil {
+		return fmt.Errorf("%s: %w", fnInit, err)
+	}
+
 	return nil
 }
@@ -0,0 +1,80 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/solumD/go-blog-api/internal/storage"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestStorageSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	s := newStorage(t)
+	s.SetTracerProvider(tp)
+
+	// спан запроса, как его открывает middleware трассировки
+	ctx, request := tp.Tracer("test").Start(context.Background(), "GET /v1/users/{login}")
+
+	_, err := s.IsUserExist(ctx, "test_user")
+	require.NoError(t, err)
+
+	request.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	call := spans[0]
+	require.Equal(t, "storage.sqlite.IsUserExist", call.Name)
+	require.Equal(t, trace.SpanKindClient, call.SpanKind)
+	require.Contains(t, call.Attributes, semconv.DBSystemSqlite)
+
+	// вызов хранилища - дочерний спан спана запроса
+	require.Equal(t, request.SpanContext().TraceID(), call.SpanContext.TraceID())
+	require.Equal(t, request.SpanContext().SpanID(), call.Parent.SpanID())
+}
+
+func TestUpdatePost(t *testing.T) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	id, err := s.SavePost(ctx, "author_user", "Very Cool Title", "Very Cool Text", "plain", "public", nil, date)
+	require.NoError(t, err)
+
+	// пустые поля не меняются
+	require.NoError(t, s.UpdatePost(ctx, int(id), "New Title", "New Text", "", "private", "2026-10-19 13:00:00"))
+
+	post, err := s.GetPost(ctx, int(id), "author_user")
+	require.NoError(t, err)
+
+	require.Equal(t, "New Title", post.Title)
+	require.Equal(t, "New Text", post.Text)
+	require.Equal(t, "plain", post.Format)
+	require.Equal(t, "private", post.Visibility)
+	// изменился текст, но не формат: ревизия выросла на один
+	require.Equal(t, 2, post.Revision)
+	require.Equal(t, "2026-10-19T13:00:00Z", post.Updated_at)
+}
+
+func TestLikePostTwice(t *testing.T) {
+	ctx := context.Background()
+	s := newStorage(t)
+
+	id, err := s.SavePost(ctx, "author_user", "Very Cool Title", "Very Cool Text", "plain", "public", nil, date)
+	require.NoError(t, err)
+
+	require.NoError(t, s.LikePost(ctx, int(id), "test_user"))
+	require.ErrorIs(t, s.LikePost(ctx, int(id), "test_user"), storage.ErrAlreadyReacted)
+
+	// повторный лайк не увеличивает счетчик
+	post, err := s.GetPost(ctx, int(id), "test_user")
+	require.NoError(t, err)
+	require.Equal(t, 1, post.Likes)
+}
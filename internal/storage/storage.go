@@ -0,0 +1,26 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Виды ошибок хранилища. Конкретные ошибки оборачивают их, поэтому
+// обработчики выбирают HTTP-статус по виду, а не по каждой ошибке.
+var (
+	ErrNotFound  = errors.New("not found")
+	ErrConflict  = errors.New("conflict")
+	ErrForbidden = errors.New("forbidden")
+)
+
+var (
+	ErrPostNotFound         = fmt.Errorf("post %w", ErrNotFound)
+	ErrRepostNotFound       = fmt.Errorf("repost %w", ErrNotFound)
+	ErrMediaNotFound        = fmt.Errorf("media %w", ErrNotFound)
+	ErrMediaNotAvailable    = fmt.Errorf("media doesn't exist or can't be attached: %w", ErrConflict)
+	ErrConversationNotFound = fmt.Errorf("conversation %w", ErrNotFound)
+	ErrUserNotFound         = fmt.Errorf("user %w", ErrNotFound)
+	ErrAlreadyReported      = fmt.Errorf("post already reported by user: %w", ErrConflict)
+	ErrAlreadyReacted       = fmt.Errorf("post already has user's reaction: %w", ErrConflict)
+	ErrReportsNotFound      = fmt.Errorf("open reports %w", ErrNotFound)
+)
@@ -0,0 +1,6 @@
+package types
+
+type BookmarkCollection struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
@@ -0,0 +1,28 @@
+package types
+
+// Conversation - личная переписка двух пользователей или небольшой группы.
+type Conversation struct {
+	ID          int64                `json:"id"`
+	Created_by  string               `json:"created_by"`
+	Members     []ConversationMember `json:"members"`
+	LastMessage *Message             `json:"last_message,omitempty"`
+	Unread      int                  `json:"unread"`
+	Created_at  string               `json:"created_at"`
+	Updated_at  string               `json:"updated_at"`
+}
+
+// ConversationMember - участник переписки.
+// LastReadID - id последнего прочитанного участником сообщения, по нему видно, кто что прочитал.
+type ConversationMember struct {
+	Login      string `json:"login"`
+	LastReadID int64  `json:"last_read_id"`
+}
+
+// Message - сообщение в переписке.
+type Message struct {
+	ID             int64  `json:"id"`
+	ConversationID int64  `json:"conversation_id"`
+	Sender         string `json:"sender"`
+	Text           string `json:"text"`
+	Created_at     string `json:"created_at"`
+}
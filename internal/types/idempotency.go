@@ -0,0 +1,11 @@
+package types
+
+// IdempotencyRecord - первый запрос с ключом идемпотентности и ответ на него.
+// Пока первый запрос выполняется, Status равен 0.
+type IdempotencyRecord struct {
+	// хэш метода, пути и тела запроса
+	Fingerprint string
+	Status      int
+	ContentType string
+	Body        []byte
+}
@@ -0,0 +1,17 @@
+package types
+
+type Media struct {
+	ID            int64  `json:"id"`
+	Owner         string `json:"owner"`
+	PostID        int64  `json:"-"`
+	ContentType   string `json:"content_type"`
+	Size          int64  `json:"size"`
+	Width         int    `json:"width"`
+	Height        int    `json:"height"`
+	BlobKey       string `json:"-"`
+	ThumbnailKey  string `json:"-"`
+	ThumbnailType string `json:"-"`
+	Created_at    string `json:"created_at"`
+	// загрузка - аватар пользователя, ее видят все
+	Avatar bool `json:"-"`
+}
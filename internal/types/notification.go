@@ -0,0 +1,28 @@
+package types
+
+// Типы уведомлений
+const (
+	// пост лайкнули
+	NotificationLike = "like"
+	// на пост поставили реакцию, кроме лайка
+	NotificationReaction = "reaction"
+	// на пользователя подписались
+	NotificationFollow = "follow"
+	// пользователя упомянули в посте
+	NotificationMention = "mention"
+)
+
+// NotificationTypes - все типы уведомлений, которые можно выключить в настройках
+var NotificationTypes = []string{NotificationLike, NotificationReaction, NotificationFollow, NotificationMention}
+
+// Notification - группа уведомлений одного типа об одном посте,
+// например, "alice and 12 others liked your post".
+type Notification struct {
+	Type       string `json:"type"`
+	PostID     int64  `json:"post_id,omitempty"`
+	Actor      string `json:"actor"`
+	Others     int    `json:"others"`
+	Text       string `json:"text"`
+	Read       bool   `json:"read"`
+	Updated_at string `json:"updated_at"`
+}
@@ -1,16 +1,98 @@
 package types
 
+import "encoding/json"
+
+// Форматы текста поста
+const (
+	FormatPlain    = "plain"
+	FormatMarkdown = "markdown"
+)
+
+// Видимость поста
+const (
+	// виден всем
+	VisibilityPublic = "public"
+	// виден подписчикам автора
+	VisibilityFollowers = "followers"
+	// виден всем по ссылке, но не попадает в список постов автора
+	VisibilityUnlisted = "unlisted"
+	// виден только автору
+	VisibilityPrivate = "private"
+)
+
+// ReactionLike - вид реакции, который используют /post/like и /post/unlike
+const ReactionLike = "like"
+
+// Reaction - реакция пользователя на пост.
+type Reaction struct {
+	ID     int64
+	PostID int64
+	Login  string
+	Kind   string
+}
+
 type Post struct {
-	ID         int64    `json:"id"`
-	Created_by string   `json:"created_by"`
-	Title      string   `json:"title"`
-	Text       string   `json:"text"`
-	Likes      int      `json:"likes"`
-	LikedBy    []string `json:"liked_by,omitempty"`
-	Created_at string   `json:"created_at"`
-	Updated_at string   `json:"updated_at"`
+	ID          int64          `json:"id"`
+	Created_by  string         `json:"created_by"`
+	Title       string         `json:"title"`
+	Text        string         `json:"text"`
+	Format      string         `json:"format"`
+	Visibility  string         `json:"visibility"`
+	HTML        string         `json:"html"`
+	Revision    int            `json:"-"`
+	Likes       int            `json:"likes"`
+	LikedBy     []string       `json:"liked_by,omitempty"`
+	Reactions   map[string]int `json:"reactions,omitempty"`
+	MyReactions []string       `json:"my_reactions,omitempty"`
+	Media       []Media        `json:"media,omitempty"`
+	Bookmarked  bool           `json:"bookmarked,omitempty"`
+	Reposts     int            `json:"reposts"`
+	RepostOfID  int64          `json:"repost_of_id,omitempty"`
+	RepostOf    *Post          `json:"repost_of,omitempty"`
+	Tombstone   bool           `json:"tombstone,omitempty"`
+	Hidden      bool           `json:"hidden,omitempty"`
+	Created_at  string         `json:"created_at"`
+	Updated_at  string         `json:"updated_at"`
+}
+
+// IsPlainRepost проверяет, является ли пост простым репостом, то есть репостом без своего текста.
+func (p *Post) IsPlainRepost() bool {
+	return p.RepostOfID != 0 && p.Text == ""
+}
+
+// MarshalJSON оставляет у заглушки удаленного или скрытого оригинала репоста только id.
+func (p Post) MarshalJSON() ([]byte, error) {
+	if p.Tombstone {
+		return json.Marshal(struct {
+			ID        int64 `json:"id"`
+			Tombstone bool  `json:"tombstone"`
+		}{p.ID, true})
+	}
+
+	type post Post
+
+	return json.Marshal(post(p))
 }
 
 type UsersPosts struct {
 	Posts []Post `json:"posts,omitempty"`
 }
+
+// Операции пакетного изменения постов
+const (
+	OperationCreate = "create"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+)
+
+// PostOperation - одна операция пакетного изменения постов пользователя.
+// Пустые поля при изменении поста не меняются.
+type PostOperation struct {
+	Op         string
+	ID         int64
+	Title      string
+	Text       string
+	Format     string
+	Visibility string
+	Media      []int64
+}
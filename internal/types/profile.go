@@ -0,0 +1,16 @@
+package types
+
+// Profile - публичный профиль пользователя со статистикой.
+// Posts и Likes считаются только по постам, которые видит запросивший профиль пользователь.
+type Profile struct {
+	Login       string `json:"login"`
+	DisplayName string `json:"display_name"`
+	Bio         string `json:"bio"`
+	Website     string `json:"website"`
+	AvatarID    int64  `json:"avatar_id,omitempty"`
+	Joined_at   string `json:"joined_at"`
+	Posts       int    `json:"posts"`
+	Likes       int    `json:"likes"`
+	Followers   int    `json:"followers"`
+	Following   int    `json:"following"`
+}
@@ -0,0 +1,55 @@
+package types
+
+// Причины жалоб на посты
+const (
+	ReportSpam           = "spam"
+	ReportHarassment     = "harassment"
+	ReportHate           = "hate"
+	ReportViolence       = "violence"
+	ReportNudity         = "nudity"
+	ReportMisinformation = "misinformation"
+	ReportOther          = "other"
+)
+
+// ReportReasons - все причины, по которым можно пожаловаться на пост
+var ReportReasons = []string{
+	ReportSpam, ReportHarassment, ReportHate, ReportViolence, ReportNudity, ReportMisinformation, ReportOther,
+}
+
+// Решения модератора по жалобам
+const (
+	// жалобы отклонены, с постом ничего не происходит
+	ModerationDismiss = "dismiss"
+	// пост скрыт ото всех, кроме автора и модераторов
+	ModerationHide = "hide"
+	// пост удален
+	ModerationDelete = "delete"
+	// пост скрыт, автор заблокирован и не может войти
+	ModerationSuspend = "suspend"
+)
+
+// ModerationActions - все решения, которые может принять модератор
+var ModerationActions = []string{ModerationDismiss, ModerationHide, ModerationDelete, ModerationSuspend}
+
+// ReportGroup - открытые жалобы на один пост в очереди модерации.
+type ReportGroup struct {
+	PostID         int64          `json:"post_id"`
+	Author         string         `json:"author"`
+	Reports        int            `json:"reports"`
+	Reasons        map[string]int `json:"reasons"`
+	Notes          []string       `json:"notes,omitempty"`
+	First_reported string         `json:"first_reported_at"`
+	Last_reported  string         `json:"last_reported_at"`
+}
+
+// ModerationAction - запись о решении модератора.
+type ModerationAction struct {
+	ID         int64  `json:"id"`
+	PostID     int64  `json:"post_id"`
+	Author     string `json:"author"`
+	Moderator  string `json:"moderator"`
+	Action     string `json:"action"`
+	Note       string `json:"note"`
+	Reports    int    `json:"reports"`
+	Created_at string `json:"created_at"`
+}
@@ -17,6 +17,9 @@ const (
 	host = "localhost:8081"
 )
 
+// problemJSON - ответы с ошибкой приходят в формате application/problem+json
+var problemJSON = httpexpect.ContentOpts{MediaType: "application/problem+json"}
+
 func TestRegisterLoginCreateDelete(t *testing.T) {
 	u := url.URL{
 		Scheme: "http",
@@ -67,6 +70,59 @@ func TestRegisterLoginCreateDelete(t *testing.T) {
 
 }
 
+// TestV1RegisterLoginCreateDelete - тот же сценарий через маршруты /v1.
+func TestV1RegisterLoginCreateDelete(t *testing.T) {
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+	}
+
+	e := httpexpect.Default(t, u.String())
+
+	l := gofakeit.Username()
+	p := gofakeit.Password(true, true, true, false, false, 10)
+
+	e.POST("/v1/auth/register").
+		WithJSON(register.Request{
+			Login:    l,
+			Password: p,
+		}).
+		Expect().Status(http.StatusOK)
+
+	r := e.POST("/v1/auth/login").
+		WithJSON(login.Request{
+			Login:    l,
+			Password: p,
+		}).
+		Expect().Status(http.StatusOK).
+		JSON().Object().
+		ContainsKey("token")
+
+	token := r.Value("token").String().Raw()
+
+	rr := e.POST("/v1/posts").
+		WithJSON(save.Request{
+			Title: gofakeit.Paragraph(1, 1, 3, ""),
+			Text:  gofakeit.Paragraph(1, 5, 40, ""),
+		}).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().Status(http.StatusOK).
+		JSON().Object().
+		ContainsKey("id")
+
+	postID := int(rr.Value("id").Number().Raw())
+
+	e.DELETE("/v1/posts/{id}", postID).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().Status(http.StatusOK)
+
+	e.DELETE("/v1/posts/{id}", postID).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().Status(http.StatusNotFound).
+		JSON(problemJSON).Object().
+		Value("code").String().IsEqual("post_not_found")
+}
+
 func TestRegisterUserAlreadyExists(t *testing.T) {
 	u := url.URL{
 		Scheme: "http",
@@ -90,11 +146,25 @@ func TestRegisterUserAlreadyExists(t *testing.T) {
 			Login:    l,
 			Password: p,
 		}).
-		Expect().Status(http.StatusBadRequest).
+		Expect().Status(http.StatusConflict).
+		JSON(problemJSON).Object().
+		ContainsKey("detail")
+
+	expectedError := "user already exists"
+	r.Value("detail").String().IsEqual(expectedError)
+	r.Value("code").String().IsEqual("user_already_exists")
+
+	// прежний формат ошибок
+	r = e.POST("/auth/register").
+		WithJSON(register.Request{
+			Login:    l,
+			Password: p,
+		}).
+		WithHeader("Accept", "application/json").
+		Expect().Status(http.StatusConflict).
 		JSON().Object().
 		ContainsKey("error")
 
-	expectedError := "user already exists"
 	r.Value("error").String().IsEqual(expectedError)
 }
 
@@ -147,10 +217,11 @@ func TestRegisterInvalidLoginOrPassword(t *testing.T) {
 					Password: tc.password,
 				}).
 				Expect().Status(http.StatusBadRequest).
-				JSON().Object().
-				ContainsKey("error")
+				JSON(problemJSON).Object().
+				ContainsKey("detail")
 
-			r.Value("error").String().IsEqual(tc.error)
+			r.Value("detail").String().IsEqual(tc.error)
+			r.Value("code").String().IsEqual("validation_failed")
 		})
 	}
 }